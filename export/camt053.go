@@ -0,0 +1,242 @@
+package export
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/anoideaopen/token/model"
+)
+
+// Camt053Namespace is the XML namespace of the produced ISO 20022 statements.
+const Camt053Namespace = "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"
+
+// ISO 20022 codes used in the statements.
+const (
+	camtCredit         = "CRDT"
+	camtDebit          = "DBIT"
+	camtOpeningBooked  = "OPBD"
+	camtClosingBooked  = "CLBD"
+	camtStatusBooked   = "BOOK"
+	camtDateTimeLayout = "2006-01-02T15:04:05Z"
+)
+
+// camtDocument and the nested types describe the subset of the camt.053.001.02
+// message used by the statements.
+type (
+	camtDocument struct {
+		XMLName xml.Name      `xml:"Document"`
+		Xmlns   string        `xml:"xmlns,attr"`
+		Stmt    camtBkToCstmr `xml:"BkToCstmrStmt"`
+	}
+
+	camtBkToCstmr struct {
+		GrpHdr camtGrpHdr `xml:"GrpHdr"`
+		Stmt   camtStmt   `xml:"Stmt"`
+	}
+
+	camtGrpHdr struct {
+		MsgID    string `xml:"MsgId"`
+		CreDtTm  string `xml:"CreDtTm"`
+		MsgPgntn struct {
+			PgNb      int  `xml:"PgNb"`
+			LastPgInd bool `xml:"LastPgInd"`
+		} `xml:"MsgPgntn"`
+	}
+
+	camtStmt struct {
+		ID      string     `xml:"Id"`
+		CreDtTm string     `xml:"CreDtTm"`
+		FrToDt  camtFrToDt `xml:"FrToDt"`
+		Acct    camtAcct   `xml:"Acct"`
+		Bal     []camtBal  `xml:"Bal"`
+		Ntry    []camtNtry `xml:"Ntry"`
+	}
+
+	camtFrToDt struct {
+		FrDtTm string `xml:"FrDtTm"`
+		ToDtTm string `xml:"ToDtTm"`
+	}
+
+	camtAcct struct {
+		ID  camtAcctID `xml:"Id"`
+		Ccy string     `xml:"Ccy"`
+	}
+
+	camtAcctID struct {
+		Othr struct {
+			ID string `xml:"Id"`
+		} `xml:"Othr"`
+	}
+
+	camtAmt struct {
+		Ccy   string `xml:"Ccy,attr"`
+		Value string `xml:",chardata"`
+	}
+
+	camtCode struct {
+		Cd string `xml:"Cd"`
+	}
+
+	camtDtTm struct {
+		DtTm string `xml:"DtTm"`
+	}
+
+	camtBal struct {
+		Tp struct {
+			CdOrPrtry camtCode `xml:"CdOrPrtry"`
+		} `xml:"Tp"`
+		Amt       camtAmt  `xml:"Amt"`
+		CdtDbtInd string   `xml:"CdtDbtInd"`
+		Dt        camtDtTm `xml:"Dt"`
+	}
+
+	camtNtry struct {
+		Amt         camtAmt  `xml:"Amt"`
+		CdtDbtInd   string   `xml:"CdtDbtInd"`
+		Sts         string   `xml:"Sts"`
+		BookgDt     camtDtTm `xml:"BookgDt"`
+		ValDt       camtDtTm `xml:"ValDt"`
+		AcctSvcrRef string   `xml:"AcctSvcrRef,omitempty"`
+		BkTxCd      struct {
+			Prtry struct {
+				Cd string `xml:"Cd"`
+			} `xml:"Prtry"`
+		} `xml:"BkTxCd"`
+		NtryDtls struct {
+			TxDtls camtTxDtls `xml:"TxDtls"`
+		} `xml:"NtryDtls"`
+	}
+
+	camtTxDtls struct {
+		Refs struct {
+			AcctSvcrRef string `xml:"AcctSvcrRef,omitempty"`
+		} `xml:"Refs"`
+		RltdPties *camtRltdPties `xml:"RltdPties,omitempty"`
+		RmtInf    *camtRmtInf    `xml:"RmtInf,omitempty"`
+	}
+
+	camtRltdPties struct {
+		DbtrAcct *camtAcctRef `xml:"DbtrAcct,omitempty"`
+		CdtrAcct *camtAcctRef `xml:"CdtrAcct,omitempty"`
+	}
+
+	camtAcctRef struct {
+		ID camtAcctID `xml:"Id"`
+	}
+
+	camtRmtInf struct {
+		Ustrd string `xml:"Ustrd"`
+	}
+)
+
+// WriteCamt053 writes the statement as an ISO 20022 camt.053.001.02 bank-to-customer
+// statement message created at the moment. The account of the statement is
+// identified by the address and the account type, and every balance update becomes a
+// booked entry referencing the transaction.
+func WriteCamt053(w io.Writer, st *Statement, created time.Time) error {
+	doc := camtDocument{Xmlns: Camt053Namespace}
+
+	hdr := &doc.Stmt.GrpHdr
+	hdr.MsgID = st.ID()
+	hdr.CreDtTm = created.UTC().Format(camtDateTimeLayout)
+	hdr.MsgPgntn.PgNb = 1
+	hdr.MsgPgntn.LastPgInd = true
+
+	stmt := &doc.Stmt.Stmt
+	stmt.ID = st.ID()
+	stmt.CreDtTm = hdr.CreDtTm
+	stmt.FrToDt = camtFrToDt{
+		FrDtTm: st.From.UTC().Format(camtDateTimeLayout),
+		ToDtTm: st.To.UTC().Format(camtDateTimeLayout),
+	}
+	stmt.Acct.ID = camtAccount(st.Address, st.Account)
+	stmt.Acct.Ccy = string(st.Currency)
+
+	stmt.Bal = []camtBal{
+		camtBalance(camtOpeningBooked, st.Currency, st.Opening, st.From),
+		camtBalance(camtClosingBooked, st.Currency, st.Closing, st.To),
+	}
+
+	for _, u := range st.Entries {
+		stmt.Ntry = append(stmt.Ntry, camtEntry(u))
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+
+	return err
+}
+
+func camtAccount(addr model.Address, acc model.Account) (id camtAcctID) {
+	id.Othr.ID = string(addr) + "/" + strconv.Itoa(int(acc))
+	return id
+}
+
+func camtBalance(code string, curr model.Currency, a *model.Amount, at time.Time) camtBal {
+	var b camtBal
+	b.Tp.CdOrPrtry.Cd = code
+	b.Amt, b.CdtDbtInd = camtAmount(curr, a)
+	b.Dt.DtTm = at.UTC().Format(camtDateTimeLayout)
+
+	return b
+}
+
+// camtAmount returns the absolute value of the amount and its credit-debit indicator.
+func camtAmount(curr model.Currency, a *model.Amount) (camtAmt, string) {
+	ind := camtCredit
+	if a.Sign() < 0 {
+		ind = camtDebit
+		v := a.Int()
+		a = model.NewAmount(v.Neg(v), a.Precision)
+	}
+
+	return camtAmt{Ccy: string(curr), Value: a.String()}, ind
+}
+
+func camtEntry(u model.BalanceUpdate) camtNtry {
+	var (
+		n  camtNtry
+		ts = u.Timestamp.UTC().Format(camtDateTimeLayout)
+	)
+
+	n.Amt, _ = camtAmount(u.Currency, u.ValueDelta)
+	n.CdtDbtInd = camtCredit
+	if direction(u) == model.DirectionDebit {
+		n.CdtDbtInd = camtDebit
+	}
+
+	n.Sts = camtStatusBooked
+	n.BookgDt.DtTm = ts
+	n.ValDt.DtTm = ts
+	n.AcctSvcrRef = u.TxID
+	n.BkTxCd.Prtry.Cd = formatOperation(u.Operation)
+	n.NtryDtls.TxDtls.Refs.AcctSvcrRef = u.TxID
+
+	if u.Counterparty != "" {
+		// the counterparty is the creditor of debits and the debtor of credits
+		ref := &camtAcctRef{ID: camtAccount(u.Counterparty, u.CounterpartyAccount)}
+		if n.CdtDbtInd == camtDebit {
+			n.NtryDtls.TxDtls.RltdPties = &camtRltdPties{CdtrAcct: ref}
+		} else {
+			n.NtryDtls.TxDtls.RltdPties = &camtRltdPties{DbtrAcct: ref}
+		}
+	}
+
+	if u.Memo != "" {
+		n.NtryDtls.TxDtls.RmtInf = &camtRmtInf{Ustrd: u.Memo}
+	}
+
+	return n
+}
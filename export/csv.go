@@ -0,0 +1,100 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/anoideaopen/token/model"
+)
+
+// csvHeader contains the column names of the CSV journal.
+var csvHeader = []string{
+	"timestamp",
+	"tx_id",
+	"address",
+	"account",
+	"currency",
+	"operation",
+	"direction",
+	"amount",
+	"old_value",
+	"new_value",
+	"counterparty",
+	"counterparty_account",
+	"memo",
+}
+
+// WriteCSV writes the balance updates as a CSV journal with a header row. Amounts are
+// written as decimal numbers, and the values unknown for the update are left empty.
+func WriteCSV(w io.Writer, updates model.BalancesUpdate) error {
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, u := range updates {
+		var counterpartyAccount string
+		if u.CounterpartyAccount != 0 {
+			counterpartyAccount = strconv.Itoa(int(u.CounterpartyAccount))
+		}
+
+		if err := cw.Write([]string{
+			formatTime(u.Timestamp),
+			u.TxID,
+			string(u.Address),
+			strconv.Itoa(int(u.Account)),
+			string(u.Currency),
+			formatOperation(u.Operation),
+			string(direction(u)),
+			formatAmount(u.ValueDelta),
+			formatAmount(u.OldValue),
+			formatAmount(u.NewValue),
+			string(u.Counterparty),
+			counterpartyAccount,
+			u.Memo,
+		}); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// direction returns the direction of the update, derived from its values for the
+// records without the direction.
+func direction(u model.BalanceUpdate) model.Direction {
+	if u.SignedDelta().Sign() < 0 {
+		return model.DirectionDebit
+	}
+
+	return model.DirectionCredit
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatOperation(op model.Operation) string {
+	if op == 0 {
+		return ""
+	}
+
+	return op.String()
+}
+
+func formatAmount(a *model.Amount) string {
+	if a == nil {
+		return ""
+	}
+
+	return a.String()
+}
@@ -0,0 +1,241 @@
+// Package export converts the accounting records of the notification database into
+// the formats used by the finance systems: CSV journals and ISO 20022 camt.053
+// bank-to-customer statements.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// ErrExportRepository represents a generic error related to the repository operations.
+var ErrExportRepository = errors.New("export repository error")
+
+// Period is a calendar period the exported journals are grouped by.
+type Period string
+
+// Constants for the periods.
+const (
+	PeriodDaily   Period = "D"
+	PeriodMonthly Period = "M"
+	PeriodYearly  Period = "Y"
+)
+
+// Key returns a key of the calendar period (in UTC) the moment belongs to.
+// example: "D20240131", "M202401" or "Y2024"
+func (p Period) Key(t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case PeriodDaily:
+		return string(p) + t.Format("20060102")
+	case PeriodMonthly:
+		return string(p) + t.Format("200601")
+	case PeriodYearly:
+		return string(p) + t.Format("2006")
+	default:
+		return string(p)
+	}
+}
+
+// Exporter reads balance updates of the model.Notification[model.BalancesUpdate]
+// records from the notification database. The journals are read from the records
+// of the exported types, while the statements are read from the history of the
+// balance updates of the address.
+type Exporter struct {
+	repository.Notification
+
+	// Types are the notification types with model.BalancesUpdate bodies to export.
+	// If it is empty, model.NotificationTypeBalancesUpdate records are exported.
+	// The types must not include the same balance updates twice.
+	Types []string
+}
+
+// Statement is an account statement of the balance of an address for a period.
+type Statement struct {
+	Address  model.Address
+	Account  model.Account
+	Currency model.Currency
+	From     time.Time     // Beginning of the period, inclusive.
+	To       time.Time     // End of the period, exclusive.
+	Opening  *model.Amount // Balance at the beginning of the period.
+	Closing  *model.Amount // Balance at the end of the period.
+	Entries  model.BalancesUpdate
+}
+
+// ID returns the identifier of the statement derived from the balance and the period.
+func (s *Statement) ID() string {
+	return fmt.Sprintf("%s-%d-%s-%s", s.Address, s.Account, s.Currency, s.From.UTC().Format("20060102T150405"))
+}
+
+// Journal retrieves the balance updates made within the time range in the order of
+// their timestamps. A zero bound does not limit the range.
+func (e *Exporter) Journal(ctx context.Context, from, to time.Time) (model.BalancesUpdate, error) {
+	return e.updates(ctx, model.NotificationQuery{From: from, To: to})
+}
+
+// Journals retrieves the balance updates made within the time range, grouped by the
+// calendar periods, see Period.Key.
+func (e *Exporter) Journals(
+	ctx context.Context,
+	period Period,
+	from, to time.Time,
+) (map[string]model.BalancesUpdate, error) {
+	updates, err := e.Journal(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]model.BalancesUpdate)
+	for _, u := range updates {
+		key := period.Key(u.Timestamp)
+		out[key] = append(out[key], u)
+	}
+
+	return out, nil
+}
+
+// Statement builds the statement of the balance for the time range. The history of
+// the address is read newest first from the end of the range, so the updates made
+// after it are not read. The opening balance is the last new value of the balance
+// before the range plus the accumulated credits without the old and new values made
+// after it. The closing balance replays the updates of the range from the opening
+// one: every update with the new value sets the running balance to it.
+func (e *Exporter) Statement(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	from, to time.Time,
+) (*Statement, error) {
+	var (
+		st      = &Statement{Address: addr, Account: acc, Currency: curr, From: from, To: to}
+		opening = new(big.Int)
+		found   bool
+		moment  []model.BalanceUpdate
+		q       = model.HistoryQuery{Address: addr, Currency: curr, Before: to}
+	)
+
+	// resolve takes the updates of the same moment from the latest one: the ones of
+	// the range are the entries of the statement, and the ones before it make the
+	// opening balance up to the latest new value
+	resolve := func() {
+		for i := len(moment) - 1; i >= 0 && !found; i-- {
+			u := moment[i]
+
+			switch {
+			case !u.Timestamp.Before(from):
+				st.Entries = append(st.Entries, u)
+			case u.NewValue != nil:
+				opening.Add(opening, u.NewValue.Int())
+				found = true
+			default:
+				opening.Add(opening, u.SignedDelta())
+			}
+		}
+
+		moment = moment[:0]
+	}
+
+	for !found {
+		page, err := e.Notification.History(ctx, q)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrExportRepository, err.Error())
+		}
+
+		for _, entry := range page.Items {
+			u := entry.Update
+			if u.Account != acc {
+				continue
+			}
+
+			if len(moment) != 0 && !u.Timestamp.Equal(moment[0].Timestamp) {
+				if resolve(); found {
+					break
+				}
+			}
+
+			moment = append(moment, u)
+		}
+
+		if page.Bookmark == "" {
+			break
+		}
+
+		q.Bookmark = page.Bookmark
+	}
+
+	resolve()
+
+	// the entries are collected newest first
+	for i, j := 0, len(st.Entries)-1; i < j; i, j = i+1, j-1 {
+		st.Entries[i], st.Entries[j] = st.Entries[j], st.Entries[i]
+	}
+
+	closing := new(big.Int).Set(opening)
+	for _, u := range st.Entries {
+		if u.NewValue != nil {
+			closing.Set(u.NewValue.Int())
+		} else {
+			closing.Add(closing, u.SignedDelta())
+		}
+	}
+
+	st.Opening = curr.Amount(opening)
+	st.Closing = curr.Amount(closing)
+
+	return st, nil
+}
+
+// updates retrieves the balance updates of the exported types matching the query
+// in the order of their timestamps.
+func (e *Exporter) updates(ctx context.Context, q model.NotificationQuery) (model.BalancesUpdate, error) {
+	types := e.Types
+	if len(types) == 0 {
+		types = []string{model.NotificationTypeBalancesUpdate}
+	}
+
+	var out model.BalancesUpdate
+	for _, typ := range types {
+		q.Type, q.Bookmark = typ, ""
+
+		for {
+			page, err := e.Notification.Query(ctx, q)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrExportRepository, err.Error())
+			}
+
+			for _, raw := range page.Items {
+				n, err := model.DecodeNotification[model.BalancesUpdate](raw)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %s", ErrExportRepository, err.Error())
+				}
+
+				for _, u := range n.Body {
+					if (q.From.IsZero() || !u.Timestamp.Before(q.From)) &&
+						(q.To.IsZero() || u.Timestamp.Before(q.To)) {
+						out = append(out, u)
+					}
+				}
+			}
+
+			if page.Bookmark == "" {
+				break
+			}
+
+			q.Bookmark = page.Bookmark
+		}
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Timestamp.Before(out[j].Timestamp)
+	})
+
+	return out, nil
+}
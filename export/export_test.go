@@ -0,0 +1,132 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	alice = model.Address("alice")
+	bob   = model.Address("bob")
+	day   = time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func amount(v int64) *model.Amount {
+	return model.NewAmount(big.NewInt(v), 0)
+}
+
+func newExporter(t *testing.T) *Exporter {
+	n := &storage.Notification{Object: storage.Object{DB: new(inmem.KeyValueDB)}}
+
+	save := func(id string, ts time.Time, updates ...model.BalanceUpdate) {
+		ctx := model.ContextWithTransaction(context.Background(), model.Transaction{ID: id, Timestamp: ts})
+		for i := range updates {
+			updates[i].TxID, updates[i].Timestamp = id, ts
+		}
+
+		assert.NoError(t, n.SaveBalancesUpdate(ctx, model.Notification[model.BalancesUpdate]{
+			ID:   id,
+			Type: model.NotificationTypeBalancesUpdate,
+			Body: updates,
+		}))
+	}
+
+	save("tx1", day.Add(-time.Hour), model.BalanceUpdate{
+		Address: alice, Account: model.AccountToken, Currency: "USD",
+		OldValue: amount(0), NewValue: amount(100), ValueDelta: amount(100),
+		Direction: model.DirectionCredit, Operation: model.OperationDeposit,
+	})
+	save("tx2", day.Add(time.Hour), model.BalanceUpdate{
+		Address: alice, Account: model.AccountToken, Currency: "USD",
+		OldValue: amount(100), NewValue: amount(70), ValueDelta: amount(30),
+		Direction: model.DirectionDebit, Operation: model.OperationTransfer,
+		Counterparty: bob, CounterpartyAccount: model.AccountToken, Memo: "rent, january",
+	}, model.BalanceUpdate{
+		Address: bob, Account: model.AccountToken, Currency: "USD",
+		OldValue: amount(0), NewValue: amount(30), ValueDelta: amount(30),
+		Direction: model.DirectionCredit, Operation: model.OperationTransfer,
+		Counterparty: alice, CounterpartyAccount: model.AccountToken, Memo: "rent, january",
+	})
+	// accumulated credit without the old and the new values
+	save("tx3", day.Add(2*time.Hour), model.BalanceUpdate{
+		Address: alice, Account: model.AccountToken, Currency: "USD",
+		ValueDelta: amount(5), Direction: model.DirectionCredit, Operation: model.OperationDeposit,
+	})
+
+	return &Exporter{Notification: n}
+}
+
+func TestExporter_Journal(t *testing.T) {
+	e := newExporter(t)
+
+	journals, err := e.Journals(context.Background(), PeriodDaily, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, journals["D20240130"], 1)
+	assert.Len(t, journals["D20240131"], 3)
+
+	yearly, err := e.Journals(context.Background(), PeriodYearly, time.Time{}, time.Time{})
+	assert.NoError(t, err)
+	assert.Len(t, yearly["Y2024"], 4)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCSV(&buf, journals["D20240130"]))
+	assert.Equal(t,
+		"timestamp,tx_id,address,account,currency,operation,direction,amount,old_value,new_value,counterparty,counterparty_account,memo\n"+
+			"2024-01-30T23:00:00Z,tx1,alice,43,USD,Deposit,credit,100,0,100,,,\n",
+		buf.String(),
+	)
+
+	buf.Reset()
+	assert.NoError(t, WriteCSV(&buf, journals["D20240131"][:1]))
+	assert.Contains(t, buf.String(), `2024-01-31T01:00:00Z,tx2,alice,43,USD,Transfer,debit,30,100,70,bob,43,"rent, january"`)
+}
+
+func TestExporter_Statement(t *testing.T) {
+	e := newExporter(t)
+
+	st, err := e.Statement(context.Background(), alice, model.AccountToken, "USD", day, day.Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, amount(100), st.Opening)
+	assert.Equal(t, amount(75), st.Closing)
+	assert.Len(t, st.Entries, 2)
+
+	var buf bytes.Buffer
+	assert.NoError(t, WriteCamt053(&buf, st, day.Add(24*time.Hour)))
+	assert.True(t, strings.HasPrefix(buf.String(), xml.Header))
+
+	var doc camtDocument
+	assert.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "alice/43", doc.Stmt.Stmt.Acct.ID.Othr.ID)
+	assert.Equal(t, camtOpeningBooked, doc.Stmt.Stmt.Bal[0].Tp.CdOrPrtry.Cd)
+	assert.Equal(t, "100", doc.Stmt.Stmt.Bal[0].Amt.Value)
+	assert.Equal(t, "75", doc.Stmt.Stmt.Bal[1].Amt.Value)
+	assert.Len(t, doc.Stmt.Stmt.Ntry, 2)
+	assert.Equal(t, camtDebit, doc.Stmt.Stmt.Ntry[0].CdtDbtInd)
+	assert.Equal(t, "bob/43", doc.Stmt.Stmt.Ntry[0].NtryDtls.TxDtls.RltdPties.CdtrAcct.ID.Othr.ID)
+	assert.Equal(t, "rent, january", doc.Stmt.Stmt.Ntry[0].NtryDtls.TxDtls.RmtInf.Ustrd)
+	assert.Equal(t, camtCredit, doc.Stmt.Stmt.Ntry[1].CdtDbtInd)
+
+	// the updates after the end of the period are not included
+	st, err = e.Statement(context.Background(), alice, model.AccountToken, "USD", day, day.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, amount(100), st.Opening)
+	assert.Equal(t, amount(70), st.Closing)
+	assert.Len(t, st.Entries, 1)
+
+	// a period without updates keeps the balance
+	st, err = e.Statement(context.Background(), alice, model.AccountToken, "USD", day.Add(24*time.Hour), day.Add(48*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, amount(75), st.Opening)
+	assert.Equal(t, amount(75), st.Closing)
+	assert.Empty(t, st.Entries)
+}
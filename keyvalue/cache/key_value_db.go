@@ -0,0 +1,268 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+var (
+	_ keyvalue.DB     = &KeyValueDB{}
+	_ keyvalue.PageDB = &KeyValueDB{}
+)
+
+// KeyValueDB wraps a keyvalue.DB and keeps the writes of the current transaction, so
+// they are returned by the reads of the same transaction. The chaincode stub does not
+// return uncommitted writes, so all the storage structures used by a transaction must
+// share one KeyValueDB to see the writes of each other.
+//
+// The writes are kept for the transaction carried by the context and dropped as soon
+// as another transaction is seen. Outside a transaction the calls are passed to the
+// wrapped DB as is.
+type KeyValueDB struct {
+	DB keyvalue.DB
+
+	writes map[keyvalue.Key]write
+	tx     string
+	m      sync.Mutex
+}
+
+// write is a value written by the transaction, or a deletion.
+type write struct {
+	v       keyvalue.Value
+	deleted bool
+}
+
+// Set method stores the provided Value under the given Key in the wrapped DB and
+// keeps it for the current transaction.
+func (db *KeyValueDB) Set(ctx context.Context, k keyvalue.Key, v keyvalue.Value) error {
+	if err := db.DB.Set(ctx, k, v); err != nil {
+		return err
+	}
+
+	db.remember(ctx, k, write{v: v})
+
+	return nil
+}
+
+// Get method retrieves the value written by the current transaction, if any, or the
+// value stored in the wrapped DB.
+func (db *KeyValueDB) Get(ctx context.Context, k keyvalue.Key) (keyvalue.Value, error) {
+	if w, ok := db.written(ctx, k); ok {
+		if w.deleted {
+			return nil, keyvalue.ErrNotFound
+		}
+
+		return w.v, nil
+	}
+
+	return db.DB.Get(ctx, k)
+}
+
+// Del method removes the data associated with the provided Key from the wrapped DB and
+// keeps the deletion for the current transaction.
+func (db *KeyValueDB) Del(ctx context.Context, k keyvalue.Key) error {
+	if err := db.DB.Del(ctx, k); err != nil {
+		return err
+	}
+
+	db.remember(ctx, k, write{deleted: true})
+
+	return nil
+}
+
+// Iter method returns an Iterator over the keys with the provided Prefix, which merges
+// the writes of the current transaction into the keys of the wrapped DB. The written
+// keys match the prefix by whole components, as the chaincode partial composite keys
+// do, and are ordered like them.
+func (db *KeyValueDB) Iter(ctx context.Context, p keyvalue.Prefix) (keyvalue.Iterator, error) {
+	iter, err := db.DB.Iter(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return iter, nil
+	}
+
+	db.m.Lock()
+	defer db.m.Unlock()
+
+	if db.tx != tx.ID || len(db.writes) == 0 {
+		return iter, nil
+	}
+
+	out := &mergeIter{
+		iter:    iter,
+		written: make(map[keyvalue.Key]struct{}, len(db.writes)),
+	}
+
+	for k, w := range db.writes {
+		out.written[k] = struct{}{}
+
+		if !w.deleted && matches(k, p) {
+			out.items = append(out.items, item{k: k, v: w.v})
+		}
+	}
+
+	sort.Slice(out.items, func(a, b int) bool {
+		return keyvalue.Less(out.items[a].k, out.items[b].k)
+	})
+
+	return out, nil
+}
+
+// Page method returns a page of the keys with the provided Prefix of the wrapped DB,
+// if the current transaction has no writes and the wrapped DB implements
+// keyvalue.PageDB. Otherwise the keys starting from the provided Key are returned as
+// a single page, merged with the writes of the transaction.
+func (db *KeyValueDB) Page(
+	ctx context.Context,
+	p keyvalue.Prefix,
+	start keyvalue.Key,
+	size int,
+) (keyvalue.Iterator, keyvalue.Key, error) {
+	if pdb, ok := db.DB.(keyvalue.PageDB); ok && !db.writing(ctx) {
+		return pdb.Page(ctx, p, start, size)
+	}
+
+	iter, err := db.Iter(ctx, p)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return keyvalue.SkipTo(iter, start), "", nil
+}
+
+// writing reports whether the transaction of the context has written any keys.
+func (db *KeyValueDB) writing(ctx context.Context) bool {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return false
+	}
+
+	db.m.Lock()
+	defer db.m.Unlock()
+
+	return db.tx == tx.ID && len(db.writes) != 0
+}
+
+// written returns the write of the key made by the transaction of the context.
+func (db *KeyValueDB) written(ctx context.Context, k keyvalue.Key) (write, bool) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return write{}, false
+	}
+
+	db.m.Lock()
+	defer db.m.Unlock()
+
+	if db.tx != tx.ID {
+		return write{}, false
+	}
+
+	w, ok := db.writes[k]
+
+	return w, ok
+}
+
+// remember keeps the write of the key made by the transaction of the context.
+func (db *KeyValueDB) remember(ctx context.Context, k keyvalue.Key, w write) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	db.m.Lock()
+	defer db.m.Unlock()
+
+	if db.tx != tx.ID || db.writes == nil {
+		db.writes = make(map[keyvalue.Key]write)
+		db.tx = tx.ID
+	}
+
+	db.writes[k] = w
+}
+
+// matches reports whether the key starts with the whole components of the prefix.
+func matches(k keyvalue.Key, p keyvalue.Prefix) bool {
+	return string(k) == string(p) || strings.HasPrefix(string(k), string(p)+keyvalue.KeySeparator)
+}
+
+// item contains a key-value pair of the iteration.
+type item struct {
+	k keyvalue.Key
+	v keyvalue.Value
+}
+
+// mergeIter iterates over the keys of the wrapped DB and the keys written by the
+// transaction in order. The keys of the wrapped DB written by the transaction are
+// skipped, so the written values take precedence and the deleted keys are omitted.
+type mergeIter struct {
+	iter    keyvalue.Iterator
+	written map[keyvalue.Key]struct{}
+	items   []item
+
+	next    *item
+	nextErr error
+}
+
+// HasNext method returns a boolean indicating if there are more keys to iterate over.
+func (i *mergeIter) HasNext() bool {
+	i.fill()
+	return i.next != nil || i.nextErr != nil || len(i.items) != 0
+}
+
+// Next method returns the next key-value pair in order.
+func (i *mergeIter) Next() (keyvalue.Key, keyvalue.Value, error) {
+	i.fill()
+
+	if err := i.nextErr; err != nil {
+		i.nextErr = nil
+		return "", nil, err
+	}
+
+	switch {
+	case i.next == nil && len(i.items) == 0:
+		return "", nil, keyvalue.ErrNotFound
+	case i.next == nil || (len(i.items) != 0 && keyvalue.Less(i.items[0].k, i.next.k)):
+		out := i.items[0]
+		i.items = i.items[1:]
+
+		return out.k, out.v, nil
+	default:
+		out := *i.next
+		i.next = nil
+
+		return out.k, out.v, nil
+	}
+}
+
+// Close method releases the wrapped iterator.
+func (i *mergeIter) Close() error {
+	i.items = nil
+	i.next = nil
+
+	return i.iter.Close()
+}
+
+// fill reads the next key of the wrapped iterator, which is not written by the
+// transaction.
+func (i *mergeIter) fill() {
+	for i.next == nil && i.nextErr == nil && i.iter.HasNext() {
+		k, v, err := i.iter.Next()
+		if err != nil {
+			i.nextErr = err
+			return
+		}
+
+		if _, ok := i.written[k]; !ok {
+			i.next = &item{k: k, v: v}
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubDB behaves like the chaincode stub: the writes are not visible until they are
+// committed.
+type stubDB struct {
+	inmem.KeyValueDB
+
+	pending map[keyvalue.Key]*keyvalue.Value
+}
+
+func (db *stubDB) Set(_ context.Context, k keyvalue.Key, v keyvalue.Value) error {
+	if db.pending == nil {
+		db.pending = make(map[keyvalue.Key]*keyvalue.Value)
+	}
+
+	db.pending[k] = &v
+
+	return nil
+}
+
+func (db *stubDB) Del(_ context.Context, k keyvalue.Key) error {
+	if db.pending == nil {
+		db.pending = make(map[keyvalue.Key]*keyvalue.Value)
+	}
+
+	db.pending[k] = nil
+
+	return nil
+}
+
+func (db *stubDB) commit(ctx context.Context) {
+	for k, v := range db.pending {
+		if v == nil {
+			_ = db.KeyValueDB.Del(ctx, k)
+		} else {
+			_ = db.KeyValueDB.Set(ctx, k, *v)
+		}
+	}
+
+	db.pending = nil
+}
+
+func TestKeyValueDB_ReadYourWrites(t *testing.T) {
+	stub := new(stubDB)
+	db := &KeyValueDB{DB: stub}
+
+	tx1 := model.ContextWithTransaction(context.Background(), model.Transaction{ID: "tx1"})
+
+	assert.NoError(t, db.Set(tx1, "a/1", keyvalue.Value("1")))
+
+	v, err := db.Get(tx1, "a/1")
+	assert.NoError(t, err)
+	assert.Equal(t, keyvalue.Value("1"), v)
+
+	// other transactions and calls outside a transaction read the committed state
+	tx2 := model.ContextWithTransaction(context.Background(), model.Transaction{ID: "tx2"})
+
+	_, err = db.Get(context.Background(), "a/1")
+	assert.ErrorIs(t, err, keyvalue.ErrNotFound)
+
+	stub.commit(tx1)
+
+	v, err = db.Get(tx2, "a/1")
+	assert.NoError(t, err)
+	assert.Equal(t, keyvalue.Value("1"), v)
+
+	assert.NoError(t, db.Del(tx2, "a/1"))
+
+	_, err = db.Get(tx2, "a/1")
+	assert.ErrorIs(t, err, keyvalue.ErrNotFound)
+}
+
+func TestKeyValueDB_Iter(t *testing.T) {
+	stub := new(stubDB)
+	db := &KeyValueDB{DB: stub}
+
+	ctx := context.Background()
+	for _, k := range []keyvalue.Key{"a/1", "a/3", "a/5", "ab/1"} {
+		_ = stub.KeyValueDB.Set(ctx, k, keyvalue.Value(k))
+	}
+
+	tx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx"})
+
+	assert.NoError(t, db.Set(tx, "a/2", keyvalue.Value("new")))
+	assert.NoError(t, db.Set(tx, "a/3", keyvalue.Value("changed")))
+	assert.NoError(t, db.Del(tx, "a/5"))
+	assert.NoError(t, db.Set(tx, "a/6/x", keyvalue.Value("nested")))
+	assert.NoError(t, db.Set(tx, "ab/2", keyvalue.Value("other")))
+
+	iter, err := db.Iter(tx, "a")
+	assert.NoError(t, err)
+
+	defer iter.Close()
+
+	var got []string
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		assert.NoError(t, err)
+
+		got = append(got, string(k)+"="+string(v))
+	}
+
+	// the committed "ab/1" is returned by the inmem prefix match, the written
+	// "ab/2" is not
+	assert.Equal(t, []string{"a/1=a/1", "a/2=new", "a/3=changed", "a/6/x=nested", "ab/1=ab/1"}, got)
+}
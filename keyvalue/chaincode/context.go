@@ -0,0 +1,27 @@
+package chaincode
+
+import (
+	"context"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/hyperledger/fabric-chaincode-go/shim"
+)
+
+// NewContext returns a copy of ctx carrying the identifier and the timestamp of the
+// transaction being executed by the stub. The result should be passed to the service
+// layer, which uses it to derive time periods, record identifiers and so on.
+func NewContext(ctx context.Context, stub shim.ChaincodeStubInterface) (context.Context, error) {
+	if stub == nil {
+		return nil, internalError(ErrChaincodeNilStub)
+	}
+
+	ts, err := stub.GetTxTimestamp()
+	if err != nil {
+		return nil, internalError(err)
+	}
+
+	return model.ContextWithTransaction(ctx, model.Transaction{
+		ID:        stub.GetTxID(),
+		Timestamp: ts.AsTime(),
+	}), nil
+}
@@ -10,7 +10,10 @@ import (
 	"github.com/hyperledger/fabric-chaincode-go/shim"
 )
 
-var _ keyvalue.DB = &KeyValueDB{}
+var (
+	_ keyvalue.DB     = &KeyValueDB{}
+	_ keyvalue.PageDB = &KeyValueDB{}
+)
 
 // ErrChaincodeNilStub returns when ChaincodeStorage stub is empty.
 var ErrChaincodeNilStub = errors.New("chaincode stub is nil")
@@ -108,6 +111,51 @@ func (db *KeyValueDB) Iter(_ context.Context, p keyvalue.Prefix) (keyvalue.Itera
 	return &chaincodeIterator{iter: iter}, nil
 }
 
+// Page takes a context, a prefix, a start key and a page size, and returns an iterator
+// over the page of the keys in the chaincode storage that match the prefix, and the key
+// to start the next page from. The ledger paginates only in read-only queries.
+func (db *KeyValueDB) Page(
+	_ context.Context,
+	p keyvalue.Prefix,
+	start keyvalue.Key,
+	size int,
+) (keyvalue.Iterator, keyvalue.Key, error) {
+	if db.Stub == nil {
+		return nil, "", internalError(ErrChaincodeNilStub)
+	}
+
+	var bookmark string
+	if start != "" {
+		keys := strings.Split(string(start), keyvalue.KeySeparator)
+
+		key, err := db.Stub.CreateCompositeKey(keys[0], keys[1:])
+		if err != nil {
+			return nil, "", internalError(err)
+		}
+
+		bookmark = key
+	}
+
+	keys := strings.Split(string(p), keyvalue.KeySeparator)
+
+	iter, meta, err := db.Stub.GetStateByPartialCompositeKeyWithPagination(
+		keys[0],
+		keys[1:],
+		int32(size), //nolint:gosec
+		bookmark,
+	)
+	if err != nil {
+		return nil, "", internalError(err)
+	}
+
+	var next keyvalue.Key
+	if meta != nil && meta.GetBookmark() != "" && int(meta.GetFetchedRecordsCount()) == size {
+		next = splitCompositeKey(meta.GetBookmark())
+	}
+
+	return &chaincodeIterator{iter: iter}, next, nil
+}
+
 func (db *KeyValueDB) tryComposite(k keyvalue.Key) (string, error) {
 	if keys := strings.Split(string(k), keyvalue.KeySeparator); len(keys) > 1 {
 		key, err := db.Stub.CreateCompositeKey(keys[0], keys[1:])
@@ -139,6 +187,20 @@ func (i *chaincodeIterator) Next() (keyvalue.Key, keyvalue.Value, error) {
 		return "", nil, internalError(err)
 	}
 
+	return splitCompositeKey(response.Key), response.Value, nil
+}
+
+// Close method that will release resources associated with the Iterator.
+func (i *chaincodeIterator) Close() error {
+	if err := i.iter.Close(); err != nil {
+		return internalError(err)
+	}
+	return nil
+}
+
+// splitCompositeKey converts the composite key of the ledger to the key with the
+// components divided by keyvalue.KeySeparator.
+func splitCompositeKey(key string) keyvalue.Key {
 	// https://github.com/hyperledger/fabric-chaincode-go/blob/main/shim/stub.go#L469
 	const minUnicodeRuneValue = 0
 
@@ -147,22 +209,14 @@ func (i *chaincodeIterator) Next() (keyvalue.Key, keyvalue.Value, error) {
 		components     = []string{}
 	)
 
-	for i := 1; i < len(response.Key); i++ {
-		if response.Key[i] == minUnicodeRuneValue {
-			components = append(components, response.Key[componentIndex:i])
+	for i := 1; i < len(key); i++ {
+		if key[i] == minUnicodeRuneValue {
+			components = append(components, key[componentIndex:i])
 			componentIndex = i + 1
 		}
 	}
 
-	return keyvalue.Key(strings.Join(components, keyvalue.KeySeparator)), response.Value, nil
-}
-
-// Close method that will release resources associated with the Iterator.
-func (i *chaincodeIterator) Close() error {
-	if err := i.iter.Close(); err != nil {
-		return internalError(err)
-	}
-	return nil
+	return keyvalue.Key(strings.Join(components, keyvalue.KeySeparator))
 }
 
 func internalError(cause error) error {
@@ -3,11 +3,15 @@ package chaincode
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/anoideaopen/token/keyvalue"
 	"github.com/anoideaopen/token/keyvalue/mock"
+	"github.com/anoideaopen/token/model"
+	"github.com/hyperledger/fabric-protos-go/peer"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestKeyValueDB_SaveLoadDelete(t *testing.T) {
@@ -72,3 +76,48 @@ func TestKeyValueDB_Iterate(t *testing.T) {
 	assert.Nil(t, err)
 	assert.NotNil(t, iterator)
 }
+
+func TestNewContext(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stub := mock.NewMockChaincodeStubInterface(ctrl)
+	ts := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	stub.EXPECT().GetTxTimestamp().Return(timestamppb.New(ts), nil)
+	stub.EXPECT().GetTxID().Return("tx1")
+
+	ctx, err := NewContext(context.Background(), stub)
+	assert.NoError(t, err)
+
+	tx, ok := model.TransactionFromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, model.Transaction{ID: "tx1", Timestamp: ts}, tx)
+}
+
+func TestKeyValueDB_Page(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	stub := mock.NewMockChaincodeStubInterface(ctrl)
+	cs := &KeyValueDB{Stub: stub}
+	ctx := context.Background()
+
+	gomock.InOrder(
+		stub.EXPECT().GetStateByPartialCompositeKeyWithPagination("key1", []string{}, int32(2), "").
+			Return(nil, &peer.QueryResponseMetadata{FetchedRecordsCount: 2, Bookmark: "\x00key1\x00c\x00"}, nil),
+		stub.EXPECT().CreateCompositeKey("key1", []string{"c"}).Return("\x00key1\x00c\x00", nil),
+		stub.EXPECT().GetStateByPartialCompositeKeyWithPagination("key1", []string{}, int32(2), "\x00key1\x00c\x00").
+			Return(nil, &peer.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: "\x00key1\x00d\x00"}, nil),
+	)
+
+	iterator, next, err := cs.Page(ctx, "key1", "", 2)
+	assert.NoError(t, err)
+	assert.NotNil(t, iterator)
+	assert.Equal(t, keyvalue.Key("key1/c"), next)
+
+	// the short page is the last one
+	_, next, err = cs.Page(ctx, "key1", next, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, next)
+}
@@ -71,6 +71,19 @@ type DB interface {
 	Iter(context.Context, Prefix) (Iterator, error)
 }
 
+// PageDB interface is implemented by the storages, which can start an iteration from
+// a key. Paginated queries use it to read the keys of the requested page only, instead
+// of scanning the keys of the previous pages again. The chaincode storage paginates
+// only in read-only queries, as the ledger does not support pagination in transactions
+// which update the state.
+type PageDB interface {
+	// Page method returns an Iterator for at most size keys with the provided Prefix,
+	// starting from the provided Key inclusive, and the Key to start the next page from,
+	// which is empty for the last page. An empty start Key starts from the first key
+	// with the Prefix.
+	Page(ctx context.Context, p Prefix, start Key, size int) (Iterator, Key, error)
+}
+
 // Iterator interface provides methods for iterating over keys and values in the storage.
 type Iterator interface {
 	// HasNext method returns a boolean indicating if there are more keys to iterate over.
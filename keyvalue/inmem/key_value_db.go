@@ -2,13 +2,17 @@ package inmem
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/anoideaopen/token/keyvalue"
 )
 
-var _ keyvalue.DB = &KeyValueDB{}
+var (
+	_ keyvalue.DB     = &KeyValueDB{}
+	_ keyvalue.PageDB = &KeyValueDB{}
+)
 
 // KeyValueDB implements a KeyValue interface using a map stored in memory.
 type KeyValueDB struct {
@@ -65,9 +69,43 @@ func (db *KeyValueDB) Iter(_ context.Context, p keyvalue.Prefix) (keyvalue.Itera
 		}
 	}
 
+	// keys are iterated in the order of their components, as it is done by the ledger
+	sort.Slice(i.items, func(a, b int) bool {
+		return keyvalue.Less(i.items[a].k, i.items[b].k)
+	})
+
 	return i, nil
 }
 
+// Page method returns an Iterator for at most size keys with the provided Prefix,
+// starting from the provided Key, and the Key to start the next page from.
+func (db *KeyValueDB) Page(
+	ctx context.Context,
+	p keyvalue.Prefix,
+	start keyvalue.Key,
+	size int,
+) (keyvalue.Iterator, keyvalue.Key, error) {
+	iter, err := db.Iter(ctx, p)
+	if err != nil {
+		return nil, "", err
+	}
+
+	i := iter.(*inmemIter) //nolint:forcetypeassert
+
+	from := sort.Search(len(i.items), func(n int) bool {
+		return !keyvalue.Less(i.items[n].k, start)
+	})
+	i.items = i.items[from:]
+
+	var next keyvalue.Key
+	if size > 0 && len(i.items) > size {
+		next = i.items[size].k
+		i.items = i.items[:size]
+	}
+
+	return i, next, nil
+}
+
 func (db *KeyValueDB) lazyInit() {
 	if db.data == nil {
 		db.data = make(map[keyvalue.Key]keyvalue.Value)
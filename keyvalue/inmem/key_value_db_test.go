@@ -81,3 +81,36 @@ func TestKeyValueDB_Iterate(t *testing.T) {
 	err = it.Close()
 	assert.NoError(t, err)
 }
+
+func TestKeyValueDB_Page(t *testing.T) {
+	kv := new(KeyValueDB)
+	ctx := context.Background()
+
+	for _, k := range []keyvalue.Key{"a/1", "a/2", "a/3", "a/4", "a/5", "b/1"} {
+		_ = kv.Set(ctx, k, keyvalue.Value(k))
+	}
+
+	iter, next, err := kv.Page(ctx, "a", "a/2", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, keyvalue.Key("a/4"), next)
+
+	var keys []keyvalue.Key
+	for iter.HasNext() {
+		k, _, err := iter.Next()
+		assert.NoError(t, err)
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []keyvalue.Key{"a/2", "a/3"}, keys)
+
+	// the pages are read one by one up to the last key of the prefix
+	iter, err = keyvalue.IterFrom(ctx, kv, "a", "a/2", 2)
+	assert.NoError(t, err)
+
+	keys = nil
+	for iter.HasNext() {
+		k, _, err := iter.Next()
+		assert.NoError(t, err)
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []keyvalue.Key{"a/2", "a/3", "a/4", "a/5"}, keys)
+}
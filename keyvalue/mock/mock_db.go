@@ -93,6 +93,45 @@ func (mr *MockDBMockRecorder) Set(arg0, arg1, arg2 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockDB)(nil).Set), arg0, arg1, arg2)
 }
 
+// MockPageDB is a mock of PageDB interface.
+type MockPageDB struct {
+	ctrl     *gomock.Controller
+	recorder *MockPageDBMockRecorder
+}
+
+// MockPageDBMockRecorder is the mock recorder for MockPageDB.
+type MockPageDBMockRecorder struct {
+	mock *MockPageDB
+}
+
+// NewMockPageDB creates a new mock instance.
+func NewMockPageDB(ctrl *gomock.Controller) *MockPageDB {
+	mock := &MockPageDB{ctrl: ctrl}
+	mock.recorder = &MockPageDBMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPageDB) EXPECT() *MockPageDBMockRecorder {
+	return m.recorder
+}
+
+// Page mocks base method.
+func (m *MockPageDB) Page(ctx context.Context, p keyvalue.Prefix, start keyvalue.Key, size int) (keyvalue.Iterator, keyvalue.Key, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Page", ctx, p, start, size)
+	ret0, _ := ret[0].(keyvalue.Iterator)
+	ret1, _ := ret[1].(keyvalue.Key)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Page indicates an expected call of Page.
+func (mr *MockPageDBMockRecorder) Page(ctx, p, start, size interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Page", reflect.TypeOf((*MockPageDB)(nil).Page), ctx, p, start, size)
+}
+
 // MockIterator is a mock of Iterator interface.
 type MockIterator struct {
 	ctrl     *gomock.Controller
@@ -0,0 +1,159 @@
+package keyvalue
+
+import (
+	"context"
+	"strings"
+)
+
+// IterFrom returns an Iterator for the keys with the provided Prefix, starting from the
+// provided Key inclusive. If the DB implements PageDB, the keys are read page by page
+// with the size and the keys before the start are not read at all. Otherwise all the
+// keys with the Prefix are read and the ones before the start are skipped.
+func IterFrom(ctx context.Context, db DB, p Prefix, start Key, size int) (Iterator, error) {
+	if pdb, ok := db.(PageDB); ok && size > 0 {
+		i := &pageIter{ctx: ctx, db: pdb, p: p, size: size}
+		if err := i.open(start); err != nil {
+			return nil, err
+		}
+
+		return i, nil
+	}
+
+	iter, err := db.Iter(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return SkipTo(iter, start), nil
+}
+
+// SkipTo returns an Iterator, which skips the keys of the provided Iterator preceding
+// the start Key. The keys are compared by Less.
+func SkipTo(iter Iterator, start Key) Iterator {
+	if start == "" {
+		return iter
+	}
+
+	return &skipIter{Iterator: iter, start: start}
+}
+
+// Less compares the keys component by component, which is the order of the keys in
+// the chaincode storage.
+func Less(a, b Key) bool {
+	return strings.ReplaceAll(string(a), KeySeparator, "\x00") <
+		strings.ReplaceAll(string(b), KeySeparator, "\x00")
+}
+
+// pageIter iterates over the keys of the PageDB, reading the next page when the
+// current one is over.
+type pageIter struct {
+	ctx  context.Context //nolint:containedctx
+	db   PageDB
+	p    Prefix
+	size int
+
+	iter Iterator
+	next Key
+	err  error
+}
+
+// HasNext method returns a boolean indicating if there are more keys to iterate over.
+func (i *pageIter) HasNext() bool {
+	for i.err == nil && !i.iter.HasNext() {
+		if i.next == "" {
+			return false
+		}
+
+		i.err = i.open(i.next)
+	}
+
+	return true
+}
+
+// Next method returns the next key-value pair, reading the next page if needed.
+func (i *pageIter) Next() (Key, Value, error) {
+	if !i.HasNext() {
+		return "", nil, ErrNotFound
+	}
+
+	if err := i.err; err != nil {
+		i.err = nil
+		i.next = ""
+
+		return "", nil, err
+	}
+
+	return i.iter.Next()
+}
+
+// Close method releases the iterator of the current page.
+func (i *pageIter) Close() error {
+	return i.iter.Close()
+}
+
+// open replaces the iterator with the one of the page starting from the key.
+func (i *pageIter) open(start Key) error {
+	iter, next, err := i.db.Page(i.ctx, i.p, start, i.size)
+	if err != nil {
+		return err
+	}
+
+	if i.iter != nil {
+		_ = i.iter.Close()
+	}
+
+	i.iter, i.next = iter, next
+
+	return nil
+}
+
+// skipIter skips the keys preceding the start.
+type skipIter struct {
+	Iterator
+
+	start   Key
+	peeked  *skipItem
+	skipped bool
+}
+
+// skipItem contains the first key-value pair which is not skipped.
+type skipItem struct {
+	k   Key
+	v   Value
+	err error
+}
+
+// HasNext method returns a boolean indicating if there are more keys to iterate over.
+func (i *skipIter) HasNext() bool {
+	i.skip()
+	return i.peeked != nil || i.Iterator.HasNext()
+}
+
+// Next method returns the next key-value pair, which does not precede the start.
+func (i *skipIter) Next() (Key, Value, error) {
+	i.skip()
+
+	if p := i.peeked; p != nil {
+		i.peeked = nil
+		return p.k, p.v, p.err
+	}
+
+	return i.Iterator.Next()
+}
+
+// skip reads the keys up to the first one, which does not precede the start.
+func (i *skipIter) skip() {
+	if i.skipped {
+		return
+	}
+
+	for i.Iterator.HasNext() {
+		k, v, err := i.Iterator.Next()
+		if err != nil || !Less(k, i.start) {
+			i.peeked = &skipItem{k: k, v: v, err: err}
+			break
+		}
+	}
+
+	i.skipped = true
+}
@@ -0,0 +1,18 @@
+package model
+
+// Role represents a privilege granted to an address, which allows it to
+// perform restricted operations.
+type Role string
+
+// Constants for Roles.
+const (
+	// RoleRegulator allows an address to freeze other addresses and to move
+	// funds without the owner's consent.
+	RoleRegulator Role = "regulator"
+
+	// RoleVerifier allows an address to add verified addresses to the allow-list.
+	RoleVerifier Role = "verifier"
+
+	// RoleMinter allows an address to mint non-fungible tokens.
+	RoleMinter Role = "minter"
+)
@@ -0,0 +1,222 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Errors related to the account type registry.
+var (
+	// ErrAccountInvalid is returned when an account type fails to validate.
+	ErrAccountInvalid = errors.New("invalid account type")
+
+	// ErrAccountRegistered is returned when an account type is declared twice.
+	ErrAccountRegistered = errors.New("account type is already registered")
+)
+
+// Operation is a set of balance operations permitted on an account.
+type Operation uint
+
+// Constants for Operations.
+const (
+	OperationDeposit Operation = 1 << iota
+	OperationWithdraw
+	OperationTransfer
+	OperationInternalTransfer
+	OperationForcedTransfer
+	OperationHold
+	OperationReversal // reversal of the balance updates by the regulator
+	OperationDistribution
+	OperationAccrual // balances not permitting it are left as they are by accrual rates
+	OperationCapture
+
+	// OperationAll permits all the balance operations.
+	OperationAll = OperationDeposit | OperationWithdraw | OperationTransfer |
+		OperationInternalTransfer | OperationForcedTransfer | OperationHold |
+		OperationReversal | OperationDistribution | OperationAccrual |
+		OperationCapture
+)
+
+// LockedOperations are the only operations permitted on a locked account: the funds
+// are moved between it and the account they are locked of by InternalTransfer, and
+// the regulator operations and the adjustments of the balances are applied as usual.
+const LockedOperations = OperationInternalTransfer | OperationForcedTransfer |
+	OperationReversal | OperationDistribution | OperationAccrual
+
+var operationNames = map[Operation]string{
+	OperationDeposit:          "Deposit",
+	OperationWithdraw:         "Withdraw",
+	OperationTransfer:         "Transfer",
+	OperationInternalTransfer: "InternalTransfer",
+	OperationForcedTransfer:   "ForcedTransfer",
+	OperationHold:             "Hold",
+	OperationReversal:         "Reversal",
+	OperationDistribution:     "Distribution",
+	OperationAccrual:          "Accrual",
+	OperationCapture:          "Capture",
+}
+
+// String returns a string representation of the Operation.
+func (op Operation) String() string {
+	if name, ok := operationNames[op]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Operation(%d)", uint(op))
+}
+
+// MarshalText encodes a single operation as its name and a set of operations as
+// a number.
+func (op Operation) MarshalText() ([]byte, error) {
+	if name, ok := operationNames[op]; ok {
+		return []byte(name), nil
+	}
+
+	return []byte(strconv.FormatUint(uint64(op), 10)), nil //nolint:gomnd
+}
+
+// UnmarshalText decodes an operation encoded by MarshalText.
+func (op *Operation) UnmarshalText(text []byte) error {
+	for o, name := range operationNames {
+		if name == string(text) {
+			*op = o
+			return nil
+		}
+	}
+
+	v, err := strconv.ParseUint(string(text), 10, 0) //nolint:gomnd
+	if err != nil {
+		return fmt.Errorf("unknown operation '%s'", text)
+	}
+
+	*op = Operation(v)
+
+	return nil
+}
+
+// AccountType describes an account declared in the registry.
+type AccountType struct {
+	Account     Account   // Identifier of the account, stored as a part of the balance key.
+	Name        string    // Human-readable name of the account.
+	Operations  Operation // Set of the balance operations permitted on the account, see LockedOperations.
+	LockedOf    Account   // Account whose funds are locked on this one, zero if it is not locked.
+	AllowListed bool      // Only verified addresses may hold balances on the account.
+}
+
+// IsLocked reports whether funds on the account are locked funds of another account.
+func (t AccountType) IsLocked() bool {
+	return t.LockedOf != 0
+}
+
+// Permits reports whether all the operations are permitted on the account. A locked
+// account permits only the LockedOperations among its Operations.
+func (t AccountType) Permits(op Operation) bool {
+	ops := t.Operations
+	if t.IsLocked() {
+		ops &= LockedOperations
+	}
+
+	return ops&op == op
+}
+
+// Pairs reports whether the funds may be moved between the accounts by
+// InternalTransfer. A locked account is paired only with the account its funds are
+// locked of, while two unlocked accounts are always paired.
+func (t AccountType) Pairs(other AccountType) bool {
+	switch {
+	case t.IsLocked():
+		return t.LockedOf == other.Account
+	case other.IsLocked():
+		return other.LockedOf == t.Account
+	default:
+		return true
+	}
+}
+
+var accounts = struct {
+	sync.RWMutex
+	types map[Account]AccountType
+}{
+	types: map[Account]AccountType{
+		AccountToken: {
+			Account:    AccountToken,
+			Name:       "AccountToken",
+			Operations: OperationAll,
+		},
+		AccountTokenLocked: {
+			Account:    AccountTokenLocked,
+			Name:       "AccountTokenLocked",
+			Operations: OperationAll,
+			LockedOf:   AccountToken,
+		},
+		AccountAllowed: {
+			Account:     AccountAllowed,
+			Name:        "AccountAllowed",
+			Operations:  OperationAll,
+			AllowListed: true,
+		},
+		AccountAllowedLocked: {
+			Account:     AccountAllowedLocked,
+			Name:        "AccountAllowedLocked",
+			Operations:  OperationAll,
+			LockedOf:    AccountAllowed,
+			AllowListed: true,
+		},
+	},
+}
+
+// RegisterAccount declares a new account type. It is intended to be called during
+// the application initialization. Account identifiers must be positive and unique.
+// A locked account must refer to an already registered one.
+func RegisterAccount(t AccountType) error {
+	accounts.Lock()
+	defer accounts.Unlock()
+
+	if t.Account <= 0 || t.Name == "" {
+		return fmt.Errorf("%w: %d", ErrAccountInvalid, t.Account)
+	}
+
+	if _, ok := accounts.types[t.Account]; ok {
+		return fmt.Errorf("%w: %d", ErrAccountRegistered, t.Account)
+	}
+
+	if t.IsLocked() {
+		if _, ok := accounts.types[t.LockedOf]; !ok || t.LockedOf == t.Account {
+			return fmt.Errorf("%w: unknown locked account %d", ErrAccountInvalid, t.LockedOf)
+		}
+	}
+
+	accounts.types[t.Account] = t
+
+	return nil
+}
+
+// LookupAccount returns the declared type of the account.
+func LookupAccount(a Account) (AccountType, bool) {
+	accounts.RLock()
+	defer accounts.RUnlock()
+
+	t, ok := accounts.types[a]
+
+	return t, ok
+}
+
+// Accounts returns all the declared account types in ascending order of identifiers.
+func Accounts() []AccountType {
+	accounts.RLock()
+	defer accounts.RUnlock()
+
+	out := make([]AccountType, 0, len(accounts.types))
+	for _, t := range accounts.types {
+		out = append(out, t)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Account < out[j].Account
+	})
+
+	return out
+}
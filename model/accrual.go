@@ -0,0 +1,184 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAccrualRateInvalid is returned when an accrual rate fails to validate.
+var ErrAccrualRateInvalid = errors.New("invalid accrual rate")
+
+// AccrualRate configures time-based adjustments of the balances of a currency: the
+// compound interest for a positive rate or the demurrage for a negative one. Only
+// positive balances are adjusted. The rates of a currency form a history, each rate is
+// in force from its Since till the Since of the next one, and a zero rate stops the
+// accrual.
+type AccrualRate struct {
+	Rate   *big.Rat      `validate:"required"` // Rate per period, greater than -1.
+	Period time.Duration `validate:"gte=1s"`   // Compounding period, at least a second.
+	Since  time.Time     // Moment the rate is effective from.
+}
+
+// Apply returns the balance adjusted for the time between the moments at the rate,
+// see Accrue.
+func (r *AccrualRate) Apply(val *big.Int, from, to time.Time) *big.Int {
+	return Accrue(val, []*AccrualRate{r}, from, to)
+}
+
+// Factor returns the multiplier of the balance for the time between the moments. The
+// balance is compounded once per full period, and the rate of the last incomplete
+// period is proportional to its duration:
+//
+//	factor = (1 + rate)^periods * (1 + rate*rest/period)
+//
+// The power is computed exactly by squaring. The time before Since is not taken into
+// account, and the factor is one for a zero from, unless Since is set.
+func (r *AccrualRate) Factor(from, to time.Time) *big.Rat {
+	out := big.NewRat(1, 1)
+
+	if from.Before(r.Since) {
+		from = r.Since
+	}
+
+	if r.Rate.Sign() == 0 || from.IsZero() || !to.After(from) {
+		return out
+	}
+
+	elapsed := to.Sub(from)
+
+	// base = 1 + rate
+	base := new(big.Rat).Add(big.NewRat(1, 1), r.Rate)
+	for n := uint64(elapsed / r.Period); n > 0; n >>= 1 {
+		if n&1 == 1 {
+			out.Mul(out, base)
+		}
+
+		if n > 1 {
+			base.Mul(base, base)
+		}
+	}
+
+	if rest := elapsed % r.Period; rest != 0 {
+		// 1 + rate * rest / period
+		partial := new(big.Rat).Mul(r.Rate, big.NewRat(int64(rest), int64(r.Period)))
+		out.Mul(out, partial.Add(partial, big.NewRat(1, 1)))
+	}
+
+	return out
+}
+
+// Accrue returns the balance adjusted for the time between the moments at the rates of
+// the history, each applied for the time it is in force. The rates are ordered by
+// their Since, the later one of the rates with the same Since is in force. The factors
+// of the rates are multiplied exactly and the result is rounded toward zero once.
+func Accrue(val *big.Int, rates []*AccrualRate, from, to time.Time) *big.Int {
+	out := new(big.Int).Set(val)
+	if out.Sign() <= 0 {
+		return out
+	}
+
+	factor := big.NewRat(1, 1)
+	for i, r := range rates {
+		factor.Mul(factor, r.Factor(from, end(rates, i, to)))
+	}
+
+	// val * factor, rounded toward zero
+	out.Mul(out, factor.Num())
+
+	return out.Quo(out, factor.Denom())
+}
+
+// RatesInForce returns the rates of the history, which are in force for some time
+// between the moments, see Accrue.
+func RatesInForce(rates []*AccrualRate, from, to time.Time) []*AccrualRate {
+	var out []*AccrualRate
+	for i, r := range rates {
+		since := r.Since
+		if since.Before(from) {
+			since = from
+		}
+
+		if end(rates, i, to).After(since) {
+			out = append(out, r)
+		}
+	}
+
+	return out
+}
+
+// end returns the moment the rate of the history with given index is in force till,
+// but not later than the provided one.
+func end(rates []*AccrualRate, i int, to time.Time) time.Time {
+	if i+1 < len(rates) && rates[i+1].Since.Before(to) {
+		return rates[i+1].Since
+	}
+
+	return to
+}
+
+// Реализация интерфейса model.Object.
+func (r *AccrualRate) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(r)
+}
+
+func (r *AccrualRate) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+func (r *AccrualRate) Clone() Object {
+	nr := *r
+	if r.Rate != nil {
+		nr.Rate = new(big.Rat).Set(r.Rate)
+	}
+
+	return &nr
+}
+
+func (r *AccrualRate) Validate() error {
+	if err := NewValidator().Struct(r); err != nil {
+		return err
+	}
+
+	if r.Rate.Cmp(big.NewRat(-1, 1)) <= 0 {
+		return ErrAccrualRateInvalid
+	}
+
+	return nil
+}
+
+// -----------------------------------
+
+// Accrual is an accounting record of the interest or the demurrage applied to a balance.
+type Accrual struct {
+	Rate   *big.Rat       `validate:"required"` // Rate per period in force at To.
+	Period time.Duration  `validate:"gt=0"`     // Compounding period of the rate.
+	From   time.Time      // Moment the balance was last touched.
+	To     time.Time      `validate:"required"` // Moment the balance is adjusted to.
+	Rates  []*AccrualRate // Rates applied between From and To in order.
+	Update BalanceUpdate  // Update of the balance by the accrued amount.
+}
+
+// Реализация интерфейса model.Validator.
+func (a Accrual) Validate() error {
+	return NewValidator().Struct(a)
+}
+
+// BalanceUpdates returns the balance update made by the accrual.
+func (a Accrual) BalanceUpdates() BalancesUpdate {
+	return BalancesUpdate{a.Update}
+}
+
+// Addresses returns the address of the balance updated by the accrual.
+func (a Accrual) Addresses() []Address {
+	return []Address{a.Update.Address}
+}
+
+// AccrualID returns the identifier of the notification of the accrual applied to the
+// balance by the transaction. A balance is adjusted at most once per transaction.
+func AccrualID(txID string, addr Address, acc Account, curr Currency) string {
+	return strings.Join([]string{txID, string(addr), strconv.Itoa(int(acc)), string(curr)}, ":")
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AllowListEntry contains the result of the verification (KYC) of an address.
+type AllowListEntry struct {
+	Address   Address   `validate:"required"` // Verified address.
+	Level     uint      `validate:"gt=0"`     // Verification level, the higher the stricter.
+	ExpiresAt time.Time // Moment the verification expires, zero value means never.
+}
+
+// IsActive checks whether the verification is not expired at the moment and its
+// level is not lower than required.
+func (e *AllowListEntry) IsActive(now time.Time, level uint) bool {
+	if e.Level < level {
+		return false
+	}
+
+	return e.ExpiresAt.IsZero() || now.Before(e.ExpiresAt)
+}
+
+// Реализация интерфейса model.Object.
+func (e *AllowListEntry) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(e)
+}
+
+func (e *AllowListEntry) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, e)
+}
+
+func (e *AllowListEntry) Clone() Object {
+	ne := *e
+	return &ne
+}
+
+func (e *AllowListEntry) Validate() error {
+	return NewValidator().Struct(e)
+}
@@ -0,0 +1,201 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Errors related to amounts.
+var (
+	// ErrAmountInvalid is returned when a string is not a valid decimal number.
+	ErrAmountInvalid = errors.New("invalid amount")
+
+	// ErrAmountPrecision is returned when an amount has more fractional digits than
+	// the precision allows.
+	ErrAmountPrecision = errors.New("amount exceeds precision")
+)
+
+// Amount is a fixed-point decimal number. It is stored as an integer number of the
+// minimal units of a currency, e.g. Value = 12345 with Precision = 3 means "12.345".
+type Amount struct {
+	Value     *big.Int // Number of the minimal units.
+	Precision uint8    // Number of the fractional decimal digits.
+}
+
+// NewAmount returns a new Amount of the minimal units with the given precision.
+// The value is copied.
+func NewAmount(v *big.Int, precision uint8) *Amount {
+	a := &Amount{Value: new(big.Int), Precision: precision}
+	if v != nil {
+		a.Value.Set(v)
+	}
+
+	return a
+}
+
+// ParseAmount parses a decimal string like "12.345" or "-0.5". It returns
+// ErrAmountPrecision if the string contains more fractional digits than precision.
+func ParseAmount(s string, precision uint8) (*Amount, error) {
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+
+	sign := ""
+	if strings.HasPrefix(intPart, "-") || strings.HasPrefix(intPart, "+") {
+		sign, intPart = intPart[:1], intPart[1:]
+	}
+
+	if (intPart == "" && fracPart == "") || (hasPoint && fracPart == "") ||
+		!isDigits(intPart) || !isDigits(fracPart) {
+		return nil, fmt.Errorf("%w: '%s'", ErrAmountInvalid, s)
+	}
+
+	if trimmed := strings.TrimRight(fracPart, "0"); len(trimmed) > int(precision) {
+		return nil, fmt.Errorf("%w: '%s' has more than %d fractional digits", ErrAmountPrecision, s, precision)
+	}
+
+	digits := sign + intPart + fracPart
+	if len(fracPart) < int(precision) {
+		digits += strings.Repeat("0", int(precision)-len(fracPart))
+	} else {
+		digits = digits[:len(digits)-len(fracPart)+int(precision)]
+	}
+
+	v, ok := new(big.Int).SetString(digits, 10) //nolint:gomnd
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s'", ErrAmountInvalid, s)
+	}
+
+	return &Amount{Value: v, Precision: precision}, nil
+}
+
+// Int returns a copy of the number of the minimal units.
+func (a *Amount) Int() *big.Int {
+	if a == nil || a.Value == nil {
+		return new(big.Int)
+	}
+
+	return new(big.Int).Set(a.Value)
+}
+
+// Sign returns -1, 0 or +1 depending on the sign of the amount.
+func (a *Amount) Sign() int {
+	if a == nil || a.Value == nil {
+		return 0
+	}
+
+	return a.Value.Sign()
+}
+
+// Cmp compares two amounts regardless of their precisions and returns -1, 0 or +1.
+func (a *Amount) Cmp(b *Amount) int {
+	precision := max(a.precision(), b.precision())
+	return a.scaled(precision).Cmp(b.scaled(precision))
+}
+
+// Rescale returns the same amount expressed with the given precision. It returns
+// ErrAmountPrecision if the amount can not be expressed without a loss.
+func (a *Amount) Rescale(precision uint8) (*Amount, error) {
+	if precision >= a.precision() {
+		return &Amount{Value: a.scaled(precision), Precision: precision}, nil
+	}
+
+	q, r := new(big.Int).QuoRem(a.Int(), pow10(a.precision()-precision), new(big.Int))
+	if r.Sign() != 0 {
+		return nil, fmt.Errorf("%w: '%s' has more than %d fractional digits", ErrAmountPrecision, a, precision)
+	}
+
+	return &Amount{Value: q, Precision: precision}, nil
+}
+
+// Ceil returns the same amount expressed with the given precision, the fractions of
+// the minimal unit of the precision are rounded up.
+func (a *Amount) Ceil(precision uint8) *Amount {
+	if precision >= a.precision() {
+		return &Amount{Value: a.scaled(precision), Precision: precision}
+	}
+
+	q, r := new(big.Int).QuoRem(a.Int(), pow10(a.precision()-precision), new(big.Int))
+	if r.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+
+	return &Amount{Value: q, Precision: precision}
+}
+
+// String returns the decimal representation of the amount with exactly Precision
+// fractional digits.
+func (a *Amount) String() string {
+	v := a.Int()
+	if a.precision() == 0 {
+		return v.String()
+	}
+
+	digits := new(big.Int).Abs(v).String()
+	if pad := int(a.precision()) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+
+	point := len(digits) - int(a.precision())
+
+	sign := ""
+	if v.Sign() < 0 {
+		sign = "-"
+	}
+
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// MarshalJSON encodes the amount as a decimal string.
+func (a *Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.String())
+}
+
+// UnmarshalJSON decodes the amount from a decimal string. The precision is taken
+// from the number of the fractional digits. Plain JSON integers written before the
+// amounts were introduced are decoded with zero precision.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(data, `"`))
+
+	_, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 255 { //nolint:gomnd
+		return fmt.Errorf("%w: '%s'", ErrAmountInvalid, s)
+	}
+
+	parsed, err := ParseAmount(s, uint8(len(frac)))
+	if err != nil {
+		return err
+	}
+
+	*a = *parsed
+
+	return nil
+}
+
+func (a *Amount) precision() uint8 {
+	if a == nil {
+		return 0
+	}
+
+	return a.Precision
+}
+
+func (a *Amount) scaled(precision uint8) *big.Int {
+	return new(big.Int).Mul(a.Int(), pow10(precision-a.precision()))
+}
+
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil) //nolint:gomnd
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in        string
+		precision uint8
+		want      int64
+		wantErr   error
+	}{
+		{in: "12.345", precision: 3, want: 12345},
+		{in: "12.3", precision: 3, want: 12300},
+		{in: "12", precision: 3, want: 12000},
+		{in: ".5", precision: 1, want: 5},
+		{in: "-0.05", precision: 2, want: -5},
+		{in: "1.2300", precision: 2, want: 123},
+		{in: "12.3456", precision: 3, wantErr: ErrAmountPrecision},
+		{in: "1.5", precision: 0, wantErr: ErrAmountPrecision},
+		{in: "", precision: 2, wantErr: ErrAmountInvalid},
+		{in: "1.", precision: 2, wantErr: ErrAmountInvalid},
+		{in: "1,5", precision: 2, wantErr: ErrAmountInvalid},
+		{in: "1e5", precision: 2, wantErr: ErrAmountInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseAmount(tt.in, tt.precision)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, big.NewInt(tt.want), got.Value)
+			assert.Equal(t, tt.precision, got.Precision)
+		})
+	}
+}
+
+func TestAmount_String(t *testing.T) {
+	assert.Equal(t, "12.345", NewAmount(big.NewInt(12345), 3).String())
+	assert.Equal(t, "0.005", NewAmount(big.NewInt(5), 3).String())
+	assert.Equal(t, "-0.05", NewAmount(big.NewInt(-5), 2).String())
+	assert.Equal(t, "100", NewAmount(big.NewInt(100), 0).String())
+}
+
+func TestAmount_Rescale(t *testing.T) {
+	a, err := NewAmount(big.NewInt(1230), 3).Rescale(2)
+	assert.NoError(t, err)
+	assert.Equal(t, NewAmount(big.NewInt(123), 2), a)
+
+	_, err = NewAmount(big.NewInt(1235), 3).Rescale(2)
+	assert.ErrorIs(t, err, ErrAmountPrecision)
+
+	assert.Equal(t, 0, NewAmount(big.NewInt(5), 1).Cmp(NewAmount(big.NewInt(500), 3)))
+}
+
+func TestAmount_JSON(t *testing.T) {
+	data, err := json.Marshal(NewAmount(big.NewInt(12300), 3))
+	assert.NoError(t, err)
+	assert.Equal(t, `"12.300"`, string(data))
+
+	a := new(Amount)
+	assert.NoError(t, json.Unmarshal(data, a))
+	assert.Equal(t, NewAmount(big.NewInt(12300), 3), a)
+
+	// legacy integer format
+	assert.NoError(t, json.Unmarshal([]byte(`100`), a))
+	assert.Equal(t, NewAmount(big.NewInt(100), 0), a)
+}
@@ -20,18 +20,18 @@ const (
 
 // String returns a string representation of the Account.
 func (a Account) String() string {
-	switch a {
-	case AccountToken:
-		return "AccountToken"
-	case AccountTokenLocked:
-		return "AccountTokenLocked"
-	case AccountAllowed:
-		return "AccountAllowed"
-	case AccountAllowedLocked:
-		return "AccountAllowedLocked"
-	default:
-		return fmt.Sprintf("Unknown Account type: %d", a)
+	if t, ok := LookupAccount(a); ok {
+		return t.Name
 	}
+
+	return fmt.Sprintf("Unknown Account type: %d", a)
+}
+
+// RequiresAllowList reports whether only allow-listed (verified) addresses may hold
+// balances on the account.
+func (a Account) RequiresAllowList() bool {
+	t, ok := LookupAccount(a)
+	return ok && t.AllowListed
 }
 
 // Currency represents the name of the asset used to store balances in an account.
@@ -2,6 +2,7 @@ package model
 
 import (
 	"math/big"
+	"time"
 )
 
 // BalancesUpdate contains a set of updated balances.
@@ -21,14 +22,74 @@ func (bu BalancesUpdate) Validate() error {
 	return nil
 }
 
+// BalanceUpdates returns the updates themselves.
+func (bu BalancesUpdate) BalanceUpdates() BalancesUpdate {
+	return bu
+}
+
+// Addresses returns the distinct addresses of the updated balances in the order of
+// the updates.
+func (bu BalancesUpdate) Addresses() []Address {
+	var (
+		out  []Address
+		seen = make(map[Address]bool)
+	)
+
+	for _, upd := range bu {
+		if !seen[upd.Address] {
+			seen[upd.Address] = true
+			out = append(out, upd.Address)
+		}
+	}
+
+	return out
+}
+
 // -----------------------------------
 
+// Direction shows whether a balance update increases or decreases the balance.
+type Direction string
+
+// Constants for Directions.
+const (
+	DirectionCredit Direction = "credit"
+	DirectionDebit  Direction = "debit"
+)
+
 // BalanceUpdate contains information about a balance update for a specific account.
+// OldValue and NewValue are omitted for credits accumulated as delta records, since
+// the resulting balance is not read by such operations.
+//
+// Records written before the operation metadata was introduced have no Direction,
+// Operation, counterparty, transaction and memo fields.
 type BalanceUpdate struct {
 	Address    `validate:"required"`
 	Account    `validate:"required"`
 	Currency   `validate:"required"`
-	OldValue   *big.Int `validate:"required"`
-	NewValue   *big.Int `validate:"required"`
-	ValueDelta *big.Int `validate:"required"`
+	OldValue   *Amount `validate:"required_with=NewValue"`
+	NewValue   *Amount `validate:"required_with=OldValue"`
+	ValueDelta *Amount `validate:"required"` // Absolute value of the change.
+
+	Direction           Direction `json:",omitempty" validate:"omitempty,oneof=credit debit"`
+	Operation           Operation `json:",omitempty"` // Kind of the operation.
+	Counterparty        Address   `json:",omitempty"` // Address on the other side of a transfer.
+	CounterpartyAccount Account   `json:",omitempty"` // Account on the other side of a transfer.
+	TxID                string    `json:",omitempty"` // Identifier of the ledger transaction.
+	Timestamp           time.Time // Timestamp of the ledger transaction.
+	Memo                string    `json:",omitempty"` // Optional comment of the operation.
+}
+
+// SignedDelta returns the change of the balance in the minimal units of the currency:
+// positive for credits and negative for debits. For records without the Direction
+// it is derived from the old and the new values, and updates without them are
+// accumulated credits.
+func (u BalanceUpdate) SignedDelta() *big.Int {
+	switch {
+	case u.Direction == DirectionDebit:
+		return new(big.Int).Neg(u.ValueDelta.Int())
+	case u.Direction == DirectionCredit || u.OldValue == nil || u.NewValue == nil:
+		return u.ValueDelta.Int()
+	default:
+		return new(big.Int).Sub(u.NewValue.Int(), u.OldValue.Int())
+	}
 }
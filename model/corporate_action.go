@@ -0,0 +1,92 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// ErrCorporateActionInvalid is returned when a corporate action fails to validate.
+var ErrCorporateActionInvalid = errors.New("invalid corporate action")
+
+// Rounding is a rule of rounding the balances adjusted by a corporate action to the
+// minimal units of the currency.
+type Rounding string
+
+// Constants for Roundings.
+const (
+	RoundingDown   Rounding = "down"    // Toward zero, the fractions are dropped.
+	RoundingHalfUp Rounding = "half-up" // To the nearest unit, halves away from zero.
+)
+
+// CorporateAction multiplies all the balances of a currency by the ratio, e.g. 2/1
+// for a 2:1 split or 1/1000 for a redenomination. The actions of a currency are
+// numbered sequentially and applied to a balance lazily, when it is read, so an
+// action takes constant time regardless of the number of the balances. The values
+// derived from the balances, i.e. the registry of the holders, the snapshots, the
+// holds and the credit lines, are adjusted the same way, while the outflow limits are
+// not, since they are set by the regulator.
+type CorporateAction struct {
+	ID        string    `validate:"required"` // Unique identifier of the action.
+	Currency  Currency  `validate:"required"` // Currency whose balances are adjusted.
+	Seq       uint64    // Sequence number among the actions of the currency, assigned when stored.
+	Ratio     *big.Rat  `validate:"required"`           // Multiplier of the balances, positive.
+	Rounding  Rounding  `validate:"oneof=down half-up"` // Rounding of the adjusted balances.
+	Reason    string    `validate:"required"`           // Human-readable reason of the action.
+	Timestamp time.Time // Timestamp of the transaction which performed the action.
+	Remainder *big.Rat  `json:",omitempty"` // Rounding remainder of the totals of the holders, set in the accounting record.
+}
+
+// Apply returns the balance multiplied by the ratio and rounded to the minimal units
+// according to the rounding rule. The remainder of the rounding is not kept by the
+// balance, the remainder of the totals of the balances is recorded as Remainder.
+func (a *CorporateAction) Apply(val *big.Int) *big.Int {
+	exact := new(big.Rat).Mul(new(big.Rat).SetInt(val), a.Ratio)
+
+	abs := new(big.Rat).Abs(exact)
+	if a.Rounding == RoundingHalfUp {
+		abs.Add(abs, big.NewRat(1, 2)) //nolint:gomnd
+	}
+
+	out := new(big.Int).Quo(abs.Num(), abs.Denom())
+	if exact.Sign() < 0 {
+		out.Neg(out)
+	}
+
+	return out
+}
+
+// Реализация интерфейса model.Object.
+func (a *CorporateAction) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(a)
+}
+
+func (a *CorporateAction) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, a)
+}
+
+func (a *CorporateAction) Clone() Object {
+	na := *a
+	if a.Ratio != nil {
+		na.Ratio = new(big.Rat).Set(a.Ratio)
+	}
+
+	if a.Remainder != nil {
+		na.Remainder = new(big.Rat).Set(a.Remainder)
+	}
+
+	return &na
+}
+
+func (a *CorporateAction) Validate() error {
+	if err := NewValidator().Struct(a); err != nil {
+		return err
+	}
+
+	if a.Ratio.Sign() <= 0 {
+		return ErrCorporateActionInvalid
+	}
+
+	return nil
+}
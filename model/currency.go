@@ -0,0 +1,84 @@
+package model
+
+import (
+	"math/big"
+	"sync"
+)
+
+// CurrencyClass groups currencies, which share the outflow limits, e.g. the
+// stablecoins or the securities.
+type CurrencyClass string
+
+var currencies = struct {
+	sync.RWMutex
+	precisions      map[Currency]uint8
+	classes         map[Currency]CurrencyClass
+	classPrecisions map[CurrencyClass]uint8
+}{
+	precisions:      make(map[Currency]uint8),
+	classes:         make(map[Currency]CurrencyClass),
+	classPrecisions: make(map[CurrencyClass]uint8),
+}
+
+// RegisterCurrency declares the number of the fractional decimal digits of the
+// currency. Currencies which are not declared have zero precision.
+func RegisterCurrency(curr Currency, precision uint8) {
+	currencies.Lock()
+	defer currencies.Unlock()
+
+	currencies.precisions[curr] = precision
+}
+
+// RegisterCurrencyClass assigns the currency to the class. Currencies which are not
+// assigned belong to no class.
+func RegisterCurrencyClass(curr Currency, class CurrencyClass) {
+	currencies.Lock()
+	defer currencies.Unlock()
+
+	currencies.classes[curr] = class
+}
+
+// RegisterCurrencyClassPrecision declares the number of the fractional decimal digits
+// of the minimal units of the class, in which its limits and the amounts spent in all
+// its currencies are expressed. Classes which are not declared have zero precision.
+func RegisterCurrencyClassPrecision(class CurrencyClass, precision uint8) {
+	currencies.Lock()
+	defer currencies.Unlock()
+
+	currencies.classPrecisions[class] = precision
+}
+
+// Precision returns the number of the fractional decimal digits of the minimal units
+// of the class.
+func (c CurrencyClass) Precision() uint8 {
+	currencies.RLock()
+	defer currencies.RUnlock()
+
+	return currencies.classPrecisions[c]
+}
+
+// Class returns the class of the currency, empty if it belongs to no class.
+func (c Currency) Class() CurrencyClass {
+	currencies.RLock()
+	defer currencies.RUnlock()
+
+	return currencies.classes[c]
+}
+
+// Precision returns the number of the fractional decimal digits of the currency.
+func (c Currency) Precision() uint8 {
+	currencies.RLock()
+	defer currencies.RUnlock()
+
+	return currencies.precisions[c]
+}
+
+// Amount returns the amount of the minimal units of the currency.
+func (c Currency) Amount(v *big.Int) *Amount {
+	return NewAmount(v, c.Precision())
+}
+
+// ParseAmount parses a decimal string according to the precision of the currency.
+func (c Currency) ParseAmount(s string) (*Amount, error) {
+	return ParseAmount(s, c.Precision())
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"encoding/json"
+	"math/big"
+	"time"
+)
+
+// DistributionStatus represents a state of a pro-rata distribution.
+type DistributionStatus string
+
+// Constants for Distribution Statuses.
+const (
+	DistributionStatusActive    DistributionStatus = "active"
+	DistributionStatusCompleted DistributionStatus = "completed"
+)
+
+// Distribution is a pro-rata payment, e.g. a dividend or a coupon, from the source
+// address to all the holders of the reference currency. The holdings are taken as of
+// the snapshot made when the distribution starts, and the holders are credited in
+// batches in the order of the holder registry. The whole amount is moved from the
+// source to the escrow address of the distribution when it starts, and the batches
+// are paid from the escrow.
+//
+// The share of a holder is the difference between the rounded down cumulative shares
+// of the holders up to and including it and of the preceding ones:
+//
+//	share = Amount*(Counted+holding)/Supply - Amount*Counted/Supply
+//
+// so the shares never differ from the exact ones by a minimal unit or more, and they
+// sum up to Amount exactly regardless of how the holders are split into batches. The
+// cumulative holdings are capped by Supply, so the shares never exceed Amount, also
+// when the total of the holdings differs from Supply by the rounding of the corporate
+// actions performed before the distribution. The rest of the amount is returned from
+// the escrow to the source, when the distribution is completed. No corporate action of Currency or Reference can be performed while
+// the distribution is active.
+type Distribution struct {
+	ID          string             `validate:"required"` // Unique identifier of the distribution.
+	Source      Address            `validate:"required"` // Address paying the distribution.
+	Account     Account            `validate:"required"` // Account of the payments and the holdings.
+	Currency    Currency           `validate:"required"` // Currency of the payments.
+	Reference   Currency           `validate:"required"` // Currency, whose holders are paid.
+	Amount      *Amount            `validate:"required"` // Total amount to distribute.
+	Supply      *Amount            `validate:"required"` // Total holdings of the reference currency, except the source.
+	SnapshotSeq uint64             `validate:"gt=0"`     // Sequence number of the snapshot of the holdings.
+	Counted     *Amount            `validate:"required"` // Holdings of the holders credited so far.
+	Paid        *Amount            `validate:"required"` // Amount paid so far.
+	Returned    *Amount            // Amount returned to the source on completion, nil before.
+	Cursor      Address            // Last processed holder, empty if none.
+	Status      DistributionStatus `validate:"required"` // Stored status of the distribution.
+	Timestamp   time.Time          // Timestamp of the transaction which started the distribution.
+}
+
+// Escrow returns the address, which holds the amount of the distribution not paid yet.
+func (d *Distribution) Escrow() Address {
+	return Address("distribution:" + d.ID)
+}
+
+// Share returns the amount in the minimal units of Currency due to the holder with
+// given holding, provided the holders preceding it are already counted.
+func (d *Distribution) Share(holding *big.Int) *big.Int {
+	supply := d.Supply.Int()
+	if supply.Sign() <= 0 {
+		return new(big.Int)
+	}
+
+	counted := d.Counted.Int()
+	if counted.Cmp(supply) > 0 {
+		counted = supply
+	}
+
+	after := new(big.Int).Add(counted, holding)
+	if after.Cmp(supply) > 0 {
+		after = supply
+	}
+
+	// share = amount*after/supply - amount*counted/supply
+	share := new(big.Int).Quo(new(big.Int).Mul(d.Amount.Int(), after), supply)
+
+	return share.Sub(share, new(big.Int).Quo(new(big.Int).Mul(d.Amount.Int(), counted), supply))
+}
+
+// Реализация интерфейса model.Object.
+func (d *Distribution) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(d)
+}
+
+func (d *Distribution) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, d)
+}
+
+func (d *Distribution) Clone() Object {
+	nd := *d
+	nd.Amount = NewAmount(d.Amount.Int(), d.Amount.precision())
+	nd.Supply = NewAmount(d.Supply.Int(), d.Supply.precision())
+	nd.Counted = NewAmount(d.Counted.Int(), d.Counted.precision())
+	nd.Paid = NewAmount(d.Paid.Int(), d.Paid.precision())
+
+	if d.Returned != nil {
+		nd.Returned = NewAmount(d.Returned.Int(), d.Returned.precision())
+	}
+
+	return &nd
+}
+
+func (d *Distribution) Validate() error {
+	return NewValidator().Struct(d)
+}
@@ -0,0 +1,42 @@
+package model
+
+// ForcedTransferOrder contains the legal grounds for moving funds out of an
+// address without the owner's consent.
+type ForcedTransferOrder struct {
+	ID         string  `validate:"required"` // Unique identifier of the order, used as the notification ID.
+	Regulator  Address `validate:"required"` // Address of the regulator executing the order.
+	DocumentID string  `validate:"required"` // Reference to the court order or other legal document.
+	Reason     string  `validate:"required"` // Human-readable reason of the transfer.
+}
+
+// Реализация интерфейса model.Validator.
+func (o ForcedTransferOrder) Validate() error {
+	return NewValidator().Struct(o)
+}
+
+// -----------------------------------
+
+// ForcedTransfer is an accounting record of a transfer executed by a regulator.
+type ForcedTransfer struct {
+	ForcedTransferOrder
+	Updates BalancesUpdate `validate:"required"`
+}
+
+// Реализация интерфейса model.Validator.
+func (ft ForcedTransfer) Validate() error {
+	if err := NewValidator().Struct(ft); err != nil {
+		return err
+	}
+
+	return ft.Updates.Validate()
+}
+
+// BalanceUpdates returns the balance updates made by the transfer.
+func (ft ForcedTransfer) BalanceUpdates() BalancesUpdate {
+	return ft.Updates
+}
+
+// Addresses returns the addresses of the balances updated by the transfer.
+func (ft ForcedTransfer) Addresses() []Address {
+	return ft.Updates.Addresses()
+}
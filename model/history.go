@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// HistoryEntry is a balance update of an address together with the reference to
+// the notification which recorded it.
+type HistoryEntry struct {
+	Type   string        // Type of the notification.
+	ID     string        // Identifier of the notification.
+	Update BalanceUpdate // Update of the balance of the address.
+}
+
+// HistoryQuery describes a request of the balance updates of an address. The updates
+// are returned newest first.
+type HistoryQuery struct {
+	Address  Address   `validate:"required"`
+	Currency Currency  // Currency of the updates, empty for all the currencies.
+	Before   time.Time // Updates made before the time, zero for all the updates.
+	Bookmark string    // Bookmark returned with the previous page.
+	Limit    int       `validate:"gte=0"` // Size of the page, 0 for the default one.
+}
+
+// Validate checks the fields of the query.
+func (q HistoryQuery) Validate() error {
+	return NewValidator().Struct(q)
+}
+
+// HistoryPage is a page of the balance updates returned for a HistoryQuery.
+type HistoryPage struct {
+	Items    []*HistoryEntry
+	Bookmark string // Bookmark of the next page, empty for the last page.
+}
+
+// BalanceUpdater is implemented by the notification bodies which contain balance
+// updates. The updates are indexed in the history of their addresses.
+type BalanceUpdater interface {
+	BalanceUpdates() BalancesUpdate
+}
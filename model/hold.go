@@ -0,0 +1,68 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// HoldStatus represents a state of an authorization hold.
+type HoldStatus string
+
+// Constants for Hold Statuses.
+const (
+	HoldStatusActive   HoldStatus = "active"
+	HoldStatusCaptured HoldStatus = "captured"
+	HoldStatusVoided   HoldStatus = "voided"
+	HoldStatusExpired  HoldStatus = "expired"
+)
+
+// Hold is an authorization hold which reserves funds of an account for a merchant
+// until they are captured, voided or the hold expires.
+type Hold struct {
+	ID        string     `validate:"required"` // Unique identifier of the hold.
+	Address   Address    `validate:"required"` // Address whose funds are reserved.
+	Account   Account    `validate:"required"` // Account whose funds are reserved.
+	Currency  Currency   `validate:"required"` // Currency of the reserved funds.
+	Merchant  Address    `validate:"required"` // Address receiving the captured funds.
+	Amount    *Amount    `validate:"required"` // Amount which is still reserved.
+	Captured  *Amount    `validate:"required"` // Total amount captured so far.
+	ExpiresAt time.Time  `validate:"required"` // Moment the reserved funds are released.
+	Status    HoldStatus `validate:"required"` // Stored status of the hold.
+	Index     uint64     // Index of the last corporate action of Currency applied to the amounts.
+}
+
+// StatusAt returns the status of the hold at the moment. An active hold is
+// released automatically once it expires.
+func (h *Hold) StatusAt(now time.Time) HoldStatus {
+	if h.Status == HoldStatusActive && !now.Before(h.ExpiresAt) {
+		return HoldStatusExpired
+	}
+
+	return h.Status
+}
+
+// IsFinal reports whether the stored status of the hold can not change anymore.
+func (h *Hold) IsFinal() bool {
+	return h.Status != HoldStatusActive
+}
+
+// Реализация интерфейса model.Object.
+func (h *Hold) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(h)
+}
+
+func (h *Hold) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, h)
+}
+
+func (h *Hold) Clone() Object {
+	nh := *h
+	nh.Amount = NewAmount(h.Amount.Int(), h.Amount.precision())
+	nh.Captured = NewAmount(h.Captured.Int(), h.Captured.precision())
+
+	return &nh
+}
+
+func (h *Hold) Validate() error {
+	return NewValidator().Struct(h)
+}
@@ -0,0 +1,29 @@
+package model
+
+// Holding is a non-zero balance of an address.
+type Holding struct {
+	Address  Address
+	Account  Account
+	Currency Currency
+	Balance  *Amount
+}
+
+// HolderQuery describes a request of the holders of a currency on an account. The
+// holders are returned in ascending order of their addresses.
+type HolderQuery struct {
+	Currency Currency `validate:"required"`
+	Account  Account  `validate:"required"`
+	Bookmark string   // Bookmark returned with the previous page.
+	Limit    int      `validate:"gte=0"` // Size of the page, 0 for the default one.
+}
+
+// Validate checks the fields of the query.
+func (q HolderQuery) Validate() error {
+	return NewValidator().Struct(q)
+}
+
+// HolderPage is a page of the holdings returned for a HolderQuery.
+type HolderPage struct {
+	Items    []*Holding
+	Bookmark string // Bookmark of the next page, empty for the last page.
+}
@@ -0,0 +1,253 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+)
+
+// ErrJournalUnbalanced is returned when the debits of a journal entry do not equal
+// its credits.
+var ErrJournalUnbalanced = errors.New("journal entry is unbalanced")
+
+// Chart is a chart of accounts which maps the balances of the token to the accounts
+// of the double-entry journal. The balances of the holders are liabilities of the
+// issuer, so credits of the balances are credits of their journal accounts. The
+// tokens created or destroyed by an operation are balanced by the counter account
+// of the operation.
+type Chart struct {
+	// Accounts maps the account types to the journal accounts. Account types which
+	// are not mapped use their names.
+	Accounts map[Account]string
+
+	// Issuance is the system account which balances the tokens issued by deposits
+	// and redeemed by withdrawals, and by the operations without a counter account.
+	Issuance string `validate:"required"`
+
+	// Interest is the system account which balances the interest and demurrage
+	// accrued to the balances. If it is empty, Issuance is used.
+	Interest string
+
+	// Adjustments is the system account which balances the changes of the balances
+	// made by corporate actions. If it is empty, Issuance is used.
+	Adjustments string
+
+	// Fees is the journal account of the balances of FeeCollector, which collects the
+	// fees of the application.
+	Fees string
+
+	// FeeCollector is the address collecting the fees. If it or Fees is empty, the
+	// balances of the address are posted to the accounts of their types.
+	FeeCollector Address
+}
+
+// DefaultChart is the chart of accounts used when none is configured.
+var DefaultChart = Chart{
+	Issuance:    "Issuance",
+	Interest:    "Interest",
+	Adjustments: "Adjustments",
+	Fees:        "Fees",
+}
+
+// Account returns the journal account of the account type.
+func (c Chart) Account(acc Account) string {
+	if name, ok := c.Accounts[acc]; ok {
+		return name
+	}
+
+	return acc.String()
+}
+
+// Holder returns the journal account of the balance of the address.
+func (c Chart) Holder(addr Address, acc Account) string {
+	if c.Fees != "" && c.FeeCollector != "" && addr == c.FeeCollector {
+		return c.Fees
+	}
+
+	return c.Account(acc)
+}
+
+// Counter returns the system account which balances the tokens created or destroyed
+// by the operation.
+func (c Chart) Counter(op Operation) string {
+	if op == OperationAccrual && c.Interest != "" {
+		return c.Interest
+	}
+
+	return c.Issuance
+}
+
+// Adjustment returns the system account which balances the corporate actions.
+func (c Chart) Adjustment() string {
+	if c.Adjustments != "" {
+		return c.Adjustments
+	}
+
+	return c.Issuance
+}
+
+// System reports whether the journal account is a system account of the chart, which
+// does not hold the balances of the holders.
+func (c Chart) System(account string) bool {
+	return account == c.Issuance || account == c.Counter(OperationAccrual) || account == c.Adjustment()
+}
+
+// Posting is a debit or a credit of a journal account.
+type Posting struct {
+	Account   string    `validate:"required"` // Journal account.
+	Address   Address   `json:",omitempty"`   // Address of the holder, empty for the system accounts.
+	Currency  Currency  `validate:"required"`
+	Direction Direction `validate:"required,oneof=credit debit"`
+	Amount    *Amount   `validate:"required"` // Positive amount of the posting.
+}
+
+// JournalEntry is a record of the double-entry journal. The debits of every currency
+// equal the credits of that currency.
+type JournalEntry struct {
+	ID        string    `validate:"required"` // Unique identifier of the entry.
+	Timestamp time.Time // Timestamp of the ledger transaction.
+	Memo      string    `json:",omitempty"`
+	Postings  []Posting `validate:"required,min=2,dive"`
+}
+
+// Validate checks the fields of the entry and whether it is balanced.
+func (e *JournalEntry) Validate() error {
+	if err := NewValidator().Struct(e); err != nil {
+		return err
+	}
+
+	sums := make(map[Currency]*big.Int)
+	for _, p := range e.Postings {
+		if p.Amount.Sign() <= 0 {
+			return fmt.Errorf("%w: posting amount must be positive", ErrJournalUnbalanced)
+		}
+
+		if sums[p.Currency] == nil {
+			sums[p.Currency] = new(big.Int)
+		}
+
+		// debits - credits
+		if p.Direction == DirectionDebit {
+			sums[p.Currency].Add(sums[p.Currency], p.Amount.Int())
+		} else {
+			sums[p.Currency].Sub(sums[p.Currency], p.Amount.Int())
+		}
+	}
+
+	for curr, sum := range sums {
+		if sum.Sign() != 0 {
+			return fmt.Errorf("%w: %s differs by %s", ErrJournalUnbalanced, curr, curr.Amount(sum))
+		}
+	}
+
+	return nil
+}
+
+// Реализация интерфейса model.Object.
+func (e *JournalEntry) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(e)
+}
+
+func (e *JournalEntry) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, e)
+}
+
+func (e *JournalEntry) Clone() Object {
+	ne := *e
+	ne.Postings = make([]Posting, len(e.Postings))
+	for i, p := range e.Postings {
+		p.Amount = NewAmount(p.Amount.Int(), p.Amount.precision())
+		ne.Postings[i] = p
+	}
+
+	return &ne
+}
+
+// -----------------------------------
+
+// ReconcileLine compares the postings of a journal account of the holders with the
+// stored balances mapped to it.
+type ReconcileLine struct {
+	Account  string
+	Currency Currency
+	Journal  *Amount // Credits minus debits of the postings of the account.
+	Stored   *Amount // Sum of the stored balances mapped to the account.
+}
+
+// Matches reports whether the postings of the account match the stored balances.
+func (l ReconcileLine) Matches() bool {
+	return l.Journal.Cmp(l.Stored) == 0
+}
+
+// -----------------------------------
+
+// TrialBalanceLine contains the totals of the postings of a journal account.
+type TrialBalanceLine struct {
+	Account  string
+	Currency Currency
+	Debit    *Amount
+	Credit   *Amount
+}
+
+// TrialBalance lists the totals of all the journal accounts. The journal is
+// consistent if the total debits equal the total credits in every currency.
+type TrialBalance struct {
+	Lines []TrialBalanceLine
+}
+
+// Add adds the posting to the totals of its account.
+func (tb *TrialBalance) Add(p Posting) {
+	i := sort.Search(len(tb.Lines), func(i int) bool {
+		l := tb.Lines[i]
+		return l.Account > p.Account || (l.Account == p.Account && l.Currency >= p.Currency)
+	})
+
+	if i == len(tb.Lines) || tb.Lines[i].Account != p.Account || tb.Lines[i].Currency != p.Currency {
+		tb.Lines = append(tb.Lines, TrialBalanceLine{})
+		copy(tb.Lines[i+1:], tb.Lines[i:])
+		tb.Lines[i] = TrialBalanceLine{
+			Account:  p.Account,
+			Currency: p.Currency,
+			Debit:    p.Currency.Amount(nil),
+			Credit:   p.Currency.Amount(nil),
+		}
+	}
+
+	l := &tb.Lines[i]
+	if p.Direction == DirectionDebit {
+		l.Debit = NewAmount(new(big.Int).Add(l.Debit.Int(), p.Amount.Int()), l.Debit.Precision)
+	} else {
+		l.Credit = NewAmount(new(big.Int).Add(l.Credit.Int(), p.Amount.Int()), l.Credit.Precision)
+	}
+}
+
+// Totals returns the total debits and credits of every currency.
+func (tb *TrialBalance) Totals() map[Currency][2]*Amount {
+	out := make(map[Currency][2]*Amount)
+	for _, l := range tb.Lines {
+		t, ok := out[l.Currency]
+		if !ok {
+			t = [2]*Amount{l.Currency.Amount(nil), l.Currency.Amount(nil)}
+		}
+
+		t[0] = NewAmount(new(big.Int).Add(t[0].Int(), l.Debit.Int()), t[0].Precision)
+		t[1] = NewAmount(new(big.Int).Add(t[1].Int(), l.Credit.Int()), t[1].Precision)
+		out[l.Currency] = t
+	}
+
+	return out
+}
+
+// Balanced reports whether the total debits equal the total credits in every currency.
+func (tb *TrialBalance) Balanced() bool {
+	for _, t := range tb.Totals() {
+		if t[0].Cmp(t[1]) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// LedgerEntry is a link of the hash chain of the accounting ledger. Every stored
+// notification gets an entry, which includes the hash of the notification body and
+// the hash of the previous entry, so removal or alteration of any notification breaks
+// the chain.
+type LedgerEntry struct {
+	Seq      uint64 `validate:"gt=0"`     // Sequence number of the entry, starting from 1.
+	Type     string `validate:"required"` // Type of the notification.
+	ID       string `validate:"required"` // Identifier of the notification.
+	BodyHash string `validate:"required"` // Hash of the notification body.
+	PrevHash string // Hash of the previous entry, empty for the first one.
+	Hash     string `validate:"required"` // Hash of the entry.
+}
+
+// LedgerBreak describes the first broken link of the ledger hash chain.
+type LedgerBreak struct {
+	Seq    uint64 // Sequence number of the broken entry.
+	Type   string // Type of the notification of the entry.
+	ID     string // Identifier of the notification of the entry.
+	Reason string // Human-readable description of the inconsistency.
+}
+
+// HashLedgerBody returns the hash of the notification body encoded as JSON.
+func HashLedgerBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeHash calculates the hash of the entry from all its fields except the Hash.
+func (e *LedgerEntry) ComputeHash() string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		strconv.FormatUint(e.Seq, 10), //nolint:gomnd
+		e.Type,
+		e.ID,
+		e.BodyHash,
+		e.PrevHash,
+	}, "\n")))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Реализация интерфейса model.Object.
+func (e *LedgerEntry) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(e)
+}
+
+func (e *LedgerEntry) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, e)
+}
+
+func (e *LedgerEntry) Clone() Object {
+	ne := *e
+	return &ne
+}
+
+func (e *LedgerEntry) Validate() error {
+	return NewValidator().Struct(e)
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+)
+
+// ErrLimitNegative is returned when a limit is configured with a negative value.
+var ErrLimitNegative = errors.New("limit must not be negative")
+
+// LimitPeriod is used to aggregate outgoing amounts of an address over time.
+type LimitPeriod string
+
+// Constants for Limit Periods.
+const (
+	LimitPeriodDaily   LimitPeriod = "D"
+	LimitPeriodMonthly LimitPeriod = "M"
+)
+
+// LimitPeriods contains all supported limit periods in the order they are checked.
+var LimitPeriods = []LimitPeriod{LimitPeriodDaily, LimitPeriodMonthly}
+
+// Key returns a key of the calendar period (in UTC) the moment belongs to.
+// example: "D20240131" or "M202401"
+func (p LimitPeriod) Key(t time.Time) string {
+	t = t.UTC()
+	switch p {
+	case LimitPeriodDaily:
+		return string(p) + t.Format("20060102")
+	case LimitPeriodMonthly:
+		return string(p) + t.Format("200601")
+	default:
+		return string(p)
+	}
+}
+
+// Limit contains the outflow limits of an address or a currency. A nil or zero
+// value of the field means that the corresponding limit is not set.
+type Limit struct {
+	Single  *big.Int `json:",omitempty"` // Maximum amount of a single operation.
+	Daily   *big.Int `json:",omitempty"` // Maximum total amount per calendar day.
+	Monthly *big.Int `json:",omitempty"` // Maximum total amount per calendar month.
+}
+
+// PeriodLimit returns the limit of the period or nil if it is not set.
+func (l *Limit) PeriodLimit(p LimitPeriod) *big.Int {
+	var v *big.Int
+	switch p {
+	case LimitPeriodDaily:
+		v = l.Daily
+	case LimitPeriodMonthly:
+		v = l.Monthly
+	}
+
+	if !isLimitSet(v) {
+		return nil
+	}
+
+	return v
+}
+
+// AllowsSingle checks whether the amount does not exceed the single operation limit.
+func (l *Limit) AllowsSingle(amt *big.Int) bool {
+	return !isLimitSet(l.Single) || amt.Cmp(l.Single) <= 0
+}
+
+// Реализация интерфейса model.Object.
+func (l *Limit) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(l)
+}
+
+func (l *Limit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, l)
+}
+
+func (l *Limit) Clone() Object {
+	return &Limit{
+		Single:  cloneInt(l.Single),
+		Daily:   cloneInt(l.Daily),
+		Monthly: cloneInt(l.Monthly),
+	}
+}
+
+func (l *Limit) Validate() error {
+	for _, v := range []*big.Int{l.Single, l.Daily, l.Monthly} {
+		if v != nil && v.Sign() < 0 {
+			return ErrLimitNegative
+		}
+	}
+
+	return nil
+}
+
+func isLimitSet(v *big.Int) bool {
+	return v != nil && v.Sign() > 0
+}
+
+func cloneInt(v *big.Int) *big.Int {
+	if v == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(v)
+}
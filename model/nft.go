@@ -0,0 +1,81 @@
+package model
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NFT is a non-fungible token, a unique token of a collection owned by an address.
+// Unlike the fungible balances, it is identified by the collection and the token ID.
+type NFT struct {
+	Collection   string    `validate:"required,excludes=/"` // Collection the token belongs to.
+	TokenID      string    `validate:"required,excludes=/"` // Identifier of the token within the collection.
+	Owner        Address   `validate:"required"`            // Address owning the token.
+	MetadataURI  string    // Location of the metadata of the token.
+	MetadataHash string    // Hash of the metadata, which binds the token to its content.
+	MintedAt     time.Time // Timestamp of the transaction which minted the token.
+}
+
+// Реализация интерфейса model.Object.
+func (n *NFT) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(n)
+}
+
+func (n *NFT) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, n)
+}
+
+func (n *NFT) Clone() Object {
+	nn := *n
+	return &nn
+}
+
+func (n *NFT) Validate() error {
+	return NewValidator().Struct(n)
+}
+
+// NFTPage is a page of the non-fungible tokens.
+type NFTPage struct {
+	Items    []*NFT
+	Bookmark string // Bookmark of the next page, empty for the last page.
+}
+
+// -----------------------------------
+
+// NFTEvent is an accounting record of the mint, the transfer or the burn of a
+// non-fungible token. The previous owner is empty for a mint, and the new one is
+// empty for a burn.
+type NFTEvent struct {
+	Collection   string  `validate:"required"` // Collection of the token.
+	TokenID      string  `validate:"required"` // Identifier of the token within the collection.
+	From         Address // Previous owner of the token.
+	To           Address // New owner of the token.
+	MetadataURI  string  // Location of the metadata of the token.
+	MetadataHash string  // Hash of the metadata of the token.
+}
+
+// Реализация интерфейса model.Validator.
+func (e NFTEvent) Validate() error {
+	return NewValidator().Struct(e)
+}
+
+// Addresses returns the previous and the new owners of the token.
+func (e NFTEvent) Addresses() []Address {
+	var out []Address
+	for _, addr := range []Address{e.From, e.To} {
+		if addr != "" {
+			out = append(out, addr)
+		}
+	}
+
+	return out
+}
+
+// NFTEventID returns the identifier of the notification of the event of the token in
+// the transaction. The sequence number tells apart the events of the same type of the
+// token in one transaction, e.g. two transfers, and starts from one.
+func NFTEventID(txID, collection, tokenID string, seq int) string {
+	return strings.Join([]string{txID, collection, tokenID, strconv.Itoa(seq)}, ":")
+}
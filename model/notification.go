@@ -2,6 +2,7 @@ package model
 
 import (
 	"encoding/json"
+	"time"
 
 	"github.com/jinzhu/copier"
 )
@@ -17,6 +18,81 @@ type Notification[T any] struct {
 	Body T      `validate:"required"` // Тело уведомления.
 }
 
+// Типы уведомлений, которые формируются сервисным слоем.
+const (
+	// NotificationTypeBalancesUpdate обозначает все изменения балансов, выполненные
+	// в рамках одной транзакции.
+	NotificationTypeBalancesUpdate = "BalancesUpdate"
+
+	// NotificationTypeForcedTransfer обозначает перевод средств, выполненный регулятором
+	// без согласия владельца.
+	NotificationTypeForcedTransfer = "ForcedTransfer"
+
+	// NotificationTypeReversal обозначает отмену ранее выполненной операции.
+	NotificationTypeReversal = "Reversal"
+
+	// NotificationTypeAccrual обозначает начисление процентов или платы за хранение
+	// на баланс.
+	NotificationTypeAccrual = "Accrual"
+
+	// NotificationTypeCorporateAction обозначает корпоративное действие, изменяющее
+	// все балансы валюты.
+	NotificationTypeCorporateAction = "CorporateAction"
+
+	// NotificationTypeNFTMint обозначает выпуск невзаимозаменяемого токена.
+	NotificationTypeNFTMint = "NFTMint"
+
+	// NotificationTypeNFTTransfer обозначает передачу невзаимозаменяемого токена.
+	NotificationTypeNFTTransfer = "NFTTransfer"
+
+	// NotificationTypeNFTBurn обозначает сжигание невзаимозаменяемого токена.
+	NotificationTypeNFTBurn = "NFTBurn"
+)
+
+// RawNotification это уведомление, тело которого не декодировано. Такие уведомления
+// возвращаются при чтении бухгалтерской книги, поскольку в ней хранятся уведомления
+// разных типов.
+type RawNotification = Notification[json.RawMessage]
+
+// DecodeNotification декодирует тело уведомления в структуру типа T, который должен
+// быть зарегистрирован для типа уведомления функцией RegisterNotification.
+func DecodeNotification[T any](n *RawNotification) (*Notification[T], error) {
+	if err := CheckNotificationType[T](n.Type); err != nil {
+		return nil, err
+	}
+
+	out := &Notification[T]{ID: n.ID, Type: n.Type}
+	if err := json.Unmarshal(n.Body, &out.Body); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// NotificationQuery описывает условия отбора уведомлений из бухгалтерской книги.
+// Пустые поля не ограничивают выборку. Уведомления, отобранные по адресу или по
+// интервалу времени, возвращаются в порядке их записи, а отобранные только по типу -
+// в порядке идентификаторов.
+type NotificationQuery struct {
+	Type     string    // Тип уведомлений.
+	Address  Address   // Адрес, балансы которого изменены операцией.
+	From     time.Time // Начало интервала времени транзакций, включительно.
+	To       time.Time `validate:"omitempty,gtfield=From"` // Конец интервала, не включительно.
+	Bookmark string    // Закладка, полученная вместе с предыдущей страницей.
+	Limit    int       `validate:"gte=0"` // Размер страницы, 0 - размер по умолчанию.
+}
+
+// Validate проверяет поля запроса.
+func (q NotificationQuery) Validate() error {
+	return NewValidator().Struct(q)
+}
+
+// NotificationPage это страница уведомлений, отобранных по запросу NotificationQuery.
+type NotificationPage struct {
+	Items    []*RawNotification // Уведомления страницы.
+	Bookmark string             // Закладка следующей страницы, пустая для последней страницы.
+}
+
 // Реализация интерфейса model.Object.
 
 func (n *Notification[T]) MarshalBinary() (data []byte, err error) {
@@ -0,0 +1,178 @@
+package model
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Ошибки реестра типов уведомлений.
+var (
+	// ErrNotificationUnknown возвращается для уведомлений незарегистрированного типа.
+	ErrNotificationUnknown = errors.New("unknown notification type")
+
+	// ErrNotificationRegistered возвращается при повторной регистрации типа уведомлений.
+	ErrNotificationRegistered = errors.New("notification type is already registered")
+
+	// ErrNotificationBody возвращается, если тип тела уведомления не совпадает с
+	// зарегистрированным для его типа.
+	ErrNotificationBody = errors.New("notification body type mismatch")
+)
+
+// Addresser реализуется телами уведомлений, которые затрагивают балансы адресов.
+// По этим адресам уведомления индексируются в хранилище.
+type Addresser interface {
+	Addresses() []Address
+}
+
+// notificationKind описывает зарегистрированный тип уведомлений.
+type notificationKind struct {
+	body     reflect.Type
+	validate func(body any) error
+}
+
+var notifications = struct {
+	sync.RWMutex
+	kinds map[string]notificationKind
+}{
+	kinds: make(map[string]notificationKind),
+}
+
+func init() {
+	_ = RegisterNotification[BalancesUpdate](NotificationTypeBalancesUpdate, nil)
+	_ = RegisterNotification[ForcedTransfer](NotificationTypeForcedTransfer, nil)
+	_ = RegisterNotification[Reversal](NotificationTypeReversal, nil)
+	_ = RegisterNotification[Accrual](NotificationTypeAccrual, nil)
+	_ = RegisterNotification[CorporateAction](NotificationTypeCorporateAction, func(a CorporateAction) error {
+		return a.Validate()
+	})
+	_ = RegisterNotification[NFTEvent](NotificationTypeNFTMint, nil)
+	_ = RegisterNotification[NFTEvent](NotificationTypeNFTTransfer, nil)
+	_ = RegisterNotification[NFTEvent](NotificationTypeNFTBurn, nil)
+}
+
+// RegisterNotification регистрирует тип уведомлений typ с телом типа T. Функция
+// validate проверяет тело уведомления перед сохранением; если она не задана, тело
+// проверяется методом Validate, когда T реализует интерфейс Validator. Регистрация
+// выполняется при инициализации приложения, после чего для сохранения и чтения
+// уведомлений нового типа не требуется код в хранилище.
+func RegisterNotification[T any](typ string, validate func(body T) error) error {
+	if typ == "" {
+		return fmt.Errorf("%w: empty type", ErrNotificationUnknown)
+	}
+
+	notifications.Lock()
+	defer notifications.Unlock()
+
+	if _, ok := notifications.kinds[typ]; ok {
+		return fmt.Errorf("%w: %s", ErrNotificationRegistered, typ)
+	}
+
+	kind := notificationKind{body: reflect.TypeOf((*T)(nil)).Elem()}
+	if validate != nil {
+		kind.validate = func(body any) error {
+			return validate(body.(T)) //nolint:forcetypeassert
+		}
+	}
+
+	notifications.kinds[typ] = kind
+
+	return nil
+}
+
+// NotificationTypes возвращает все зарегистрированные типы уведомлений в алфавитном порядке.
+func NotificationTypes() []string {
+	notifications.RLock()
+	defer notifications.RUnlock()
+
+	out := make([]string, 0, len(notifications.kinds))
+	for typ := range notifications.kinds {
+		out = append(out, typ)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
+
+// CheckNotificationType проверяет, что тип уведомлений typ зарегистрирован с телом типа T.
+func CheckNotificationType[T any](typ string) error {
+	kind, err := lookupNotification(typ)
+	if err != nil {
+		return err
+	}
+
+	if body := reflect.TypeOf((*T)(nil)).Elem(); body != kind.body {
+		return fmt.Errorf("%w: %s has body %s, not %s", ErrNotificationBody, typ, kind.body, body)
+	}
+
+	return nil
+}
+
+// DecodeNotificationBody декодирует тело уведомления в структуру зарегистрированного
+// для его типа и проверяет ее. Возвращается значение тела, а не указатель на него.
+func DecodeNotificationBody(n *RawNotification) (any, error) {
+	kind, err := lookupNotification(n.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	ptr := reflect.New(kind.body)
+	if err := json.Unmarshal(n.Body, ptr.Interface()); err != nil {
+		return nil, err
+	}
+
+	body := ptr.Elem().Interface()
+	if err := kind.check(body); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// EncodeNotification преобразует уведомление зарегистрированного типа в уведомление
+// с недекодированным телом, проверяя тело уведомления.
+func EncodeNotification[T any](n Notification[T]) (*RawNotification, error) {
+	if err := CheckNotificationType[T](n.Type); err != nil {
+		return nil, err
+	}
+
+	kind, _ := lookupNotification(n.Type)
+	if err := kind.check(n.Body); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(n.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RawNotification{ID: n.ID, Type: n.Type, Body: body}, nil
+}
+
+func lookupNotification(typ string) (notificationKind, error) {
+	notifications.RLock()
+	defer notifications.RUnlock()
+
+	kind, ok := notifications.kinds[typ]
+	if !ok {
+		return kind, fmt.Errorf("%w: %s", ErrNotificationUnknown, typ)
+	}
+
+	return kind, nil
+}
+
+func (k notificationKind) check(body any) error {
+	if k.validate != nil {
+		return k.validate(body)
+	}
+
+	if validator, ok := body.(Validator); ok {
+		return validator.Validate()
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+// OutboxEntry is a record of the notification outbox. Entries reference the stored
+// notifications in the order of their storing and are delivered to the consumers of
+// the off-chain systems.
+type OutboxEntry struct {
+	Seq  uint64 `validate:"gt=0"`     // Sequence number of the entry, starting from 1.
+	Type string `validate:"required"` // Type of the notification.
+	ID   string `validate:"required"` // Identifier of the notification.
+}
+
+// Реализация интерфейса model.Object.
+func (e *OutboxEntry) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(e)
+}
+
+func (e *OutboxEntry) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, e)
+}
+
+func (e *OutboxEntry) Clone() Object {
+	ne := *e
+	return &ne
+}
+
+func (e *OutboxEntry) Validate() error {
+	return NewValidator().Struct(e)
+}
+
+// -----------------------------------
+
+// OutboxConsumer is a consumer of the notification outbox with its delivery cursor.
+type OutboxConsumer struct {
+	Name   string `validate:"required"` // Unique name of the consumer.
+	Cursor uint64 // Sequence number of the last acknowledged entry.
+}
+
+// Реализация интерфейса model.Object.
+func (c *OutboxConsumer) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(c)
+}
+
+func (c *OutboxConsumer) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+func (c *OutboxConsumer) Clone() Object {
+	nc := *c
+	return &nc
+}
+
+func (c *OutboxConsumer) Validate() error {
+	return NewValidator().Struct(c)
+}
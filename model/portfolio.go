@@ -0,0 +1,17 @@
+package model
+
+// Position is a balance of a currency on an account of an address.
+type Position struct {
+	Account  Account
+	Currency Currency
+	Balance  *Amount
+}
+
+// Portfolio is the full position of an address: its non-zero balances on all the
+// declared accounts in ascending order of currencies and accounts, and the totals
+// of the balances per currency.
+type Portfolio struct {
+	Address   Address
+	Positions []Position
+	Totals    map[Currency]*Amount
+}
@@ -0,0 +1,34 @@
+package model
+
+// Reversal is an accounting record of the operation undoing the balance updates of
+// an original notification.
+type Reversal struct {
+	OriginalType string         `validate:"required"` // Type of the reversed notification.
+	OriginalID   string         `validate:"required"` // Identifier of the reversed notification.
+	Updates      BalancesUpdate `validate:"required"` // Balance updates made by the reversal.
+}
+
+// Реализация интерфейса model.Validator.
+func (r Reversal) Validate() error {
+	if err := NewValidator().Struct(r); err != nil {
+		return err
+	}
+
+	return r.Updates.Validate()
+}
+
+// BalanceUpdates returns the balance updates made by the reversal.
+func (r Reversal) BalanceUpdates() BalancesUpdate {
+	return r.Updates
+}
+
+// Addresses returns the addresses of the balances updated by the reversal.
+func (r Reversal) Addresses() []Address {
+	return r.Updates.Addresses()
+}
+
+// ReversalID returns the identifier of the notification reversing the original one.
+// It is derived from the original notification, so an operation can be reversed once.
+func ReversalID(originalType, originalID string) string {
+	return originalType + ":" + originalID
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Snapshot is a point in time, as of which the balances can be retrieved later. The
+// balances are not copied when the snapshot is taken: the value of a balance is
+// captured right before its first change after the snapshot.
+type Snapshot struct {
+	ID        string    `validate:"required"` // Unique identifier of the snapshot.
+	Seq       uint64    `validate:"gt=0"`     // Sequence number of the snapshot, starting from 1.
+	Timestamp time.Time // Timestamp of the transaction which took the snapshot.
+}
+
+// Реализация интерфейса model.Object.
+func (s *Snapshot) MarshalBinary() (data []byte, err error) {
+	return json.Marshal(s)
+}
+
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, s)
+}
+
+func (s *Snapshot) Clone() Object {
+	ns := *s
+	return &ns
+}
+
+func (s *Snapshot) Validate() error {
+	return NewValidator().Struct(s)
+}
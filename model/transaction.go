@@ -0,0 +1,41 @@
+package model
+
+import (
+	"context"
+	"time"
+)
+
+// Transaction contains information about the ledger transaction in which the
+// operations are executed.
+type Transaction struct {
+	ID        string    // Identifier of the transaction.
+	Timestamp time.Time // Timestamp of the transaction, set by the client.
+}
+
+type (
+	transactionKey struct{}
+	memoKey        struct{}
+)
+
+// ContextWithTransaction returns a copy of ctx carrying the transaction information.
+func ContextWithTransaction(ctx context.Context, tx Transaction) context.Context {
+	return context.WithValue(ctx, transactionKey{}, tx)
+}
+
+// TransactionFromContext returns the transaction information stored in ctx, if any.
+func TransactionFromContext(ctx context.Context) (Transaction, bool) {
+	tx, ok := ctx.Value(transactionKey{}).(Transaction)
+	return tx, ok
+}
+
+// ContextWithMemo returns a copy of ctx carrying the comment of the operation, which
+// is recorded in the balance updates.
+func ContextWithMemo(ctx context.Context, memo string) context.Context {
+	return context.WithValue(ctx, memoKey{}, memo)
+}
+
+// MemoFromContext returns the comment of the operation stored in ctx, if any.
+func MemoFromContext(ctx context.Context) string {
+	memo, _ := ctx.Value(memoKey{}).(string)
+	return memo
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math"
 	"math/big"
 
 	"github.com/btcsuite/btcutil/base58"
@@ -34,5 +35,12 @@ func NewValidator() (v *validator.Validate) {
 		panic(err)
 	}
 
+	if err := v.RegisterValidation("gt0_decimal", func(fl validator.FieldLevel) bool {
+		a, err := ParseAmount(fl.Field().String(), math.MaxUint8)
+		return err == nil && a.Sign() > 0
+	}); err != nil {
+		panic(err)
+	}
+
 	return
 }
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// Access service errors.
+var (
+	// ErrAccessRepository represents a generic error related to the repository operations.
+	ErrAccessRepository = errors.New("access repository error")
+
+	// ErrAccessForbidden is returned when the caller has no rights to perform the operation.
+	ErrAccessForbidden = errors.New("access is forbidden")
+)
+
+// Access is a struct that provides methods to restrict operations of addresses.
+//
+//go:generate ifacemaker -f access.go -o controller/access.go -i Access -s Access -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/access.go -destination controller/mock/mock_access.go
+type Access struct {
+	repository.Access
+}
+
+// Freeze method forbids any balance operations of the address except forced
+// transfers. It can be executed only by an address holding the model.RoleRegulator role.
+func (as *Access) Freeze(ctx context.Context, regulator, addr model.Address) error {
+	if err := as.checkRegulator(ctx, regulator); err != nil {
+		return err
+	}
+
+	if err := as.Access.Freeze(ctx, addr); err != nil {
+		return as.wrap(ErrAccessRepository, err)
+	}
+
+	return nil
+}
+
+// Unfreeze method allows balance operations of the previously frozen address.
+// It can be executed only by an address holding the model.RoleRegulator role.
+func (as *Access) Unfreeze(ctx context.Context, regulator, addr model.Address) error {
+	if err := as.checkRegulator(ctx, regulator); err != nil {
+		return err
+	}
+
+	if err := as.Access.Unfreeze(ctx, addr); err != nil {
+		return as.wrap(ErrAccessRepository, err)
+	}
+
+	return nil
+}
+
+// IsFrozen reports whether the address is frozen.
+func (as *Access) IsFrozen(ctx context.Context, addr model.Address) (bool, error) {
+	frozen, err := as.Access.IsFrozen(ctx, addr)
+	if err != nil {
+		return false, as.wrap(ErrAccessRepository, err)
+	}
+
+	return frozen, nil
+}
+
+func (as *Access) checkRegulator(ctx context.Context, addr model.Address) error {
+	ok, err := as.Access.HasRole(ctx, addr, model.RoleRegulator)
+	if err != nil {
+		return as.wrap(ErrAccessRepository, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s is not a regulator", ErrAccessForbidden, addr)
+	}
+
+	return nil
+}
+
+func (as *Access) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
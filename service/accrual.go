@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// Accrual service errors.
+var (
+	// ErrAccrualRepository represents a generic error related to the repository operations.
+	ErrAccrualRepository = errors.New("accrual repository error")
+
+	// ErrAccrualForbidden is returned when the caller has no rights to configure rates.
+	ErrAccrualForbidden = errors.New("accrual configuration is forbidden")
+
+	// ErrAccrualInvalid is returned when the accrual rate fails to validate.
+	ErrAccrualInvalid = errors.New("invalid accrual rate")
+)
+
+// Accrual is a struct that provides methods to configure the interest and demurrage
+// rates of currencies. The rates are applied by the Balance service.
+//
+//go:generate ifacemaker -f accrual.go -o controller/accrual.go -i Accrual -s Accrual -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/accrual.go -destination controller/mock/mock_accrual.go
+type Accrual struct {
+	repository.Accrual
+
+	// Access is used to check that the caller holds the model.RoleRegulator role.
+	Access repository.Access
+}
+
+// SetRate appends the accrual rate to the history of the Currency. A nil rate stops the
+// accrual. The change is not retroactive: the rate is effective from the later of its
+// Since, the timestamp of the transaction and the moment the previous rate is
+// effective from, and the balances are adjusted at the previous rates till then.
+func (as *Accrual) SetRate(
+	ctx context.Context,
+	regulator model.Address,
+	curr model.Currency,
+	rate *model.AccrualRate,
+) error {
+	if rate != nil {
+		if err := rate.Validate(); err != nil {
+			return as.wrap(ErrAccrualInvalid, err)
+		}
+	}
+
+	ok, err := as.Access.HasRole(ctx, regulator, model.RoleRegulator)
+	if err != nil {
+		return as.wrap(ErrAccrualRepository, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s is not a regulator", ErrAccrualForbidden, regulator)
+	}
+
+	last, err := as.Accrual.LoadRate(ctx, curr)
+	if err != nil {
+		return as.wrap(ErrAccrualRepository, err)
+	}
+
+	if rate == nil {
+		if last == nil || last.Rate.Sign() == 0 {
+			return nil
+		}
+
+		// the zero rate stops the accrual
+		rate = &model.AccrualRate{Rate: new(big.Rat), Period: last.Period}
+	}
+
+	next := *rate
+	if tx, ok := model.TransactionFromContext(ctx); ok && next.Since.Before(tx.Timestamp) {
+		next.Since = tx.Timestamp
+	}
+
+	if last != nil && next.Since.Before(last.Since) {
+		next.Since = last.Since
+	}
+
+	if err := as.Accrual.AppendRate(ctx, curr, &next); err != nil {
+		return as.wrap(ErrAccrualRepository, err)
+	}
+
+	return nil
+}
+
+// GetRate returns the accrual rate of the Currency in force. If no rate is configured
+// or the accrual is stopped, nil is returned.
+func (as *Accrual) GetRate(ctx context.Context, curr model.Currency) (*model.AccrualRate, error) {
+	rate, err := as.Accrual.LoadRate(ctx, curr)
+	if err != nil {
+		return nil, as.wrap(ErrAccrualRepository, err)
+	}
+
+	if rate == nil || rate.Rate.Sign() == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	return rate, nil
+}
+
+func (as *Accrual) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
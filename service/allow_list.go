@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// AllowList service errors.
+var (
+	// ErrAllowListRepository represents a generic error related to the repository operations.
+	ErrAllowListRepository = errors.New("allow-list repository error")
+
+	// ErrAllowListForbidden is returned when the caller has no rights to modify the allow-list.
+	ErrAllowListForbidden = errors.New("allow-list modification is forbidden")
+
+	// ErrAllowListInvalidEntry is returned when the allow-list entry fails to validate.
+	ErrAllowListInvalidEntry = errors.New("invalid allow-list entry")
+)
+
+// AllowList is a struct that provides methods to maintain the registry of verified
+// addresses. The registry is used by the Balance service to restrict the holders of
+// the allow-listed accounts.
+//
+//go:generate ifacemaker -f allow_list.go -o controller/allow_list.go -i AllowList -s AllowList -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/allow_list.go -destination controller/mock/mock_allow_list.go
+type AllowList struct {
+	repository.AllowList
+
+	// Access is used to check that the caller holds the model.RoleVerifier role.
+	Access repository.Access
+}
+
+// Verify adds the address to the allow-list or updates its verification level
+// and expiry. It can be executed only by an address holding the model.RoleVerifier role.
+func (as *AllowList) Verify(
+	ctx context.Context,
+	verifier model.Address,
+	entry *model.AllowListEntry,
+) error {
+	if err := entry.Validate(); err != nil {
+		return as.wrap(ErrAllowListInvalidEntry, err)
+	}
+
+	if err := as.checkVerifier(ctx, verifier); err != nil {
+		return err
+	}
+
+	if err := as.AllowList.Save(ctx, entry); err != nil {
+		return as.wrap(ErrAllowListRepository, err)
+	}
+
+	return nil
+}
+
+// Revoke removes the address from the allow-list. It can be executed only by an
+// address holding the model.RoleVerifier role.
+func (as *AllowList) Revoke(ctx context.Context, verifier, addr model.Address) error {
+	if err := as.checkVerifier(ctx, verifier); err != nil {
+		return err
+	}
+
+	if err := as.AllowList.Delete(ctx, addr); err != nil {
+		return as.wrap(ErrAllowListRepository, err)
+	}
+
+	return nil
+}
+
+// Entry returns the allow-list entry of the address or nil if the address has
+// never been verified.
+func (as *AllowList) Entry(ctx context.Context, addr model.Address) (*model.AllowListEntry, error) {
+	entry, err := as.AllowList.Load(ctx, addr)
+	if err != nil {
+		return nil, as.wrap(ErrAllowListRepository, err)
+	}
+
+	return entry, nil
+}
+
+func (as *AllowList) checkVerifier(ctx context.Context, addr model.Address) error {
+	ok, err := as.Access.HasRole(ctx, addr, model.RoleVerifier)
+	if err != nil {
+		return as.wrap(ErrAllowListRepository, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s is not a verifier", ErrAllowListForbidden, addr)
+	}
+
+	return nil
+}
+
+func (as *AllowList) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
@@ -2,11 +2,15 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"time"
 
 	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/service/controller"
 	"github.com/anoideaopen/token/storage/repository"
 )
 
@@ -20,14 +24,142 @@ var (
 
 	// ErrBalanceInsufficientFunds indicates insufficient funds for processing.
 	ErrBalanceInsufficientFunds = errors.New("insufficient funds to process")
+
+	// ErrBalanceAddressFrozen is returned when an operation touches a frozen address.
+	ErrBalanceAddressFrozen = errors.New("address is frozen")
+
+	// ErrBalanceForbidden is returned when the caller has no rights to perform the operation.
+	ErrBalanceForbidden = errors.New("operation is forbidden")
+
+	// ErrBalanceInvalidOrder is returned when a forced transfer order fails to validate.
+	ErrBalanceInvalidOrder = errors.New("invalid forced transfer order")
+
+	// ErrBalanceLimitExceeded is returned when an operation exceeds the outflow limits.
+	ErrBalanceLimitExceeded = errors.New("limit exceeded")
+
+	// ErrBalanceNoTransaction is returned when an operation requires the transaction
+	// information, but the context does not carry it.
+	ErrBalanceNoTransaction = errors.New("transaction information is missing")
+
+	// ErrBalanceNotAllowListed is returned when an address which is not verified
+	// receives funds on an allow-listed account.
+	ErrBalanceNotAllowListed = errors.New("address is not allow-listed")
+
+	// ErrBalanceHoldNotFound is returned when the authorization hold does not exist.
+	ErrBalanceHoldNotFound = errors.New("hold not found")
+
+	// ErrBalanceHoldExists is returned when an authorization hold with the same
+	// identifier already exists.
+	ErrBalanceHoldExists = errors.New("hold already exists")
+
+	// ErrBalanceInvalidHold is returned when the authorization hold expires before
+	// it is made or reserves the funds for their owner.
+	ErrBalanceInvalidHold = errors.New("invalid hold")
+
+	// ErrBalanceHoldNotActive is returned when the authorization hold is captured,
+	// voided or expired.
+	ErrBalanceHoldNotActive = errors.New("hold is not active")
+
+	// ErrBalanceOperationNotFound is returned when the operation to reverse does not exist.
+	ErrBalanceOperationNotFound = errors.New("operation not found")
+
+	// ErrBalanceAlreadyReversed is returned when the operation has already been reversed.
+	ErrBalanceAlreadyReversed = errors.New("operation already reversed")
+
+	// ErrBalanceNotReversible is returned when the operation does not update balances
+	// or is a reversal itself.
+	ErrBalanceNotReversible = errors.New("operation is not reversible")
+
+	// ErrBalanceOperationNotPermitted is returned when the account is not declared
+	// or does not permit the operation.
+	ErrBalanceOperationNotPermitted = errors.New("operation is not permitted on the account")
+
+	// ErrBalanceSnapshotNotFound is returned when the balance snapshot does not exist.
+	ErrBalanceSnapshotNotFound = errors.New("snapshot not found")
+
+	// ErrBalanceSnapshotExists is returned when a balance snapshot with the same
+	// identifier already exists.
+	ErrBalanceSnapshotExists = errors.New("snapshot already exists")
+
+	// ErrBalanceDistributionNotFound is returned when the distribution does not exist.
+	ErrBalanceDistributionNotFound = errors.New("distribution not found")
+
+	// ErrBalanceDistributionExists is returned when a distribution with the same
+	// identifier already exists.
+	ErrBalanceDistributionExists = errors.New("distribution already exists")
+
+	// ErrBalanceDistributionCompleted is returned when all the holders of the
+	// distribution are already credited.
+	ErrBalanceDistributionCompleted = errors.New("distribution is completed")
+
+	// ErrBalanceNoHolders is returned when there are no holdings to distribute to.
+	ErrBalanceNoHolders = errors.New("no holders to distribute to")
 )
 
+// DistributionBatchLimit is the default number of holders processed by a batch of
+// a distribution.
+const DistributionBatchLimit = 100
+
 // Balance is a struct that provides methods to manipulate account balances.
 //
 //go:generate ifacemaker -f balance.go -o controller/balance.go -i Balance -s Balance -p controller -y "Controller describes methods, implemented by the service package."
 //go:generate mockgen -package mock -source controller/balance.go -destination controller/mock/mock_balance.go
 type Balance struct {
 	repository.Balance
+
+	// Access is used to check frozen addresses and regulator roles. If it is
+	// nil, freeze checks are skipped and regulator operations are forbidden.
+	Access repository.Access
+
+	// Notification stores accounting records of the regulator operations.
+	Notification repository.Notification
+
+	// Ledger is used to append the accounting records of the regulator operations to
+	// the hash chain, see Notification.Ledger. If it is nil, the chain is not kept.
+	Ledger repository.Ledger
+
+	// Outbox is used to deliver the accounting records of the regulator operations to
+	// the off-chain consumers, see Notification.Outbox. If it is nil, no entries are made.
+	Outbox repository.Outbox
+
+	// Limit is used to enforce outflow limits of Withdraw and Transfer. If it
+	// is nil, the limits are not checked.
+	Limit repository.Limit
+
+	// AllowList is used to ensure that only verified addresses receive funds on the
+	// accounts for which model.Account.RequiresAllowList is true. If it is nil, the
+	// check is skipped.
+	AllowList repository.AllowList
+
+	// AllowListLevel is the minimum verification level required by the allow-list check.
+	AllowListLevel uint
+
+	// CreditLine is used to let Withdraw and Transfer take balances below zero up to
+	// the credit limit of the address. If it is nil, overdrafts are not allowed.
+	CreditLine repository.CreditLine
+
+	// Holds stores authorization holds, which reserve funds of the accounts. If it
+	// is nil, the holds are not supported.
+	Holds repository.Hold
+
+	// Snapshots stores point-in-time balance snapshots. It must share the storage with
+	// storage.Balance.Snapshots, which captures the values of the balances. If it is
+	// nil, the snapshots are not supported.
+	Snapshots repository.Snapshot
+
+	// Distributions stores pro-rata distributions. The holdings are taken as of a
+	// snapshot, so the snapshots must be supported as well. If it is nil, the
+	// distributions are not supported.
+	Distributions repository.Distribution
+
+	// Accruals is used to apply the interest and demurrage rates of the currencies to
+	// the balances they are loaded by the operations, see model.AccrualRate. If it is
+	// nil, nothing accrues.
+	Accruals repository.Accrual
+
+	// Journal is used to post the balance updates of every operation, including the
+	// accruals, to the double-entry journal. If it is nil, nothing is posted.
+	Journal controller.Journal
 }
 
 // Deposit method is intended to increase the balance of the 'to' account.
@@ -37,15 +169,53 @@ func (bs *Balance) Deposit(
 	addr model.Address,
 	acc model.Account,
 	curr model.Currency,
-	amt *big.Int,
+	amount *model.Amount,
 ) (bu model.BalanceUpdate, err error) {
-	if amt.Sign() <= 0 {
-		return bu, ErrBalanceInvalidAmount
+	amt, err := bs.units(curr, amount)
+	if err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkOperation(model.OperationDeposit, acc); err != nil {
+		return bu, err
 	}
 
-	before, err := bs.Balance.Load(ctx, addr, acc, curr)
+	if err := bs.checkFrozen(ctx, addr); err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkAllowList(ctx, addr, acc); err != nil {
+		return bu, err
+	}
+
+	accrues, err := bs.accrues(ctx, acc, curr)
 	if err != nil {
-		return bu, bs.wrap(ErrBalanceRepository, err)
+		return bu, err
+	}
+
+	// in the accumulator mode of the repository balances are credited without reading
+	// them, so the resulting update carries only the delta. The credits are not stamped
+	// with the time, so the balances of the currencies with accrual rates are adjusted
+	// and saved instead, otherwise the credits would accrue since the balance was
+	// touched before them.
+	if bs.Balance.Accumulates() && !accrues {
+		if err := bs.Balance.Credit(ctx, addr, acc, curr, amt); err != nil {
+			return bu, bs.wrap(ErrBalanceRepository, err)
+		}
+
+		bu = bs.record(ctx, model.OperationDeposit, model.DirectionCredit, model.BalanceUpdate{
+			Address:    addr,
+			Account:    acc,
+			Currency:   curr,
+			ValueDelta: curr.Amount(amt),
+		})
+
+		return bu, bs.post(ctx, bu)
+	}
+
+	before, err := bs.load(ctx, addr, acc, curr)
+	if err != nil {
+		return bu, err
 	}
 
 	// balance = balance + value
@@ -55,14 +225,16 @@ func (bs *Balance) Deposit(
 		return bu, bs.wrap(ErrBalanceRepository, err)
 	}
 
-	return model.BalanceUpdate{
+	bu = bs.record(ctx, model.OperationDeposit, model.DirectionCredit, model.BalanceUpdate{
 		Address:    addr,
 		Account:    acc,
 		Currency:   curr,
-		OldValue:   before,
-		NewValue:   after,
-		ValueDelta: amt,
-	}, nil
+		OldValue:   curr.Amount(before),
+		NewValue:   curr.Amount(after),
+		ValueDelta: curr.Amount(amt),
+	})
+
+	return bu, bs.post(ctx, bu)
 }
 
 // Withdraw method is intended to decrease the balance of the 'from' account.
@@ -72,37 +244,52 @@ func (bs *Balance) Withdraw(
 	addr model.Address,
 	acc model.Account,
 	curr model.Currency,
-	amt *big.Int,
+	amount *model.Amount,
 ) (bu model.BalanceUpdate, err error) {
-	if amt.Sign() <= 0 {
-		return bu, ErrBalanceInvalidAmount
+	amt, err := bs.units(curr, amount)
+	if err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkOperation(model.OperationWithdraw, acc); err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkFrozen(ctx, addr); err != nil {
+		return bu, err
 	}
 
-	before, err := bs.Balance.Load(ctx, addr, acc, curr)
+	before, err := bs.load(ctx, addr, acc, curr)
 	if err != nil {
-		return bu, bs.wrap(ErrBalanceRepository, err)
+		return bu, err
 	}
 
 	// balance = balance - value
 	after := new(big.Int).Sub(before, amt)
 
 	// checking balance
-	if after.Sign() < 0 {
-		return bu, ErrBalanceInsufficientFunds
+	if err := bs.checkFunds(ctx, model.OperationWithdraw, addr, acc, curr, after); err != nil {
+		return bu, err
+	}
+
+	if err := bs.spend(ctx, model.OperationWithdraw, addr, curr, amt); err != nil {
+		return bu, err
 	}
 
 	if err := bs.Balance.Save(ctx, addr, acc, curr, after); err != nil {
 		return bu, bs.wrap(ErrBalanceRepository, err)
 	}
 
-	return model.BalanceUpdate{
+	bu = bs.record(ctx, model.OperationWithdraw, model.DirectionDebit, model.BalanceUpdate{
 		Address:    addr,
 		Account:    acc,
 		Currency:   curr,
-		OldValue:   before,
-		NewValue:   after,
-		ValueDelta: amt,
-	}, nil
+		OldValue:   curr.Amount(before),
+		NewValue:   curr.Amount(after),
+		ValueDelta: curr.Amount(amt),
+	})
+
+	return bu, bs.post(ctx, bu)
 }
 
 // Transfer method is intended to move funds from one account to another.
@@ -112,14 +299,24 @@ func (bs *Balance) Transfer(
 	addrFrom, addrTo model.Address,
 	acc model.Account,
 	curr model.Currency,
-	val *big.Int,
-) ([2]model.BalanceUpdate, error) {
+	amount *model.Amount,
+) (bu [2]model.BalanceUpdate, err error) {
+	amt, err := bs.units(curr, amount)
+	if err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkOperation(model.OperationTransfer, acc); err != nil {
+		return bu, err
+	}
+
 	return bs.transfer(
 		ctx,
+		model.OperationTransfer,
 		addrFrom, addrTo,
 		acc, acc,
 		curr,
-		val,
+		amt,
 	)
 }
 
@@ -130,93 +327,1539 @@ func (bs *Balance) InternalTransfer(
 	addr model.Address,
 	accFrom, accTo model.Account,
 	curr model.Currency,
-	val *big.Int,
-) ([2]model.BalanceUpdate, error) {
+	amount *model.Amount,
+) (bu [2]model.BalanceUpdate, err error) {
+	amt, err := bs.units(curr, amount)
+	if err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkOperation(model.OperationInternalTransfer, accFrom, accTo); err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkPair(accFrom, accTo); err != nil {
+		return bu, err
+	}
+
 	return bs.transfer(
 		ctx,
+		model.OperationInternalTransfer,
 		addr, addr,
 		accFrom, accTo,
 		curr,
-		val,
+		amt,
 	)
 }
 
 // Fetch retrieves the balance of a specific account for a given currency.
 // It takes the context (ctx), the address (addr), the account (acc),
 // and the currency (curr) as input parameters.
-// It returns the balance as a *big.Int value and an error if something goes wrong.
+// It returns the balance as a *model.Amount value with the precision of the currency
+// and an error if something goes wrong.
 func (bs *Balance) Fetch(
 	ctx context.Context,
 	addr model.Address,
 	acc model.Account,
 	curr model.Currency,
-) (*big.Int, error) {
-	balance, err := bs.Balance.Load(ctx, addr, acc, curr)
+) (*model.Amount, error) {
+	balance, err := bs.view(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	return curr.Amount(balance), nil
+}
+
+// Portfolio retrieves the non-zero balances of the address on all the declared
+// accounts together with their totals per currency.
+func (bs *Balance) Portfolio(ctx context.Context, addr model.Address) (*model.Portfolio, error) {
+	balances, err := bs.Balance.Portfolio(ctx, addr)
 	if err != nil {
 		return nil, bs.wrap(ErrBalanceRepository, err)
 	}
 
-	return balance, nil
+	p := &model.Portfolio{
+		Address: addr,
+		Totals:  make(map[model.Currency]*model.Amount),
+	}
+
+	totals := make(map[model.Currency]*big.Int)
+	for acc, currs := range balances {
+		for curr, balance := range currs {
+			if balance.Sign() == 0 {
+				continue
+			}
+
+			p.Positions = append(p.Positions, model.Position{
+				Account:  acc,
+				Currency: curr,
+				Balance:  curr.Amount(balance),
+			})
+
+			if _, ok := totals[curr]; !ok {
+				totals[curr] = new(big.Int)
+			}
+
+			totals[curr].Add(totals[curr], balance)
+		}
+	}
+
+	sort.Slice(p.Positions, func(i, j int) bool {
+		if p.Positions[i].Currency != p.Positions[j].Currency {
+			return p.Positions[i].Currency < p.Positions[j].Currency
+		}
+
+		return p.Positions[i].Account < p.Positions[j].Account
+	})
+
+	for curr, total := range totals {
+		p.Totals[curr] = curr.Amount(total)
+	}
+
+	return p, nil
 }
 
-func (bs *Balance) transfer(
+// Compact merges the credits accumulated as delta records into the balance of a
+// specific account for a given currency.
+func (bs *Balance) Compact(
 	ctx context.Context,
-	addrFrom, addrTo model.Address,
-	accFrom, accTo model.Account,
+	addr model.Address,
+	acc model.Account,
 	curr model.Currency,
-	amt *big.Int,
-) (bu [2]model.BalanceUpdate, err error) {
-	if amt.Sign() <= 0 {
-		return bu, ErrBalanceInvalidAmount
+) error {
+	if err := bs.Balance.Compact(ctx, addr, acc, curr); err != nil {
+		return bs.wrap(ErrBalanceRepository, err)
 	}
 
-	beforeFrom, err := bs.Balance.Load(ctx, addrFrom, accFrom, curr)
+	return nil
+}
+
+// AvailableBalance retrieves the balance of a specific account for a given currency
+// reduced by the funds reserved by its active authorization holds.
+func (bs *Balance) AvailableBalance(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*model.Amount, error) {
+	balance, err := bs.view(ctx, addr, acc, curr)
 	if err != nil {
-		return bu, bs.wrap(ErrBalanceRepository, err)
+		return nil, err
 	}
 
-	beforeTo, err := bs.Balance.Load(ctx, addrTo, accTo, curr)
+	held, err := bs.held(ctx, addr, acc, curr)
 	if err != nil {
-		return bu, bs.wrap(ErrBalanceRepository, err)
+		return nil, err
 	}
 
-	// transferring [balanceFrom -> balanceTo]:
-	// balanceFrom = balanceFrom - value
-	// balanceTo   = balanceTo   + value
-	afterFrom := new(big.Int).Sub(beforeFrom, amt)
-	afterTo := new(big.Int).Add(beforeTo, amt)
+	return curr.Amount(balance.Sub(balance, held)), nil
+}
 
-	// checking balance
-	if afterFrom.Sign() < 0 {
-		return bu, ErrBalanceInsufficientFunds
+// Snapshot method records a point in time, as of which the balances can be retrieved
+// by BalanceAt. The balances are not copied: their values are captured before the
+// first change after the snapshot.
+func (bs *Balance) Snapshot(ctx context.Context, id string) (*model.Snapshot, error) {
+	if bs.Snapshots == nil {
+		return nil, ErrBalanceSnapshotNotFound
 	}
 
-	if err := bs.Balance.Save(ctx, addrFrom, accFrom, curr, afterFrom); err != nil {
-		return bu, bs.wrap(ErrBalanceRepository, err)
+	existing, err := bs.Snapshots.Load(ctx, id)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
 	}
 
-	if err := bs.Balance.Save(ctx, addrTo, accTo, curr, afterTo); err != nil {
-		return bu, bs.wrap(ErrBalanceRepository, err)
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceSnapshotExists, id)
 	}
 
-	return [2]model.BalanceUpdate{
-		{
-			Address:    addrFrom,
-			Account:    accFrom,
-			Currency:   curr,
-			OldValue:   beforeFrom,
-			NewValue:   afterFrom,
-			ValueDelta: amt,
-		},
-		{
-			Address:    addrTo,
-			Account:    accTo,
-			Currency:   curr,
-			OldValue:   beforeTo,
-			NewValue:   afterTo,
-			ValueDelta: amt,
-		},
-	}, nil
+	snap := &model.Snapshot{ID: id}
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		snap.Timestamp = tx.Timestamp
+	}
+
+	if err := bs.Snapshots.Take(ctx, snap); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return snap, nil
+}
+
+// BalanceAt retrieves the balance of a specific account for a given currency as of
+// the snapshot with given identifier.
+func (bs *Balance) BalanceAt(
+	ctx context.Context,
+	snapshotID string,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*model.Amount, error) {
+	if bs.Snapshots == nil {
+		return nil, ErrBalanceSnapshotNotFound
+	}
+
+	snap, err := bs.Snapshots.Load(ctx, snapshotID)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if snap == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceSnapshotNotFound, snapshotID)
+	}
+
+	balance, err := bs.Balance.LoadAt(ctx, snap.Seq, addr, acc, curr)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return curr.Amount(balance), nil
+}
+
+// Distribute method starts a pro-rata distribution of the amount from the source
+// address to all the holders of the reference currency on the same account, e.g. a
+// dividend or a coupon, moves the amount to the escrow of the distribution and credits
+// the first batch of at most limit holders from it. The
+// holdings are taken as of a snapshot made by the distribution. The remaining holders
+// are credited by ContinueDistribution in subsequent transactions.
+func (bs *Balance) Distribute(
+	ctx context.Context,
+	id string,
+	source model.Address,
+	acc model.Account,
+	curr, ref model.Currency,
+	amount *model.Amount,
+	limit int,
+) (*model.Distribution, model.BalancesUpdate, error) {
+	if bs.Distributions == nil || bs.Snapshots == nil {
+		return nil, nil, ErrBalanceDistributionNotFound
+	}
+
+	amt, err := bs.units(curr, amount)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := bs.checkOperation(model.OperationDistribution, acc); err != nil {
+		return nil, nil, err
+	}
+
+	existing, err := bs.Distributions.Load(ctx, id)
+	if err != nil {
+		return nil, nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if existing != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrBalanceDistributionExists, id)
+	}
+
+	if err := bs.checkFrozen(ctx, source); err != nil {
+		return nil, nil, err
+	}
+
+	// the holdings are not changed by the transaction yet, so the current total is
+	// the one as of the snapshot
+	supply, err := bs.Balance.Supply(ctx, acc, ref)
+	if err != nil {
+		return nil, nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	own, err := bs.Balance.Load(ctx, source, acc, ref)
+	if err != nil {
+		return nil, nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if own.Sign() > 0 {
+		supply.Sub(supply, own)
+	}
+
+	if supply.Sign() == 0 {
+		return nil, nil, fmt.Errorf("%w: %s", ErrBalanceNoHolders, ref)
+	}
+
+	snap, err := bs.Snapshot(ctx, "distribution/"+id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dist := &model.Distribution{
+		ID:          id,
+		Source:      source,
+		Account:     acc,
+		Currency:    curr,
+		Reference:   ref,
+		Amount:      curr.Amount(amt),
+		Supply:      ref.Amount(supply),
+		SnapshotSeq: snap.Seq,
+		Counted:     ref.Amount(new(big.Int)),
+		Paid:        curr.Amount(new(big.Int)),
+		Status:      model.DistributionStatusActive,
+		Timestamp:   snap.Timestamp,
+	}
+
+	escrow, err := bs.move(ctx, model.OperationDistribution, source, dist.Escrow(), acc, acc, curr, amt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dist, bu, err := bs.distribute(ctx, dist, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return dist, append(escrow[:], bu...), nil
+}
+
+// ContinueDistribution method credits the next batch of at most limit holders of the
+// distribution. The distribution is completed by the batch, which reaches the last
+// holder, and the amount left in the escrow is returned to the source then.
+func (bs *Balance) ContinueDistribution(
+	ctx context.Context,
+	id string,
+	limit int,
+) (*model.Distribution, model.BalancesUpdate, error) {
+	dist, err := bs.FetchDistribution(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if dist.Status == model.DistributionStatusCompleted {
+		return nil, nil, fmt.Errorf("%w: %s", ErrBalanceDistributionCompleted, id)
+	}
+
+	return bs.distribute(ctx, dist, limit)
+}
+
+// FetchDistribution retrieves the pro-rata distribution.
+func (bs *Balance) FetchDistribution(ctx context.Context, id string) (*model.Distribution, error) {
+	if bs.Distributions == nil {
+		return nil, ErrBalanceDistributionNotFound
+	}
+
+	dist, err := bs.Distributions.Load(ctx, id)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if dist == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceDistributionNotFound, id)
+	}
+
+	return dist, nil
+}
+
+// Hold method reserves funds of the account for the merchant until the moment of
+// expiry. Reserved funds can not be withdrawn or transferred, but they can be
+// captured by the merchant in one or several parts.
+func (bs *Balance) Hold(
+	ctx context.Context,
+	id string,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	merchant model.Address,
+	amount *model.Amount,
+	expiresAt time.Time,
+) (*model.Hold, error) {
+	amt, err := bs.units(curr, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bs.checkOperation(model.OperationHold, acc); err != nil {
+		return nil, err
+	}
+
+	if bs.Holds == nil {
+		return nil, fmt.Errorf("%w: holds are not supported", ErrBalanceOperationNotPermitted)
+	}
+
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return nil, ErrBalanceNoTransaction
+	}
+
+	if !expiresAt.After(tx.Timestamp) {
+		return nil, fmt.Errorf("%w: expires at %s", ErrBalanceInvalidHold, expiresAt)
+	}
+
+	if merchant == "" || merchant == addr {
+		return nil, fmt.Errorf("%w: merchant %s", ErrBalanceInvalidHold, merchant)
+	}
+
+	if err := bs.checkFrozen(ctx, addr); err != nil {
+		return nil, err
+	}
+
+	existing, err := bs.Holds.Load(ctx, id)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if existing != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceHoldExists, id)
+	}
+
+	balance, err := bs.view(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	held, err := bs.held(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	// available = balance - held - value
+	available := new(big.Int).Sub(balance, held)
+	if err := bs.checkFunds(ctx, model.OperationHold, addr, acc, curr, available.Sub(available, amt)); err != nil {
+		return nil, err
+	}
+
+	hold := &model.Hold{
+		ID:        id,
+		Address:   addr,
+		Account:   acc,
+		Currency:  curr,
+		Merchant:  merchant,
+		Amount:    curr.Amount(amt),
+		Captured:  curr.Amount(nil),
+		ExpiresAt: expiresAt,
+		Status:    model.HoldStatusActive,
+	}
+
+	if err := bs.Holds.Save(ctx, hold); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return hold, nil
+}
+
+// Capture method transfers the amount reserved by the authorization hold to the
+// merchant. The amount may be less than the reserved one, in which case the rest
+// stays reserved and can be captured later. It can be executed only by the merchant
+// of the hold.
+func (bs *Balance) Capture(
+	ctx context.Context,
+	merchant model.Address,
+	id string,
+	amount *model.Amount,
+) (bu [2]model.BalanceUpdate, err error) {
+	hold, err := bs.activeHold(ctx, id)
+	if err != nil {
+		return bu, err
+	}
+
+	if merchant != hold.Merchant {
+		return bu, fmt.Errorf("%w: %s is not the merchant of %s", ErrBalanceForbidden, merchant, id)
+	}
+
+	if err := bs.checkOperation(model.OperationCapture, hold.Account); err != nil {
+		return bu, err
+	}
+
+	amt, err := bs.units(hold.Currency, amount)
+	if err != nil {
+		return bu, err
+	}
+
+	remaining := hold.Amount.Int()
+	if amt.Cmp(remaining) > 0 {
+		return bu, fmt.Errorf("%w: %s is reserved", ErrBalanceInsufficientFunds, hold.Amount)
+	}
+
+	if err := bs.checkFrozen(ctx, hold.Address, hold.Merchant); err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkAllowList(ctx, hold.Merchant, hold.Account); err != nil {
+		return bu, err
+	}
+
+	hold.Amount = hold.Currency.Amount(remaining.Sub(remaining, amt))
+	hold.Captured = hold.Currency.Amount(new(big.Int).Add(hold.Captured.Int(), amt))
+
+	if hold.Amount.Sign() == 0 {
+		hold.Status = model.HoldStatusCaptured
+	}
+
+	if err := bs.Holds.Save(ctx, hold); err != nil {
+		return bu, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return bs.move(
+		ctx,
+		model.OperationCapture,
+		hold.Address, hold.Merchant,
+		hold.Account, hold.Account,
+		hold.Currency,
+		amt,
+	)
+}
+
+// Void method releases the funds reserved by the authorization hold. It can be
+// executed only by the merchant or the owner of the funds.
+func (bs *Balance) Void(ctx context.Context, caller model.Address, id string) (*model.Hold, error) {
+	hold, err := bs.activeHold(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if caller != hold.Merchant && caller != hold.Address {
+		return nil, fmt.Errorf("%w: %s is neither the merchant nor the owner of %s", ErrBalanceForbidden, caller, id)
+	}
+
+	hold.Status = model.HoldStatusVoided
+
+	if err := bs.Holds.Save(ctx, hold); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return hold, nil
+}
+
+// FetchHold retrieves the authorization hold. The status of an expired hold is
+// reported as model.HoldStatusExpired even though it is not stored yet.
+func (bs *Balance) FetchHold(ctx context.Context, id string) (*model.Hold, error) {
+	if bs.Holds == nil {
+		return nil, ErrBalanceHoldNotFound
+	}
+
+	hold, err := bs.Holds.Load(ctx, id)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if hold == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceHoldNotFound, id)
+	}
+
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		hold.Status = hold.StatusAt(tx.Timestamp)
+	}
+
+	return hold, nil
+}
+
+// ForcedTransfer method is intended for moving funds out of an address without
+// the owner's consent, e.g. by a court order. It can be executed only by an address
+// holding the model.RoleRegulator role, bypasses freeze checks and stores a
+// model.NotificationTypeForcedTransfer record referencing the order.
+func (bs *Balance) ForcedTransfer(
+	ctx context.Context,
+	order model.ForcedTransferOrder,
+	addrFrom, addrTo model.Address,
+	acc model.Account,
+	curr model.Currency,
+	amount *model.Amount,
+) (bu [2]model.BalanceUpdate, err error) {
+	if err := order.Validate(); err != nil {
+		return bu, bs.wrap(ErrBalanceInvalidOrder, err)
+	}
+
+	if bs.Access == nil || bs.Notification == nil {
+		return bu, ErrBalanceForbidden
+	}
+
+	ok, err := bs.Access.HasRole(ctx, order.Regulator, model.RoleRegulator)
+	if err != nil {
+		return bu, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if !ok {
+		return bu, fmt.Errorf("%w: %s is not a regulator", ErrBalanceForbidden, order.Regulator)
+	}
+
+	amt, err := bs.units(curr, amount)
+	if err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkOperation(model.OperationForcedTransfer, acc); err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkAllowList(ctx, addrTo, acc); err != nil {
+		return bu, err
+	}
+
+	if bu, err = bs.move(ctx, model.OperationForcedTransfer, addrFrom, addrTo, acc, acc, curr, amt); err != nil {
+		return bu, err
+	}
+
+	ft := model.ForcedTransfer{
+		ForcedTransferOrder: order,
+		Updates:             bu[:],
+	}
+
+	if err := bs.Notification.SaveForcedTransfer(ctx, model.Notification[model.ForcedTransfer]{
+		ID:   order.ID,
+		Type: model.NotificationTypeForcedTransfer,
+		Body: ft,
+	}); err != nil {
+		return bu, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := appendLedger(ctx, bs.Ledger, model.NotificationTypeForcedTransfer, order.ID, ft); err != nil {
+		return bu, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := publishOutbox(ctx, bs.Outbox, model.NotificationTypeForcedTransfer, order.ID); err != nil {
+		return bu, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return bu, nil
+}
+
+// Reverse method undoes the balance updates recorded by the notification of given type
+// and identifier, applying the exact opposite changes to the same balances. An operation
+// can be reversed only once. The reversal must be permitted on the accounts of the
+// balances, and a debit may draw on the credit line of the address. It can be executed
+// only by an address holding the model.RoleRegulator role and stores a
+// model.NotificationTypeReversal record linked to the original notification.
+func (bs *Balance) Reverse(
+	ctx context.Context,
+	regulator model.Address,
+	typ, id string,
+) (bu model.BalancesUpdate, err error) {
+	if bs.Access == nil || bs.Notification == nil {
+		return nil, ErrBalanceForbidden
+	}
+
+	ok, err := bs.Access.HasRole(ctx, regulator, model.RoleRegulator)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not a regulator", ErrBalanceForbidden, regulator)
+	}
+
+	raw, err := bs.Notification.Load(ctx, typ, id)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if raw == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrBalanceOperationNotFound, typ, id)
+	}
+
+	original, err := reversible(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	reversalID := model.ReversalID(typ, id)
+
+	reversal, err := bs.Notification.LoadReversal(ctx, reversalID)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if reversal != nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrBalanceAlreadyReversed, typ, id)
+	}
+
+	// the updates of the same balance are netted, so every balance is written once
+	var (
+		keys   []balanceKey
+		deltas = make(map[balanceKey]*big.Int)
+	)
+
+	for _, u := range original {
+		k := balanceKey{addr: u.Address, acc: u.Account, curr: u.Currency}
+		if _, ok := deltas[k]; !ok {
+			keys = append(keys, k)
+			deltas[k] = new(big.Int)
+		}
+
+		// opposite = -delta
+		deltas[k].Sub(deltas[k], u.SignedDelta())
+	}
+
+	if err := bs.checkReversal(keys); err != nil {
+		return nil, err
+	}
+
+	for _, k := range keys {
+		delta := deltas[k]
+		if delta.Sign() == 0 {
+			continue
+		}
+
+		before, err := bs.load(ctx, k.addr, k.acc, k.curr)
+		if err != nil {
+			return nil, err
+		}
+
+		// balance = balance + opposite
+		after := new(big.Int).Add(before, delta)
+
+		if delta.Sign() < 0 {
+			if err := bs.checkFunds(ctx, model.OperationReversal, k.addr, k.acc, k.curr, after); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := bs.Balance.Save(ctx, k.addr, k.acc, k.curr, after); err != nil {
+			return nil, bs.wrap(ErrBalanceRepository, err)
+		}
+
+		dir := model.DirectionCredit
+		if delta.Sign() < 0 {
+			dir = model.DirectionDebit
+		}
+
+		bu = append(bu, bs.record(ctx, model.OperationReversal, dir, model.BalanceUpdate{
+			Address:    k.addr,
+			Account:    k.acc,
+			Currency:   k.curr,
+			OldValue:   k.curr.Amount(before),
+			NewValue:   k.curr.Amount(after),
+			ValueDelta: k.curr.Amount(delta.Abs(delta)),
+		}))
+	}
+
+	if err := bs.post(ctx, bu...); err != nil {
+		return nil, err
+	}
+
+	rev := model.Reversal{
+		OriginalType: typ,
+		OriginalID:   id,
+		Updates:      bu,
+	}
+
+	if err := bs.Notification.SaveReversal(ctx, model.Notification[model.Reversal]{
+		ID:   reversalID,
+		Type: model.NotificationTypeReversal,
+		Body: rev,
+	}); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := appendLedger(ctx, bs.Ledger, model.NotificationTypeReversal, reversalID, rev); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := publishOutbox(ctx, bs.Outbox, model.NotificationTypeReversal, reversalID); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return bu, nil
+}
+
+// checkReversal returns ErrBalanceOperationNotPermitted if the reversal is not
+// permitted on any of the balances, or if the funds may not be moved between the
+// accounts of the same address, see checkPair.
+func (bs *Balance) checkReversal(keys []balanceKey) error {
+	for i, k := range keys {
+		if err := bs.checkOperation(model.OperationReversal, k.acc); err != nil {
+			return err
+		}
+
+		for _, other := range keys[:i] {
+			if other.addr != k.addr || other.acc == k.acc {
+				continue
+			}
+
+			if err := bs.checkPair(other.acc, k.acc); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// reversible returns the balance updates of the notification to reverse. The bodies
+// of the registered types are decoded through the registry, and the ones without
+// balance updates are not reversible. The balance updates may be stored with any
+// type, so the bodies of the unregistered types are decoded as model.BalancesUpdate.
+func reversible(raw *model.RawNotification) (model.BalancesUpdate, error) {
+	if raw.Type == model.NotificationTypeReversal {
+		return nil, fmt.Errorf("%w: %s/%s", ErrBalanceNotReversible, raw.Type, raw.ID)
+	}
+
+	body, err := model.DecodeNotificationBody(raw)
+	if errors.Is(err, model.ErrNotificationUnknown) {
+		var bu model.BalancesUpdate
+		if err := json.Unmarshal(raw.Body, &bu); err != nil {
+			return nil, fmt.Errorf("%w: %s/%s: %s", ErrBalanceNotReversible, raw.Type, raw.ID, err.Error())
+		}
+
+		return bu, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceRepository, err.Error())
+	}
+
+	updater, ok := body.(model.BalanceUpdater)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s/%s", ErrBalanceNotReversible, raw.Type, raw.ID)
+	}
+
+	return updater.BalanceUpdates(), nil
+}
+
+// distribute credits the next batch of the holders of the distribution and debits
+// the escrow by the total of their shares. Freeze and allow-list checks are not
+// applied to the holders, since they are entitled to the shares by their holdings.
+func (bs *Balance) distribute(
+	ctx context.Context,
+	dist *model.Distribution,
+	limit int,
+) (*model.Distribution, model.BalancesUpdate, error) {
+	if limit <= 0 {
+		limit = DistributionBatchLimit
+	}
+
+	holders, err := bs.Balance.Holders(ctx, dist.Account, dist.Reference, dist.Cursor, limit)
+	if err != nil {
+		return nil, nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	type credit struct {
+		addr   model.Address
+		amount *big.Int
+	}
+
+	var (
+		credits []credit
+		total   = new(big.Int)
+	)
+
+	for _, addr := range holders {
+		dist.Cursor = addr
+		if addr == dist.Source || addr == dist.Escrow() {
+			continue
+		}
+
+		holding, err := bs.Balance.LoadAt(ctx, dist.SnapshotSeq, addr, dist.Account, dist.Reference)
+		if err != nil {
+			return nil, nil, bs.wrap(ErrBalanceRepository, err)
+		}
+
+		if holding.Sign() <= 0 {
+			continue
+		}
+
+		share := dist.Share(holding)
+		dist.Counted = dist.Reference.Amount(holding.Add(holding, dist.Counted.Int()))
+
+		if share.Sign() > 0 {
+			credits = append(credits, credit{addr: addr, amount: share})
+			total.Add(total, share)
+		}
+	}
+
+	var bu model.BalancesUpdate
+
+	if total.Sign() > 0 {
+		before, err := bs.load(ctx, dist.Escrow(), dist.Account, dist.Currency)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// escrow = escrow - total
+		after := new(big.Int).Sub(before, total)
+
+		if after.Sign() < 0 {
+			return nil, nil, fmt.Errorf("%w: %s", ErrBalanceInsufficientFunds, dist.Escrow())
+		}
+
+		if err := bs.Balance.Save(ctx, dist.Escrow(), dist.Account, dist.Currency, after); err != nil {
+			return nil, nil, bs.wrap(ErrBalanceRepository, err)
+		}
+
+		bu = append(bu, bs.record(ctx, model.OperationDistribution, model.DirectionDebit, model.BalanceUpdate{
+			Address:    dist.Escrow(),
+			Account:    dist.Account,
+			Currency:   dist.Currency,
+			OldValue:   dist.Currency.Amount(before),
+			NewValue:   dist.Currency.Amount(after),
+			ValueDelta: dist.Currency.Amount(total),
+		}))
+	}
+
+	for _, c := range credits {
+		before, err := bs.load(ctx, c.addr, dist.Account, dist.Currency)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// balance = balance + share
+		after := new(big.Int).Add(before, c.amount)
+
+		if err := bs.Balance.Save(ctx, c.addr, dist.Account, dist.Currency, after); err != nil {
+			return nil, nil, bs.wrap(ErrBalanceRepository, err)
+		}
+
+		bu = append(bu, bs.record(ctx, model.OperationDistribution, model.DirectionCredit, model.BalanceUpdate{
+			Address:             c.addr,
+			Account:             dist.Account,
+			Currency:            dist.Currency,
+			OldValue:            dist.Currency.Amount(before),
+			NewValue:            dist.Currency.Amount(after),
+			ValueDelta:          dist.Currency.Amount(c.amount),
+			Counterparty:        dist.Escrow(),
+			CounterpartyAccount: dist.Account,
+		}))
+	}
+
+	if err := bs.post(ctx, bu...); err != nil {
+		return nil, nil, err
+	}
+
+	dist.Paid = dist.Currency.Amount(total.Add(total, dist.Paid.Int()))
+	if len(holders) < limit {
+		dist.Status = model.DistributionStatusCompleted
+
+		// the amount not paid due to the holdings short of the supply is returned
+		rest := new(big.Int).Sub(dist.Amount.Int(), dist.Paid.Int())
+		if rest.Sign() > 0 {
+			back, err := bs.move(ctx, model.OperationDistribution, dist.Escrow(), dist.Source, dist.Account, dist.Account, dist.Currency, rest)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			bu = append(bu, back[:]...)
+		}
+
+		dist.Returned = dist.Currency.Amount(rest)
+	}
+
+	if err := bs.Distributions.Save(ctx, dist); err != nil {
+		return nil, nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return dist, bu, nil
+}
+
+func (bs *Balance) transfer(
+	ctx context.Context,
+	op model.Operation,
+	addrFrom, addrTo model.Address,
+	accFrom, accTo model.Account,
+	curr model.Currency,
+	amt *big.Int,
+) (bu [2]model.BalanceUpdate, err error) {
+	if amt.Sign() <= 0 {
+		return bu, ErrBalanceInvalidAmount
+	}
+
+	if err := bs.checkFrozen(ctx, addrFrom, addrTo); err != nil {
+		return bu, err
+	}
+
+	if err := bs.checkAllowList(ctx, addrTo, accTo); err != nil {
+		return bu, err
+	}
+
+	return bs.move(ctx, op, addrFrom, addrTo, accFrom, accTo, curr, amt)
+}
+
+// move transfers funds between two accounts without any checks except the
+// balance sufficiency, which depends on the operation, and the outflow limits.
+func (bs *Balance) move(
+	ctx context.Context,
+	op model.Operation,
+	addrFrom, addrTo model.Address,
+	accFrom, accTo model.Account,
+	curr model.Currency,
+	amt *big.Int,
+) (bu [2]model.BalanceUpdate, err error) {
+	beforeFrom, err := bs.load(ctx, addrFrom, accFrom, curr)
+	if err != nil {
+		return bu, err
+	}
+
+	beforeTo, err := bs.load(ctx, addrTo, accTo, curr)
+	if err != nil {
+		return bu, err
+	}
+
+	// transferring [balanceFrom -> balanceTo]:
+	// balanceFrom = balanceFrom - value
+	// balanceTo   = balanceTo   + value
+	afterFrom := new(big.Int).Sub(beforeFrom, amt)
+	afterTo := new(big.Int).Add(beforeTo, amt)
+
+	// checking balance
+	if err := bs.checkFunds(ctx, op, addrFrom, accFrom, curr, afterFrom); err != nil {
+		return bu, err
+	}
+
+	if err := bs.spend(ctx, op, addrFrom, curr, amt); err != nil {
+		return bu, err
+	}
+
+	if err := bs.Balance.Save(ctx, addrFrom, accFrom, curr, afterFrom); err != nil {
+		return bu, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := bs.Balance.Save(ctx, addrTo, accTo, curr, afterTo); err != nil {
+		return bu, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	bu = [2]model.BalanceUpdate{
+		bs.record(ctx, op, model.DirectionDebit, model.BalanceUpdate{
+			Address:             addrFrom,
+			Account:             accFrom,
+			Currency:            curr,
+			OldValue:            curr.Amount(beforeFrom),
+			NewValue:            curr.Amount(afterFrom),
+			ValueDelta:          curr.Amount(amt),
+			Counterparty:        addrTo,
+			CounterpartyAccount: accTo,
+		}),
+		bs.record(ctx, op, model.DirectionCredit, model.BalanceUpdate{
+			Address:             addrTo,
+			Account:             accTo,
+			Currency:            curr,
+			OldValue:            curr.Amount(beforeTo),
+			NewValue:            curr.Amount(afterTo),
+			ValueDelta:          curr.Amount(amt),
+			Counterparty:        addrFrom,
+			CounterpartyAccount: accFrom,
+		}),
+	}
+
+	return bu, bs.post(ctx, bu[:]...)
+}
+
+// load retrieves the balance adjusted by the accrual rate of the currency. The
+// adjustment is saved and posted to the journal together with a
+// model.NotificationTypeAccrual record, so the balance updates of the operation start
+// from the adjusted value.
+func (bs *Balance) load(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, error) {
+	balance, accrual, err := bs.accrue(ctx, addr, acc, curr)
+	if err != nil || accrual == nil {
+		return balance, err
+	}
+
+	if err := bs.Balance.Save(ctx, addr, acc, curr, balance); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := bs.post(ctx, accrual.Update); err != nil {
+		return nil, err
+	}
+
+	if bs.Notification == nil {
+		return balance, nil
+	}
+
+	tx, _ := model.TransactionFromContext(ctx)
+
+	raw, err := model.EncodeNotification(model.Notification[model.Accrual]{
+		ID:   model.AccrualID(tx.ID, addr, acc, curr),
+		Type: model.NotificationTypeAccrual,
+		Body: *accrual,
+	})
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := bs.Notification.SaveRaw(ctx, raw); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := appendLedger(ctx, bs.Ledger, raw.Type, raw.ID, raw.Body); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if err := publishOutbox(ctx, bs.Outbox, raw.Type, raw.ID); err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return balance, nil
+}
+
+// accrues reports whether the accrual is permitted on the account and the currency
+// has the history of accrual rates.
+func (bs *Balance) accrues(ctx context.Context, acc model.Account, curr model.Currency) (bool, error) {
+	if bs.Accruals == nil || bs.checkOperation(model.OperationAccrual, acc) != nil {
+		return false, nil
+	}
+
+	rate, err := bs.Accruals.LoadRate(ctx, curr)
+	if err != nil {
+		return false, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return rate != nil, nil
+}
+
+// view retrieves the balance adjusted by the accrual rate of the currency without
+// saving the adjustment.
+func (bs *Balance) view(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, error) {
+	balance, _, err := bs.accrue(ctx, addr, acc, curr)
+	return balance, err
+}
+
+// accrue retrieves the balance and applies the accrual rates of the currency for the
+// time since the balance was touched last till the timestamp of the transaction, each
+// for the time it was in force. If the balance is adjusted, the accounting record of
+// the adjustment is returned as well. The balances of the accounts not permitting
+// model.OperationAccrual are left as they are.
+func (bs *Balance) accrue(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, *model.Accrual, error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if bs.Accruals == nil || !ok || tx.Timestamp.IsZero() ||
+		bs.checkOperation(model.OperationAccrual, acc) != nil {
+		balance, err := bs.Balance.Load(ctx, addr, acc, curr)
+		if err != nil {
+			return nil, nil, bs.wrap(ErrBalanceRepository, err)
+		}
+
+		return balance, nil, nil
+	}
+
+	rates, err := bs.Accruals.Rates(ctx, curr)
+	if err != nil {
+		return nil, nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	before, touched, err := bs.Balance.LoadTouched(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	applied := model.RatesInForce(rates, touched, tx.Timestamp)
+	if len(applied) == 0 {
+		return before, nil, nil
+	}
+
+	after := model.Accrue(before, rates, touched, tx.Timestamp)
+
+	delta := new(big.Int).Sub(after, before)
+	if delta.Sign() == 0 {
+		return after, nil, nil
+	}
+
+	dir := model.DirectionCredit
+	if delta.Sign() < 0 {
+		dir = model.DirectionDebit
+	}
+
+	if touched.Before(applied[0].Since) {
+		touched = applied[0].Since
+	}
+
+	rate := applied[len(applied)-1]
+
+	return after, &model.Accrual{
+		Rate:   rate.Rate,
+		Period: rate.Period,
+		From:   touched,
+		To:     tx.Timestamp,
+		Rates:  applied,
+		Update: bs.record(ctx, model.OperationAccrual, dir, model.BalanceUpdate{
+			Address:    addr,
+			Account:    acc,
+			Currency:   curr,
+			OldValue:   curr.Amount(before),
+			NewValue:   curr.Amount(after),
+			ValueDelta: curr.Amount(delta.Abs(delta)),
+		}),
+	}, nil
+}
+
+// record fills in the operation metadata of the balance update: the kind and the
+// direction of the operation, the transaction identifier and timestamp, and the memo
+// carried by the context.
+func (bs *Balance) record(
+	ctx context.Context,
+	op model.Operation,
+	dir model.Direction,
+	u model.BalanceUpdate,
+) model.BalanceUpdate {
+	u.Operation = op
+	u.Direction = dir
+	u.Memo = model.MemoFromContext(ctx)
+
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		u.TxID = tx.ID
+		u.Timestamp = tx.Timestamp
+	}
+
+	return u
+}
+
+// post records the balance updates of the operation to the journal.
+func (bs *Balance) post(ctx context.Context, updates ...model.BalanceUpdate) error {
+	if err := postJournal(ctx, bs.Journal, updates...); err != nil {
+		return bs.wrap(ErrBalanceRepository, err)
+	}
+
+	return nil
+}
+
+// units converts the amount to the minimal units of the currency. It returns
+// ErrBalanceInvalidAmount if the amount is not positive or exceeds the precision of
+// the currency.
+func (bs *Balance) units(curr model.Currency, amount *model.Amount) (*big.Int, error) {
+	if amount.Sign() <= 0 {
+		return nil, ErrBalanceInvalidAmount
+	}
+
+	scaled, err := amount.Rescale(curr.Precision())
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceInvalidAmount, err)
+	}
+
+	return scaled.Int(), nil
+}
+
+// activeHold loads the authorization hold and checks that it is still active. An
+// expired hold is saved with model.HoldStatusExpired and removed from the index.
+func (bs *Balance) activeHold(ctx context.Context, id string) (*model.Hold, error) {
+	if bs.Holds == nil {
+		return nil, ErrBalanceHoldNotFound
+	}
+
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return nil, ErrBalanceNoTransaction
+	}
+
+	hold, err := bs.Holds.Load(ctx, id)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if hold == nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceHoldNotFound, id)
+	}
+
+	switch status := hold.StatusAt(tx.Timestamp); status {
+	case model.HoldStatusActive:
+		return hold, nil
+	case model.HoldStatusExpired:
+		hold.Status = status
+		if err := bs.Holds.Save(ctx, hold); err != nil {
+			return nil, bs.wrap(ErrBalanceRepository, err)
+		}
+
+		fallthrough
+	default:
+		return nil, fmt.Errorf("%w: %s is %s", ErrBalanceHoldNotActive, id, hold.Status)
+	}
+}
+
+// checkOperation returns ErrBalanceOperationNotPermitted if any of the accounts is
+// not declared in the model account registry or does not permit the operation.
+func (bs *Balance) checkOperation(op model.Operation, accs ...model.Account) error {
+	for _, acc := range accs {
+		t, ok := model.LookupAccount(acc)
+		if !ok || !t.Permits(op) {
+			return fmt.Errorf("%w: %s on %s", ErrBalanceOperationNotPermitted, op, acc)
+		}
+	}
+
+	return nil
+}
+
+// checkPair returns ErrBalanceOperationNotPermitted if the funds may not be moved
+// between the accounts, see model.AccountType.Pairs. The accounts must be declared.
+func (bs *Balance) checkPair(accFrom, accTo model.Account) error {
+	from, _ := model.LookupAccount(accFrom)
+	to, _ := model.LookupAccount(accTo)
+
+	if !from.Pairs(to) {
+		return fmt.Errorf("%w: %s between %s and %s", ErrBalanceOperationNotPermitted, model.OperationInternalTransfer, accFrom, accTo)
+	}
+
+	return nil
+}
+
+// checkFrozen returns ErrBalanceAddressFrozen if any of the addresses is frozen.
+func (bs *Balance) checkFrozen(ctx context.Context, addrs ...model.Address) error {
+	if bs.Access == nil {
+		return nil
+	}
+
+	for _, addr := range addrs {
+		frozen, err := bs.Access.IsFrozen(ctx, addr)
+		if err != nil {
+			return bs.wrap(ErrBalanceRepository, err)
+		}
+
+		if frozen {
+			return fmt.Errorf("%w: %s", ErrBalanceAddressFrozen, addr)
+		}
+	}
+
+	return nil
+}
+
+// checkFunds returns ErrBalanceInsufficientFunds if the balance after the debit,
+// reduced by the funds reserved by active holds, is negative and exceeds the credit
+// limit of the address. Holds are respected by Withdraw, Transfer, InternalTransfer and
+// distributions, credit lines apply only to Withdraw, Transfer, holds, captures of
+// holds and reversals.
+func (bs *Balance) checkFunds(
+	ctx context.Context,
+	op model.Operation,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	after *big.Int,
+) error {
+	available := new(big.Int).Set(after)
+
+	if op == model.OperationWithdraw || op == model.OperationTransfer ||
+		op == model.OperationInternalTransfer || op == model.OperationDistribution {
+		held, err := bs.held(ctx, addr, acc, curr)
+		if err != nil {
+			return err
+		}
+
+		available.Sub(available, held)
+	}
+
+	if available.Sign() >= 0 {
+		return nil
+	}
+
+	if bs.CreditLine == nil || (op != model.OperationWithdraw && op != model.OperationTransfer &&
+		op != model.OperationHold && op != model.OperationCapture && op != model.OperationReversal) {
+		return ErrBalanceInsufficientFunds
+	}
+
+	limit, err := bs.CreditLine.Load(ctx, addr, acc, curr)
+	if err != nil {
+		return bs.wrap(ErrBalanceRepository, err)
+	}
+
+	// available + credit limit >= 0
+	if available.Add(available, limit).Sign() < 0 {
+		return ErrBalanceInsufficientFunds
+	}
+
+	return nil
+}
+
+// held returns the sum of the funds reserved by the active holds of the balance.
+func (bs *Balance) held(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, error) {
+	sum := new(big.Int)
+	if bs.Holds == nil {
+		return sum, nil
+	}
+
+	holds, err := bs.Holds.List(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if len(holds) == 0 {
+		return sum, nil
+	}
+
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return nil, ErrBalanceNoTransaction
+	}
+
+	for _, h := range holds {
+		if h.StatusAt(tx.Timestamp) == model.HoldStatusActive {
+			sum.Add(sum, h.Amount.Int())
+		}
+	}
+
+	return sum, nil
+}
+
+// balanceKey identifies a balance of an account.
+type balanceKey struct {
+	addr model.Address
+	acc  model.Account
+	curr model.Currency
+}
+
+// checkAllowList returns ErrBalanceNotAllowListed if the account may be held only by
+// verified addresses and the address has no active allow-list entry.
+func (bs *Balance) checkAllowList(ctx context.Context, addr model.Address, acc model.Account) error {
+	if bs.AllowList == nil || !acc.RequiresAllowList() {
+		return nil
+	}
+
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return ErrBalanceNoTransaction
+	}
+
+	entry, err := bs.AllowList.Load(ctx, addr)
+	if err != nil {
+		return bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if entry == nil || !entry.IsActive(tx.Timestamp, bs.AllowListLevel) {
+		return fmt.Errorf("%w: %s", ErrBalanceNotAllowListed, addr)
+	}
+
+	return nil
+}
+
+// spend checks the outflow limits of the address in the currency and in its class,
+// and records the amount as spent in every limited period of both. The periods are
+// derived from the transaction timestamp. Only Withdraw and Transfer are limited, and
+// they spend once the funds are checked, so a failed operation does not count.
+func (bs *Balance) spend(
+	ctx context.Context,
+	op model.Operation,
+	addr model.Address,
+	curr model.Currency,
+	amt *big.Int,
+) error {
+	if bs.Limit == nil || (op != model.OperationWithdraw && op != model.OperationTransfer) {
+		return nil
+	}
+
+	scopes, err := bs.limitScopes(ctx, addr, curr)
+	if err != nil {
+		return err
+	}
+
+	tx, hasTx := model.TransactionFromContext(ctx)
+
+	// spent amounts are saved only after all the periods of all the scopes are checked
+	var saves []func() error
+
+	for _, sc := range scopes {
+		amt := sc.units(amt)
+		if !sc.limit.AllowsSingle(amt) {
+			return fmt.Errorf("%w: single operation limit of %s is %s", ErrBalanceLimitExceeded, sc.name, sc.limit.Single)
+		}
+
+		for _, p := range model.LimitPeriods {
+			maximum := sc.limit.PeriodLimit(p)
+			if maximum == nil {
+				continue
+			}
+
+			if !hasTx {
+				return ErrBalanceNoTransaction
+			}
+
+			period := p.Key(tx.Timestamp)
+
+			before, err := sc.load(period)
+			if err != nil {
+				return bs.wrap(ErrBalanceRepository, err)
+			}
+
+			// spent = spent + value
+			after := new(big.Int).Add(before, amt)
+
+			if after.Cmp(maximum) > 0 {
+				return fmt.Errorf("%w: limit of %s for period %s is %s", ErrBalanceLimitExceeded, sc.name, period, maximum)
+			}
+
+			save := sc.save
+			saves = append(saves, func() error { return save(period, after) })
+		}
+	}
+
+	for _, save := range saves {
+		if err := save(); err != nil {
+			return bs.wrap(ErrBalanceRepository, err)
+		}
+	}
+
+	return nil
+}
+
+// limitScope is a currency or a currency class with the outflow limits of an address.
+// The limits and the spent amounts of the scope are expressed in its minimal units.
+type limitScope struct {
+	name  string
+	limit *model.Limit
+	units func(amt *big.Int) *big.Int
+	load  func(period string) (*big.Int, error)
+	save  func(period string, val *big.Int) error
+}
+
+// limitScopes returns the limited scopes of the outflow of the address in the
+// currency: the currency itself and its class, if they have limits configured. The
+// amounts in the currency are rescaled to the precision of the class, so the amounts
+// in the currencies of different precisions add up, and the fractions of the minimal
+// unit of the class are rounded up.
+func (bs *Balance) limitScopes(
+	ctx context.Context,
+	addr model.Address,
+	curr model.Currency,
+) ([]limitScope, error) {
+	var out []limitScope
+
+	limit, err := bs.Limit.LoadConfig(ctx, addr, curr)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if limit != nil {
+		out = append(out, limitScope{
+			name:  "currency " + string(curr),
+			limit: limit,
+			units: func(amt *big.Int) *big.Int {
+				return amt
+			},
+			load: func(period string) (*big.Int, error) {
+				return bs.Limit.LoadSpent(ctx, addr, curr, period)
+			},
+			save: func(period string, val *big.Int) error {
+				return bs.Limit.SaveSpent(ctx, addr, curr, period, val)
+			},
+		})
+	}
+
+	class := curr.Class()
+	if class == "" {
+		return out, nil
+	}
+
+	limit, err = bs.Limit.LoadClassConfig(ctx, addr, class)
+	if err != nil {
+		return nil, bs.wrap(ErrBalanceRepository, err)
+	}
+
+	if limit != nil {
+		out = append(out, limitScope{
+			name:  "class " + string(class),
+			limit: limit,
+			units: func(amt *big.Int) *big.Int {
+				return curr.Amount(amt).Ceil(class.Precision()).Int()
+			},
+			load: func(period string) (*big.Int, error) {
+				return bs.Limit.LoadClassSpent(ctx, addr, class, period)
+			},
+			save: func(period string, val *big.Int) error {
+				return bs.Limit.SaveClassSpent(ctx, addr, class, period, val)
+			},
+		})
+	}
+
+	return out, nil
 }
 
 func (bs *Balance) wrap(err, cause error) error {
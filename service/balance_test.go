@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/anoideaopen/token/keyvalue/inmem"
 	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage"
+	repo "github.com/anoideaopen/token/storage/repository/mock"
 	"go.uber.org/mock/gomock"
 )
 
@@ -16,7 +22,7 @@ func TestBalance_Deposit(t *testing.T) {
 		addr model.Address
 		acc  model.Account
 		curr model.Currency
-		val  *big.Int
+		val  *model.Amount
 	}
 	tests := []struct {
 		name    string
@@ -45,22 +51,24 @@ func TestBalance_Deposit(t *testing.T) {
 					).Return(nil),
 				)
 
-				return &Balance{env.repoBalance}
+				return &Balance{Balance: env.repoBalance}
 			}(),
 			args: args{
 				ctx:  ctx,
 				addr: user1.address,
 				acc:  user1.account1.account,
 				curr: user1.account1.currency,
-				val:  big.NewInt(100),
+				val:  amount(100),
 			},
 			want: model.BalanceUpdate{
 				Address:    user1.address,
 				Account:    user1.account1.account,
 				Currency:   "USD",
-				OldValue:   user1.account1.balance,
-				NewValue:   big.NewInt(200),
-				ValueDelta: big.NewInt(100),
+				OldValue:   model.NewAmount(user1.account1.balance, 0),
+				NewValue:   amount(200),
+				ValueDelta: amount(100),
+				Direction:  model.DirectionCredit,
+				Operation:  model.OperationDeposit,
 			},
 			wantErr: false,
 		},
@@ -85,7 +93,7 @@ func TestBalance_Withdraw(t *testing.T) {
 		addr model.Address
 		acc  model.Account
 		curr model.Currency
-		val  *big.Int
+		val  *model.Amount
 	}
 	tests := []struct {
 		name    string
@@ -114,22 +122,24 @@ func TestBalance_Withdraw(t *testing.T) {
 					).Return(nil),
 				)
 
-				return &Balance{env.repoBalance}
+				return &Balance{Balance: env.repoBalance}
 			}(),
 			args: args{
 				ctx:  ctx,
 				addr: user2.address,
 				acc:  user2.account1.account,
 				curr: user2.account1.currency,
-				val:  big.NewInt(100),
+				val:  amount(100),
 			},
 			want: model.BalanceUpdate{
 				Address:    user2.address,
 				Account:    user2.account1.account,
 				Currency:   user2.account1.currency,
-				OldValue:   user2.account1.balance,
-				NewValue:   big.NewInt(200),
-				ValueDelta: big.NewInt(100),
+				OldValue:   model.NewAmount(user2.account1.balance, 0),
+				NewValue:   amount(200),
+				ValueDelta: amount(100),
+				Direction:  model.DirectionDebit,
+				Operation:  model.OperationWithdraw,
 			},
 			wantErr: false,
 		},
@@ -155,7 +165,7 @@ func TestBalance_Transfer(t *testing.T) {
 		addrTo   model.Address
 		acc      model.Account
 		curr     model.Currency
-		val      *big.Int
+		val      *model.Amount
 	}
 	tests := []struct {
 		name    string
@@ -196,7 +206,7 @@ func TestBalance_Transfer(t *testing.T) {
 						big.NewInt(350),
 					).Return(nil),
 				)
-				return &Balance{env.repoBalance}
+				return &Balance{Balance: env.repoBalance}
 			}(),
 			args: args{
 				ctx:      ctx,
@@ -204,24 +214,32 @@ func TestBalance_Transfer(t *testing.T) {
 				addrTo:   user2.address,
 				acc:      user1.account1.account,
 				curr:     user1.account1.currency,
-				val:      big.NewInt(50),
+				val:      amount(50),
 			},
 			want: [2]model.BalanceUpdate{
 				{
-					Address:    user1.address,
-					Account:    user1.account1.account,
-					Currency:   user1.account1.currency,
-					OldValue:   user1.account1.balance,
-					NewValue:   big.NewInt(50),
-					ValueDelta: big.NewInt(50),
+					Address:             user1.address,
+					Account:             user1.account1.account,
+					Currency:            user1.account1.currency,
+					OldValue:            model.NewAmount(user1.account1.balance, 0),
+					NewValue:            amount(50),
+					ValueDelta:          amount(50),
+					Direction:           model.DirectionDebit,
+					Operation:           model.OperationTransfer,
+					Counterparty:        user2.address,
+					CounterpartyAccount: user2.account1.account,
 				},
 				{
-					Address:    user2.address,
-					Account:    user2.account1.account,
-					Currency:   user1.account1.currency,
-					OldValue:   user2.account1.balance,
-					NewValue:   big.NewInt(350),
-					ValueDelta: big.NewInt(50),
+					Address:             user2.address,
+					Account:             user2.account1.account,
+					Currency:            user1.account1.currency,
+					OldValue:            model.NewAmount(user2.account1.balance, 0),
+					NewValue:            amount(350),
+					ValueDelta:          amount(50),
+					Direction:           model.DirectionCredit,
+					Operation:           model.OperationTransfer,
+					Counterparty:        user1.address,
+					CounterpartyAccount: user1.account1.account,
 				},
 			},
 			wantErr: false,
@@ -248,7 +266,7 @@ func TestBalance_InternalTransfer(t *testing.T) {
 		accFrom model.Account
 		accTo   model.Account
 		curr    model.Currency
-		val     *big.Int
+		val     *model.Amount
 	}
 	tests := []struct {
 		name    string
@@ -294,7 +312,7 @@ func TestBalance_transfer(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.bs.transfer(tt.args.ctx, tt.args.addrFrom, tt.args.addrTo, tt.args.accFrom, tt.args.accTo, tt.args.curr, tt.args.amt)
+			got, err := tt.bs.transfer(tt.args.ctx, model.OperationTransfer, tt.args.addrFrom, tt.args.addrTo, tt.args.accFrom, tt.args.accTo, tt.args.curr, tt.args.amt)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Balance.transfer() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -305,3 +323,1238 @@ func TestBalance_transfer(t *testing.T) {
 		})
 	}
 }
+
+func TestBalance_ForcedTransfer(t *testing.T) {
+	regulator := model.Address("2dngBVTF93Fm6dsjbd3yLaVu3AtbBEgbNmVYxTCnm9Kkj5cVbo")
+	order := model.ForcedTransferOrder{
+		ID:         "order1",
+		Regulator:  regulator,
+		DocumentID: "court-2024-001",
+		Reason:     "court order",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		want := [2]model.BalanceUpdate{
+			{
+				Address:             user1.address,
+				Account:             user1.account1.account,
+				Currency:            user1.account1.currency,
+				OldValue:            model.NewAmount(user1.account1.balance, 0),
+				NewValue:            amount(40),
+				ValueDelta:          amount(60),
+				Direction:           model.DirectionDebit,
+				Operation:           model.OperationForcedTransfer,
+				Counterparty:        user2.address,
+				CounterpartyAccount: user2.account1.account,
+			},
+			{
+				Address:             user2.address,
+				Account:             user2.account1.account,
+				Currency:            user1.account1.currency,
+				OldValue:            model.NewAmount(user2.account1.balance, 0),
+				NewValue:            amount(360),
+				ValueDelta:          amount(60),
+				Direction:           model.DirectionCredit,
+				Operation:           model.OperationForcedTransfer,
+				Counterparty:        user1.address,
+				CounterpartyAccount: user1.account1.account,
+			},
+		}
+
+		gomock.InOrder(
+			env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil),
+			env.repoBalance.EXPECT().Load(
+				gomock.Any(),
+				user1.address,
+				user1.account1.account,
+				user1.account1.currency,
+			).Return(user1.account1.balance, nil),
+			env.repoBalance.EXPECT().Load(
+				gomock.Any(),
+				user2.address,
+				user2.account1.account,
+				user2.account1.currency,
+			).Return(user2.account1.balance, nil),
+			env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency, big.NewInt(40)).Return(nil),
+			env.repoBalance.EXPECT().Save(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency, big.NewInt(360)).Return(nil),
+			env.repoNotification.EXPECT().SaveForcedTransfer(gomock.Any(), model.Notification[model.ForcedTransfer]{
+				ID:   order.ID,
+				Type: model.NotificationTypeForcedTransfer,
+				Body: model.ForcedTransfer{
+					ForcedTransferOrder: order,
+					Updates:             want[:],
+				},
+			}).Return(nil),
+		)
+
+		got, err := bs.ForcedTransfer(ctx, order, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(60))
+		env.assert.NoError(err)
+		env.assert.Equal(want, got)
+	})
+
+	t.Run("not a regulator", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(false, nil)
+
+		_, err := bs.ForcedTransfer(ctx, order, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(60))
+		env.assert.ErrorIs(err, ErrBalanceForbidden)
+	})
+
+	t.Run("missing document", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		invalid := order
+		invalid.DocumentID = ""
+
+		_, err := bs.ForcedTransfer(ctx, invalid, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(60))
+		env.assert.ErrorIs(err, ErrBalanceInvalidOrder)
+	})
+}
+
+func TestBalance_TransferFrozen(t *testing.T) {
+	env := newEnvironment(t)
+	bs := &Balance{
+		Balance: env.repoBalance,
+		Access:  env.repoAccess,
+	}
+
+	env.repoAccess.EXPECT().IsFrozen(gomock.Any(), user1.address).Return(true, nil)
+
+	_, err := bs.Transfer(ctx, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(50))
+	env.assert.ErrorIs(err, ErrBalanceAddressFrozen)
+}
+
+func TestBalance_WithdrawLimit(t *testing.T) {
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{
+		ID:        "tx1",
+		Timestamp: time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC),
+	})
+	limit := &model.Limit{
+		Single: big.NewInt(100),
+		Daily:  big.NewInt(150),
+	}
+
+	t.Run("success", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance: env.repoBalance,
+			Limit:   env.repoLimit,
+		}
+
+		gomock.InOrder(
+			env.repoBalance.EXPECT().Load(
+				gomock.Any(),
+				user2.address,
+				user2.account1.account,
+				user2.account1.currency,
+			).Return(user2.account1.balance, nil),
+			env.repoLimit.EXPECT().LoadConfig(gomock.Any(), user2.address, user2.account1.currency).Return(limit, nil),
+			env.repoLimit.EXPECT().LoadSpent(gomock.Any(), user2.address, user2.account1.currency, "D20240131").Return(big.NewInt(50), nil),
+			env.repoLimit.EXPECT().SaveSpent(gomock.Any(), user2.address, user2.account1.currency, "D20240131", big.NewInt(150)).Return(nil),
+			env.repoBalance.EXPECT().Save(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency, big.NewInt(200)).Return(nil),
+		)
+
+		_, err := bs.Withdraw(txCtx, user2.address, user2.account1.account, user2.account1.currency, amount(100))
+		env.assert.NoError(err)
+	})
+
+	t.Run("single limit exceeded", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance: env.repoBalance,
+			Limit:   env.repoLimit,
+		}
+
+		env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(user2.account1.balance, nil)
+		env.repoLimit.EXPECT().LoadConfig(gomock.Any(), user2.address, user2.account1.currency).Return(limit, nil)
+
+		_, err := bs.Withdraw(txCtx, user2.address, user2.account1.account, user2.account1.currency, amount(101))
+		env.assert.ErrorIs(err, ErrBalanceLimitExceeded)
+	})
+
+	t.Run("daily limit exceeded", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance: env.repoBalance,
+			Limit:   env.repoLimit,
+		}
+
+		gomock.InOrder(
+			env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+				Return(user2.account1.balance, nil),
+			env.repoLimit.EXPECT().LoadConfig(gomock.Any(), user2.address, user2.account1.currency).Return(limit, nil),
+			env.repoLimit.EXPECT().LoadSpent(gomock.Any(), user2.address, user2.account1.currency, "D20240131").Return(big.NewInt(100), nil),
+		)
+
+		_, err := bs.Withdraw(txCtx, user2.address, user2.account1.account, user2.account1.currency, amount(100))
+		env.assert.ErrorIs(err, ErrBalanceLimitExceeded)
+	})
+
+	t.Run("no transaction", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance: env.repoBalance,
+			Limit:   env.repoLimit,
+		}
+
+		env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(user2.account1.balance, nil)
+		env.repoLimit.EXPECT().LoadConfig(gomock.Any(), user2.address, user2.account1.currency).Return(limit, nil)
+
+		_, err := bs.Withdraw(ctx, user2.address, user2.account1.account, user2.account1.currency, amount(100))
+		env.assert.ErrorIs(err, ErrBalanceNoTransaction)
+	})
+
+	t.Run("insufficient funds are not spent", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance: env.repoBalance,
+			Limit:   env.repoLimit,
+		}
+
+		env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(big.NewInt(10), nil)
+
+		_, err := bs.Withdraw(txCtx, user2.address, user2.account1.account, user2.account1.currency, amount(100))
+		env.assert.ErrorIs(err, ErrBalanceInsufficientFunds)
+	})
+}
+
+func TestBalance_ClassLimit(t *testing.T) {
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{
+		ID:        "tx1",
+		Timestamp: time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC),
+	})
+
+	const class = model.CurrencyClass("stable")
+	for _, curr := range []model.Currency{"USDA", "USDB"} {
+		model.RegisterCurrencyClass(curr, class)
+	}
+
+	db := new(inmem.KeyValueDB)
+	limits := &storage.Limit{Object: storage.Object{DB: db}}
+	bs := &Balance{Balance: &storage.Balance{DB: db}, Limit: limits}
+
+	env := newEnvironment(t)
+	env.assert.NoError(limits.SaveClassConfig(ctx, "", class, &model.Limit{Daily: big.NewInt(150)}))
+	env.assert.NoError(limits.SaveConfig(ctx, "", "USDA", &model.Limit{Single: big.NewInt(100)}))
+
+	for _, curr := range []model.Currency{"USDA", "USDB"} {
+		_, err := bs.Deposit(txCtx, user1.address, model.AccountToken, curr, amount(1000))
+		env.assert.NoError(err)
+	}
+
+	_, err := bs.Withdraw(txCtx, user1.address, model.AccountToken, "USDA", amount(101))
+	env.assert.ErrorIs(err, ErrBalanceLimitExceeded)
+
+	_, err = bs.Withdraw(txCtx, user1.address, model.AccountToken, "USDA", amount(100))
+	env.assert.NoError(err)
+
+	// the outflow in the other currency of the class counts toward the same limit
+	_, err = bs.Transfer(txCtx, user1.address, user2.address, model.AccountToken, "USDB", amount(51))
+	env.assert.ErrorIs(err, ErrBalanceLimitExceeded)
+
+	_, err = bs.Transfer(txCtx, user1.address, user2.address, model.AccountToken, "USDB", amount(50))
+	env.assert.NoError(err)
+
+	spent, err := limits.LoadClassSpent(ctx, user1.address, class, "D20240131")
+	env.assert.NoError(err)
+	env.assert.Equal(big.NewInt(150), spent)
+
+	// the amounts in the currencies of different precisions are rescaled to the
+	// precision of the class: the limit is 1.00
+	const fine = model.CurrencyClass("fine")
+
+	model.RegisterCurrencyClassPrecision(fine, 2)
+	for curr, precision := range map[model.Currency]uint8{"FINEA": 2, "FINEB": 8} {
+		model.RegisterCurrency(curr, precision)
+		model.RegisterCurrencyClass(curr, fine)
+
+		_, err := bs.Deposit(txCtx, user1.address, model.AccountToken, curr, model.NewAmount(big.NewInt(1), 0))
+		env.assert.NoError(err)
+	}
+
+	env.assert.NoError(limits.SaveClassConfig(ctx, "", fine, &model.Limit{Daily: big.NewInt(100)}))
+
+	_, err = bs.Withdraw(txCtx, user1.address, model.AccountToken, "FINEA", model.NewAmount(big.NewInt(60), 2))
+	env.assert.NoError(err)
+
+	_, err = bs.Withdraw(txCtx, user1.address, model.AccountToken, "FINEB", model.NewAmount(big.NewInt(40_000_000), 8))
+	env.assert.NoError(err)
+
+	// a fraction of the minimal unit of the class is rounded up
+	_, err = bs.Withdraw(txCtx, user1.address, model.AccountToken, "FINEB", model.NewAmount(big.NewInt(1), 8))
+	env.assert.ErrorIs(err, ErrBalanceLimitExceeded)
+
+	spent, err = limits.LoadClassSpent(ctx, user1.address, fine, "D20240131")
+	env.assert.NoError(err)
+	env.assert.Equal(big.NewInt(100), spent)
+}
+
+func TestBalance_DepositAllowList(t *testing.T) {
+	now := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1", Timestamp: now})
+
+	tests := []struct {
+		name    string
+		entry   *model.AllowListEntry
+		wantErr error
+	}{
+		{
+			name: "verified",
+			entry: &model.AllowListEntry{
+				Address:   user1.address,
+				Level:     2,
+				ExpiresAt: now.Add(time.Hour),
+			},
+		},
+		{
+			name:    "not verified",
+			wantErr: ErrBalanceNotAllowListed,
+		},
+		{
+			name: "expired",
+			entry: &model.AllowListEntry{
+				Address:   user1.address,
+				Level:     2,
+				ExpiresAt: now,
+			},
+			wantErr: ErrBalanceNotAllowListed,
+		},
+		{
+			name: "insufficient level",
+			entry: &model.AllowListEntry{
+				Address: user1.address,
+				Level:   1,
+			},
+			wantErr: ErrBalanceNotAllowListed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newEnvironment(t)
+			bs := &Balance{
+				Balance:        env.repoBalance,
+				AllowList:      env.repoAllowList,
+				AllowListLevel: 2,
+			}
+
+			env.repoAllowList.EXPECT().Load(gomock.Any(), user1.address).Return(tt.entry, nil)
+			if tt.wantErr == nil {
+				env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency).
+					Return(user1.account1.balance, nil)
+				env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency, big.NewInt(200)).
+					Return(nil)
+			}
+
+			_, err := bs.Deposit(txCtx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+			if tt.wantErr == nil {
+				env.assert.NoError(err)
+			} else {
+				env.assert.ErrorIs(err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBalance_OperationNotPermitted(t *testing.T) {
+	const accountBonus model.Account = 1001
+
+	if err := model.RegisterAccount(model.AccountType{
+		Account:    accountBonus,
+		Name:       "AccountBonus",
+		Operations: model.OperationDeposit | model.OperationWithdraw,
+	}); err != nil && !errors.Is(err, model.ErrAccountRegistered) {
+		t.Fatal(err)
+	}
+
+	env := newEnvironment(t)
+	bs := &Balance{Balance: env.repoBalance}
+
+	_, err := bs.Transfer(ctx, user1.address, user2.address, accountBonus, user1.account1.currency, amount(50))
+	env.assert.ErrorIs(err, ErrBalanceOperationNotPermitted)
+
+	_, err = bs.Deposit(ctx, user1.address, 1002, user1.account1.currency, amount(50))
+	env.assert.ErrorIs(err, ErrBalanceOperationNotPermitted)
+
+	env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, accountBonus, user1.account1.currency).Return(big.NewInt(0), nil)
+	env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, accountBonus, user1.account1.currency, big.NewInt(50)).Return(nil)
+
+	_, err = bs.Deposit(ctx, user1.address, accountBonus, user1.account1.currency, amount(50))
+	env.assert.NoError(err)
+}
+
+func TestBalance_LockedAccount(t *testing.T) {
+	db := new(inmem.KeyValueDB)
+	bs := &Balance{Balance: &storage.Balance{DB: db}}
+
+	env := newEnvironment(t)
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1"})
+
+	const addr = model.Address("a")
+
+	// the funds get on a locked account only from the account they are locked of
+	_, err := bs.Deposit(txCtx, addr, model.AccountTokenLocked, "USD", amount(10))
+	env.assert.ErrorIs(err, ErrBalanceOperationNotPermitted)
+
+	_, err = bs.Deposit(txCtx, addr, model.AccountToken, "USD", amount(10))
+	env.assert.NoError(err)
+
+	_, err = bs.InternalTransfer(txCtx, addr, model.AccountToken, model.AccountAllowedLocked, "USD", amount(4))
+	env.assert.ErrorIs(err, ErrBalanceOperationNotPermitted)
+
+	_, err = bs.InternalTransfer(txCtx, addr, model.AccountToken, model.AccountTokenLocked, "USD", amount(4))
+	env.assert.NoError(err)
+
+	// locked funds cannot be spent
+	_, err = bs.Withdraw(txCtx, addr, model.AccountTokenLocked, "USD", amount(1))
+	env.assert.ErrorIs(err, ErrBalanceOperationNotPermitted)
+
+	_, err = bs.Transfer(txCtx, addr, "b", model.AccountTokenLocked, "USD", amount(1))
+	env.assert.ErrorIs(err, ErrBalanceOperationNotPermitted)
+
+	bu, err := bs.InternalTransfer(txCtx, addr, model.AccountTokenLocked, model.AccountToken, "USD", amount(3))
+	env.assert.NoError(err)
+
+	// the regulator reverses the operations of the locked accounts as well
+	const regulator = model.Address("regulator")
+
+	notifications := &storage.Notification{Object: storage.Object{DB: db}}
+	bs.Access = env.repoAccess
+	bs.Notification = notifications
+
+	body, err := json.Marshal(bu[:])
+	env.assert.NoError(err)
+	env.assert.NoError(notifications.SaveRaw(ctx, &model.RawNotification{
+		ID:   "op1",
+		Type: model.NotificationTypeBalancesUpdate,
+		Body: body,
+	}))
+
+	env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil)
+	env.repoAccess.EXPECT().IsFrozen(gomock.Any(), addr).Return(false, nil).AnyTimes()
+
+	_, err = bs.Reverse(txCtx, regulator, model.NotificationTypeBalancesUpdate, "op1")
+	env.assert.NoError(err)
+
+	_, err = bs.InternalTransfer(txCtx, addr, model.AccountTokenLocked, model.AccountToken, "USD", amount(3))
+	env.assert.NoError(err)
+
+	for acc, expected := range map[model.Account]int64{model.AccountToken: 9, model.AccountTokenLocked: 1} {
+		balance, err := bs.Fetch(ctx, addr, acc, "USD")
+		env.assert.NoError(err)
+		env.assert.Equal(amount(expected), balance)
+	}
+}
+
+func TestBalance_DepositPrecision(t *testing.T) {
+	const curr model.Currency = "EUR"
+
+	model.RegisterCurrency(curr, 2)
+
+	env := newEnvironment(t)
+	bs := &Balance{Balance: env.repoBalance}
+
+	overPrecise, err := model.ParseAmount("1.005", 3)
+	env.assert.NoError(err)
+
+	_, err = bs.Deposit(ctx, user1.address, user1.account1.account, curr, overPrecise)
+	env.assert.ErrorIs(err, ErrBalanceInvalidAmount)
+	env.assert.ErrorContains(err, model.ErrAmountPrecision.Error())
+
+	gomock.InOrder(
+		env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, user1.account1.account, curr).Return(big.NewInt(100), nil),
+		env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user1.account1.account, curr, big.NewInt(250)).Return(nil),
+	)
+
+	value, err := curr.ParseAmount("1.5")
+	env.assert.NoError(err)
+
+	got, err := bs.Deposit(ctx, user1.address, user1.account1.account, curr, value)
+	env.assert.NoError(err)
+	env.assert.Equal("1.00", got.OldValue.String())
+	env.assert.Equal("2.50", got.NewValue.String())
+	env.assert.Equal("1.50", got.ValueDelta.String())
+}
+
+func TestBalance_WithdrawOverdraft(t *testing.T) {
+	tests := []struct {
+		name    string
+		credit  int64
+		want    *model.Amount
+		wantErr error
+	}{
+		{
+			name:   "within credit line",
+			credit: 50,
+			want:   amount(-50),
+		},
+		{
+			name:    "exceeds credit line",
+			credit:  49,
+			wantErr: ErrBalanceInsufficientFunds,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newEnvironment(t)
+			bs := &Balance{
+				Balance:    env.repoBalance,
+				CreditLine: env.repoCreditLine,
+			}
+
+			env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency).
+				Return(user1.account1.balance, nil)
+			env.repoCreditLine.EXPECT().Load(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency).
+				Return(big.NewInt(tt.credit), nil)
+			if tt.wantErr == nil {
+				env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency, big.NewInt(-50)).
+					Return(nil)
+			}
+
+			got, err := bs.Withdraw(ctx, user1.address, user1.account1.account, user1.account1.currency, amount(150))
+			if tt.wantErr != nil {
+				env.assert.ErrorIs(err, tt.wantErr)
+				return
+			}
+
+			env.assert.NoError(err)
+			env.assert.Equal(tt.want, got.NewValue)
+		})
+	}
+}
+
+func TestBalance_OverdraftPerAccount(t *testing.T) {
+	db := new(inmem.KeyValueDB)
+	credit := &storage.CreditLine{DB: db}
+	bs := &Balance{Balance: &storage.Balance{DB: db}, CreditLine: credit}
+
+	env := newEnvironment(t)
+	env.assert.NoError(credit.Save(ctx, user1.address, model.AccountToken, "USD", big.NewInt(100)))
+
+	// the credit line of one account does not let the other ones overdraw
+	_, err := bs.Withdraw(ctx, user1.address, model.AccountAllowed, "USD", amount(1))
+	env.assert.ErrorIs(err, ErrBalanceInsufficientFunds)
+
+	_, err = bs.Withdraw(ctx, user1.address, model.AccountToken, "USD", amount(100))
+	env.assert.NoError(err)
+}
+
+func TestBalance_DepositAccumulate(t *testing.T) {
+	now := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	txCtx := model.ContextWithMemo(model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1", Timestamp: now}), "payroll")
+
+	env := newEnvironment(t)
+	balances := repo.NewMockBalance(env.ctrlGomock)
+	bs := &Balance{Balance: balances}
+
+	balances.EXPECT().Accumulates().Return(true)
+	balances.EXPECT().Credit(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency, big.NewInt(100)).
+		Return(nil)
+
+	got, err := bs.Deposit(txCtx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+	env.assert.NoError(err)
+	env.assert.Equal(model.BalanceUpdate{
+		Address:    user1.address,
+		Account:    user1.account1.account,
+		Currency:   user1.account1.currency,
+		ValueDelta: amount(100),
+		Direction:  model.DirectionCredit,
+		Operation:  model.OperationDeposit,
+		TxID:       "tx1",
+		Timestamp:  now,
+		Memo:       "payroll",
+	}, got)
+	env.assert.NoError(model.BalancesUpdate{got}.Validate())
+}
+
+func TestBalance_Hold(t *testing.T) {
+	now := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1", Timestamp: now})
+
+	active := func(amt, captured int64) *model.Hold {
+		return &model.Hold{
+			ID:        "hold1",
+			Address:   user2.address,
+			Account:   user2.account1.account,
+			Currency:  user2.account1.currency,
+			Merchant:  user1.address,
+			Amount:    amount(amt),
+			Captured:  amount(captured),
+			ExpiresAt: now.Add(time.Hour),
+			Status:    model.HoldStatusActive,
+		}
+	}
+
+	t.Run("hold", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		other := active(100, 0)
+		other.ID = "hold0"
+
+		gomock.InOrder(
+			env.repoHold.EXPECT().Load(gomock.Any(), "hold1").Return(nil, nil),
+			env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+				Return(user2.account1.balance, nil),
+			env.repoHold.EXPECT().List(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+				Return([]*model.Hold{other}, nil),
+			env.repoHold.EXPECT().Save(gomock.Any(), active(200, 0)).Return(nil),
+		)
+
+		got, err := bs.Hold(txCtx, "hold1", user2.address, user2.account1.account, user2.account1.currency, user1.address, amount(200), now.Add(time.Hour))
+		env.assert.NoError(err)
+		env.assert.Equal(active(200, 0), got)
+	})
+
+	t.Run("insufficient available funds", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		other := active(200, 0)
+		other.ID = "hold0"
+
+		env.repoHold.EXPECT().Load(gomock.Any(), "hold1").Return(nil, nil)
+		env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(user2.account1.balance, nil)
+		env.repoHold.EXPECT().List(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return([]*model.Hold{other}, nil)
+
+		_, err := bs.Hold(txCtx, "hold1", user2.address, user2.account1.account, user2.account1.currency, user1.address, amount(101), now.Add(time.Hour))
+		env.assert.ErrorIs(err, ErrBalanceInsufficientFunds)
+	})
+
+	t.Run("partial capture", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		gomock.InOrder(
+			env.repoHold.EXPECT().Load(gomock.Any(), "hold1").Return(active(200, 0), nil),
+			env.repoHold.EXPECT().Save(gomock.Any(), active(150, 50)).Return(nil),
+			env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+				Return(user2.account1.balance, nil),
+			env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, user2.account1.account, user2.account1.currency).
+				Return(user1.account1.balance, nil),
+			env.repoBalance.EXPECT().Save(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency, big.NewInt(250)).
+				Return(nil),
+			env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user2.account1.account, user2.account1.currency, big.NewInt(150)).
+				Return(nil),
+		)
+
+		_, err := bs.Capture(txCtx, user1.address, "hold1", amount(50))
+		env.assert.NoError(err)
+	})
+
+	t.Run("invalid hold", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		_, err := bs.Hold(txCtx, "hold1", user2.address, user2.account1.account, user2.account1.currency, user1.address, amount(100), now)
+		env.assert.ErrorIs(err, ErrBalanceInvalidHold)
+
+		_, err = bs.Hold(txCtx, "hold1", user2.address, user2.account1.account, user2.account1.currency, user2.address, amount(100), now.Add(time.Hour))
+		env.assert.ErrorIs(err, ErrBalanceInvalidHold)
+	})
+
+	t.Run("capture by other", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		env.repoHold.EXPECT().Load(gomock.Any(), "hold1").Return(active(200, 0), nil)
+
+		_, err := bs.Capture(txCtx, user2.address, "hold1", amount(50))
+		env.assert.ErrorIs(err, ErrBalanceForbidden)
+	})
+
+	t.Run("void", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		voided := active(200, 0)
+		voided.Status = model.HoldStatusVoided
+
+		env.repoHold.EXPECT().Load(gomock.Any(), "hold1").Return(active(200, 0), nil).Times(2)
+		env.repoHold.EXPECT().Save(gomock.Any(), voided).Return(nil)
+
+		_, err := bs.Void(txCtx, "stranger", "hold1")
+		env.assert.ErrorIs(err, ErrBalanceForbidden)
+
+		got, err := bs.Void(txCtx, user2.address, "hold1")
+		env.assert.NoError(err)
+		env.assert.Equal(voided, got)
+	})
+
+	t.Run("capture expired", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		expired := active(200, 0)
+		expired.ExpiresAt = now
+
+		stored := active(200, 0)
+		stored.ExpiresAt = now
+		stored.Status = model.HoldStatusExpired
+
+		env.repoHold.EXPECT().Load(gomock.Any(), "hold1").Return(expired, nil)
+		env.repoHold.EXPECT().Save(gomock.Any(), stored).Return(nil)
+
+		_, err := bs.Capture(txCtx, user1.address, "hold1", amount(50))
+		env.assert.ErrorIs(err, ErrBalanceHoldNotActive)
+	})
+
+	t.Run("withdraw held funds", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{Balance: env.repoBalance, Holds: env.repoHold}
+
+		env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(user2.account1.balance, nil)
+		env.repoHold.EXPECT().List(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return([]*model.Hold{active(200, 0)}, nil)
+
+		_, err := bs.Withdraw(txCtx, user2.address, user2.account1.account, user2.account1.currency, amount(101))
+		env.assert.ErrorIs(err, ErrBalanceInsufficientFunds)
+	})
+}
+
+func TestBalance_Reverse(t *testing.T) {
+	regulator := model.Address("2dngBVTF93Fm6dsjbd3yLaVu3AtbBEgbNmVYxTCnm9Kkj5cVbo")
+	body, _ := json.Marshal(model.BalancesUpdate{
+		{
+			Address:    user1.address,
+			Account:    user1.account1.account,
+			Currency:   user1.account1.currency,
+			OldValue:   amount(150),
+			NewValue:   amount(100),
+			ValueDelta: amount(50),
+		},
+		{
+			Address:    user2.address,
+			Account:    user2.account1.account,
+			Currency:   user2.account1.currency,
+			OldValue:   amount(250),
+			NewValue:   amount(300),
+			ValueDelta: amount(50),
+		},
+	})
+	original := &model.RawNotification{ID: "op1", Type: "transfer", Body: body}
+
+	t.Run("success", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		want := model.BalancesUpdate{
+			{
+				Address:    user1.address,
+				Account:    user1.account1.account,
+				Currency:   user1.account1.currency,
+				OldValue:   amount(100),
+				NewValue:   amount(150),
+				ValueDelta: amount(50),
+				Direction:  model.DirectionCredit,
+				Operation:  model.OperationReversal,
+			},
+			{
+				Address:    user2.address,
+				Account:    user2.account1.account,
+				Currency:   user2.account1.currency,
+				OldValue:   amount(300),
+				NewValue:   amount(250),
+				ValueDelta: amount(50),
+				Direction:  model.DirectionDebit,
+				Operation:  model.OperationReversal,
+			},
+		}
+
+		gomock.InOrder(
+			env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil),
+			env.repoNotification.EXPECT().Load(gomock.Any(), "transfer", "op1").Return(original, nil),
+			env.repoNotification.EXPECT().LoadReversal(gomock.Any(), "transfer:op1").Return(nil, nil),
+			env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency).
+				Return(big.NewInt(100), nil),
+			env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency, big.NewInt(150)).
+				Return(nil),
+			env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+				Return(big.NewInt(300), nil),
+			env.repoBalance.EXPECT().Save(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency, big.NewInt(250)).
+				Return(nil),
+			env.repoNotification.EXPECT().SaveReversal(gomock.Any(), model.Notification[model.Reversal]{
+				ID:   "transfer:op1",
+				Type: model.NotificationTypeReversal,
+				Body: model.Reversal{
+					OriginalType: "transfer",
+					OriginalID:   "op1",
+					Updates:      want,
+				},
+			}).Return(nil),
+		)
+
+		got, err := bs.Reverse(ctx, regulator, "transfer", "op1")
+		env.assert.NoError(err)
+		env.assert.Equal(want, got)
+	})
+
+	t.Run("forced transfer", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		var updates model.BalancesUpdate
+		env.assert.NoError(json.Unmarshal(body, &updates))
+
+		ft, _ := json.Marshal(model.ForcedTransfer{
+			ForcedTransferOrder: model.ForcedTransferOrder{
+				ID:         "order1",
+				Regulator:  regulator,
+				DocumentID: "doc1",
+				Reason:     "court order",
+			},
+			Updates: updates,
+		})
+
+		env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil)
+		env.repoNotification.EXPECT().Load(gomock.Any(), model.NotificationTypeForcedTransfer, "order1").
+			Return(&model.RawNotification{ID: "order1", Type: model.NotificationTypeForcedTransfer, Body: ft}, nil)
+		env.repoNotification.EXPECT().LoadReversal(gomock.Any(), "ForcedTransfer:order1").Return(nil, nil)
+		env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency).
+			Return(big.NewInt(100), nil)
+		env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency, big.NewInt(150)).
+			Return(nil)
+		env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(big.NewInt(300), nil)
+		env.repoBalance.EXPECT().Save(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency, big.NewInt(250)).
+			Return(nil)
+		env.repoNotification.EXPECT().SaveReversal(gomock.Any(), gomock.Any()).Return(nil)
+
+		got, err := bs.Reverse(ctx, regulator, model.NotificationTypeForcedTransfer, "order1")
+		env.assert.NoError(err)
+		env.assert.Len(got, 2)
+	})
+
+	t.Run("not reversible", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		mint, _ := json.Marshal(model.NFTEvent{Collection: "art", TokenID: "1", To: user1.address})
+
+		env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil).Times(2)
+		env.repoNotification.EXPECT().Load(gomock.Any(), model.NotificationTypeNFTMint, "mint1").
+			Return(&model.RawNotification{ID: "mint1", Type: model.NotificationTypeNFTMint, Body: mint}, nil)
+		env.repoNotification.EXPECT().Load(gomock.Any(), model.NotificationTypeReversal, "transfer:op1").
+			Return(&model.RawNotification{ID: "transfer:op1", Type: model.NotificationTypeReversal, Body: []byte(`{}`)}, nil)
+
+		_, err := bs.Reverse(ctx, regulator, model.NotificationTypeNFTMint, "mint1")
+		env.assert.ErrorIs(err, ErrBalanceNotReversible)
+
+		_, err = bs.Reverse(ctx, regulator, model.NotificationTypeReversal, "transfer:op1")
+		env.assert.ErrorIs(err, ErrBalanceNotReversible)
+	})
+
+	t.Run("already reversed", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil)
+		env.repoNotification.EXPECT().Load(gomock.Any(), "transfer", "op1").Return(original, nil)
+		env.repoNotification.EXPECT().LoadReversal(gomock.Any(), "transfer:op1").
+			Return(&model.Notification[model.Reversal]{ID: "transfer:op1"}, nil)
+
+		_, err := bs.Reverse(ctx, regulator, "transfer", "op1")
+		env.assert.ErrorIs(err, ErrBalanceAlreadyReversed)
+	})
+
+	t.Run("credit line", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+			CreditLine:   env.repoCreditLine,
+		}
+
+		env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil)
+		env.repoNotification.EXPECT().Load(gomock.Any(), "transfer", "op1").Return(original, nil)
+		env.repoNotification.EXPECT().LoadReversal(gomock.Any(), "transfer:op1").Return(nil, nil)
+		env.repoBalance.EXPECT().Load(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency).
+			Return(big.NewInt(100), nil)
+		env.repoBalance.EXPECT().Save(gomock.Any(), user1.address, user1.account1.account, user1.account1.currency, big.NewInt(150)).
+			Return(nil)
+		env.repoBalance.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(big.NewInt(20), nil)
+		env.repoCreditLine.EXPECT().Load(gomock.Any(), user2.address, user2.account1.account, user2.account1.currency).
+			Return(big.NewInt(20), nil)
+
+		_, err := bs.Reverse(ctx, regulator, "transfer", "op1")
+		env.assert.ErrorIs(err, ErrBalanceInsufficientFunds)
+	})
+
+	t.Run("not permitted", func(t *testing.T) {
+		const accountFinal model.Account = 1003
+
+		if err := model.RegisterAccount(model.AccountType{
+			Account:    accountFinal,
+			Name:       "AccountFinal",
+			Operations: model.OperationDeposit | model.OperationTransfer,
+		}); err != nil && !errors.Is(err, model.ErrAccountRegistered) {
+			t.Fatal(err)
+		}
+
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		final, _ := json.Marshal(model.BalancesUpdate{
+			{
+				Address:    user1.address,
+				Account:    accountFinal,
+				Currency:   user1.account1.currency,
+				OldValue:   amount(0),
+				NewValue:   amount(50),
+				ValueDelta: amount(50),
+			},
+		})
+
+		env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil)
+		env.repoNotification.EXPECT().Load(gomock.Any(), "deposit", "op3").
+			Return(&model.RawNotification{ID: "op3", Type: "deposit", Body: final}, nil)
+		env.repoNotification.EXPECT().LoadReversal(gomock.Any(), "deposit:op3").Return(nil, nil)
+
+		_, err := bs.Reverse(ctx, regulator, "deposit", "op3")
+		env.assert.ErrorIs(err, ErrBalanceOperationNotPermitted)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		env := newEnvironment(t)
+		bs := &Balance{
+			Balance:      env.repoBalance,
+			Access:       env.repoAccess,
+			Notification: env.repoNotification,
+		}
+
+		env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil)
+		env.repoNotification.EXPECT().Load(gomock.Any(), "transfer", "op2").Return(nil, nil)
+
+		_, err := bs.Reverse(ctx, regulator, "transfer", "op2")
+		env.assert.ErrorIs(err, ErrBalanceOperationNotFound)
+	})
+}
+
+func TestBalance_Distribute(t *testing.T) {
+	env := newEnvironment(t)
+
+	db := new(inmem.KeyValueDB)
+	snapshots := &storage.Snapshot{Object: storage.Object{DB: db}}
+	balances := &storage.Balance{DB: db, Snapshots: snapshots, Registry: &storage.Holder{DB: db}}
+
+	bs := &Balance{
+		Balance:       balances,
+		Snapshots:     snapshots,
+		Distributions: &storage.Distribution{Object: storage.Object{DB: db}},
+	}
+
+	const (
+		issuer = model.Address("issuer")
+		acc    = model.AccountToken
+		curr   = model.Currency("USD")
+		ref    = model.Currency("BOND")
+	)
+
+	tx := func(id string) context.Context {
+		return model.ContextWithTransaction(ctx, model.Transaction{ID: id})
+	}
+
+	for addr, holding := range map[model.Address]int64{"a": 1, "b": 1, "c": 1, issuer: 5} {
+		env.assert.NoError(balances.Save(tx("tx0"), addr, acc, ref, big.NewInt(holding)))
+	}
+
+	env.assert.NoError(balances.Save(tx("tx0"), issuer, acc, curr, big.NewInt(150)))
+
+	dist, bu, err := bs.Distribute(tx("tx1"), "coupon1", issuer, acc, curr, ref, amount(100), 2)
+	env.assert.NoError(err)
+	env.assert.Equal(model.DistributionStatusActive, dist.Status)
+	env.assert.Equal(amount(3), dist.Supply)
+	env.assert.Equal(amount(66), dist.Paid)
+
+	// the whole amount is escrowed, and the batch is paid from the escrow
+	env.assert.Len(bu, 5)
+	env.assert.Equal(issuer, bu[0].Address)
+	env.assert.Equal(amount(100), bu[0].ValueDelta)
+	env.assert.Equal(dist.Escrow(), bu[2].Address)
+	env.assert.Equal(model.DirectionDebit, bu[2].Direction)
+	env.assert.Equal(amount(66), bu[2].ValueDelta)
+
+	_, _, err = bs.Distribute(tx("tx2"), "coupon1", issuer, acc, curr, ref, amount(100), 2)
+	env.assert.ErrorIs(err, ErrBalanceDistributionExists)
+
+	// the holdings changed after the distribution started are not taken into account
+	env.assert.NoError(balances.Save(tx("tx3"), "b", acc, ref, big.NewInt(0)))
+	env.assert.NoError(balances.Save(tx("tx3"), "c", acc, ref, big.NewInt(2)))
+
+	dist, bu, err = bs.ContinueDistribution(tx("tx4"), "coupon1", 3)
+	env.assert.NoError(err)
+	env.assert.Equal(model.DistributionStatusCompleted, dist.Status)
+	env.assert.Equal(amount(100), dist.Paid)
+	env.assert.Len(bu, 2)
+
+	// the holders are credited in the order of the registry buckets: "a", "c", "b", and
+	// the rounding remainder goes to the last one, so the shares sum up to the amount
+	for addr, expected := range map[model.Address]int64{"a": 33, "b": 34, "c": 33, issuer: 50, dist.Escrow(): 0} {
+		balance, err := bs.Fetch(ctx, addr, acc, curr)
+		env.assert.NoError(err)
+		env.assert.Equal(amount(expected), balance, addr)
+	}
+
+	env.assert.Equal(amount(0), dist.Returned)
+
+	_, _, err = bs.ContinueDistribution(tx("tx5"), "coupon1", 3)
+	env.assert.ErrorIs(err, ErrBalanceDistributionCompleted)
+
+	// the amount not paid due to the holdings short of the supply is returned to the
+	// source, so the escrow is emptied and the payouts and the return sum up to the
+	// deposit
+	short := &model.Distribution{
+		ID:          "coupon3",
+		Source:      issuer,
+		Account:     acc,
+		Currency:    curr,
+		Reference:   ref,
+		Amount:      amount(50),
+		Supply:      amount(5),
+		SnapshotSeq: dist.SnapshotSeq,
+		Counted:     amount(0),
+		Paid:        amount(0),
+		Status:      model.DistributionStatusActive,
+	}
+	env.assert.NoError(bs.Distributions.Save(tx("tx7"), short))
+	env.assert.NoError(balances.Save(tx("tx7"), short.Escrow(), acc, curr, big.NewInt(50)))
+	env.assert.NoError(balances.Save(tx("tx7"), issuer, acc, curr, big.NewInt(0)))
+
+	dist, bu, err = bs.ContinueDistribution(tx("tx8"), "coupon3", 5)
+	env.assert.NoError(err)
+	env.assert.Equal(model.DistributionStatusCompleted, dist.Status)
+	env.assert.Equal(amount(30), dist.Paid)
+	env.assert.Equal(amount(20), dist.Returned)
+	env.assert.Len(bu, 6)
+	env.assert.Equal(issuer, bu[5].Address)
+	env.assert.Equal(model.DirectionCredit, bu[5].Direction)
+
+	for addr, expected := range map[model.Address]int64{"a": 43, "b": 44, "c": 43, issuer: 20, short.Escrow(): 0} {
+		balance, err := bs.Fetch(ctx, addr, acc, curr)
+		env.assert.NoError(err)
+		env.assert.Equal(amount(expected), balance, addr)
+	}
+
+	_, _, err = bs.ContinueDistribution(tx("tx5"), "unknown", 3)
+	env.assert.ErrorIs(err, ErrBalanceDistributionNotFound)
+
+	_, _, err = bs.Distribute(tx("tx6"), "coupon2", issuer, acc, curr, "NONE", amount(100), 2)
+	env.assert.ErrorIs(err, ErrBalanceNoHolders)
+}
+
+func TestBalance_Portfolio(t *testing.T) {
+	env := newEnvironment(t)
+
+	balances := &storage.Balance{DB: new(inmem.KeyValueDB)}
+	bs := &Balance{Balance: balances}
+
+	const addr = model.Address("a")
+
+	for _, b := range []struct {
+		acc  model.Account
+		curr model.Currency
+		val  int64
+	}{
+		{model.AccountToken, "USD", 10},
+		{model.AccountTokenLocked, "USD", 5},
+		{model.AccountAllowed, "BOND", 3},
+		{model.AccountAllowedLocked, "USD", 0},
+		{model.AccountToken, "GBP", 7},
+	} {
+		env.assert.NoError(balances.Save(ctx, addr, b.acc, b.curr, big.NewInt(b.val)))
+	}
+
+	env.assert.NoError(balances.Save(ctx, "ab", model.AccountToken, "USD", big.NewInt(100)))
+
+	p, err := bs.Portfolio(ctx, addr)
+	env.assert.NoError(err)
+	env.assert.Equal(&model.Portfolio{
+		Address: addr,
+		Positions: []model.Position{
+			{Account: model.AccountAllowed, Currency: "BOND", Balance: amount(3)},
+			{Account: model.AccountToken, Currency: "GBP", Balance: amount(7)},
+			{Account: model.AccountToken, Currency: "USD", Balance: amount(10)},
+			{Account: model.AccountTokenLocked, Currency: "USD", Balance: amount(5)},
+		},
+		Totals: map[model.Currency]*model.Amount{
+			"BOND": amount(3),
+			"GBP":  amount(7),
+			"USD":  amount(15),
+		},
+	}, p)
+}
+
+func TestBalance_Accrual(t *testing.T) {
+	env := newEnvironment(t)
+
+	db := new(inmem.KeyValueDB)
+	accruals := &storage.Accrual{Object: storage.Object{DB: db}}
+	notifications := &storage.Notification{Object: storage.Object{DB: db}}
+
+	bs := &Balance{
+		Balance:      &storage.Balance{DB: db},
+		Notification: notifications,
+		Accruals:     accruals,
+	}
+
+	const (
+		addr = model.Address("a")
+		acc  = model.AccountToken
+		curr = model.Currency("DEP")
+	)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tx := func(id string, ts time.Time) context.Context {
+		return model.ContextWithTransaction(ctx, model.Transaction{ID: id, Timestamp: ts})
+	}
+
+	env.assert.NoError(accruals.AppendRate(ctx, curr, &model.AccrualRate{
+		Rate:   big.NewRat(1, 100),
+		Period: 24 * time.Hour,
+	}))
+
+	_, err := bs.Deposit(tx("tx1", start), addr, acc, curr, amount(10000))
+	env.assert.NoError(err)
+
+	// 10000 -> 10100 -> 10201 -> 10201 + 51 for the half of the third day
+	later := start.Add(60 * time.Hour)
+
+	balance, err := bs.Fetch(tx("tx2", later), addr, acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(amount(10252), balance)
+
+	bu, err := bs.Withdraw(tx("tx3", later), addr, acc, curr, amount(52))
+	env.assert.NoError(err)
+	env.assert.Equal(amount(10252), bu.OldValue)
+	env.assert.Equal(amount(10200), bu.NewValue)
+
+	raw, err := notifications.Load(ctx, model.NotificationTypeAccrual, model.AccrualID("tx3", addr, acc, curr))
+	env.assert.NoError(err)
+	env.assert.NotNil(raw)
+
+	accrual, err := model.DecodeNotification[model.Accrual](raw)
+	env.assert.NoError(err)
+	env.assert.Equal(amount(252), accrual.Body.Update.ValueDelta)
+	env.assert.Equal(model.OperationAccrual, accrual.Body.Update.Operation)
+	env.assert.Equal(start, accrual.Body.From)
+
+	// the viewed adjustment is not saved, the withdrawal restarted the accrual
+	balance, err = bs.Fetch(tx("tx4", later), addr, acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(amount(10200), balance)
+
+	// demurrage reduces the balance
+	env.assert.NoError(accruals.AppendRate(ctx, curr, &model.AccrualRate{
+		Rate:   big.NewRat(-1, 100),
+		Period: 24 * time.Hour,
+	}))
+
+	balance, err = bs.Fetch(tx("tx5", later.Add(24*time.Hour)), addr, acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(amount(10098), balance)
+
+	// the rate set by the regulator is not applied to the time before the change
+	const regulator = model.Address("regulator")
+
+	env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil).AnyTimes()
+
+	as := &Accrual{Accrual: accruals, Access: env.repoAccess}
+	env.assert.NoError(as.SetRate(tx("tx6", later.Add(24*time.Hour)), regulator, curr, &model.AccrualRate{
+		Rate:   big.NewRat(1, 100),
+		Period: 24 * time.Hour,
+	}))
+
+	// 10200 * 0.99 * 1.01 = 10198.98, rounded once
+	bu, err = bs.Withdraw(tx("tx7", later.Add(48*time.Hour)), addr, acc, curr, amount(198))
+	env.assert.NoError(err)
+	env.assert.Equal(amount(10198), bu.OldValue)
+
+	raw, err = notifications.Load(ctx, model.NotificationTypeAccrual, model.AccrualID("tx7", addr, acc, curr))
+	env.assert.NoError(err)
+
+	accrual, err = model.DecodeNotification[model.Accrual](raw)
+	env.assert.NoError(err)
+	env.assert.Len(accrual.Body.Rates, 2)
+	env.assert.Equal(big.NewRat(1, 100), accrual.Body.Rate)
+
+	// the credits of the accumulator accrue since they are made
+	bs.Balance.(*storage.Balance).Accumulator = true
+
+	_, err = bs.Deposit(tx("tx8", later.Add(72*time.Hour)), addr, acc, curr, amount(10000))
+	env.assert.NoError(err)
+
+	// (10000 * 1.01 + 10000) * 1.01
+	balance, err = bs.Fetch(tx("tx9", later.Add(96*time.Hour)), addr, acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(amount(20301), balance)
+
+	// the balances of the accounts not permitting the accrual are left as they are
+	const accountFlat model.Account = 1004
+
+	if err := model.RegisterAccount(model.AccountType{
+		Account:    accountFlat,
+		Name:       "AccountFlat",
+		Operations: model.OperationDeposit | model.OperationWithdraw,
+	}); err != nil && !errors.Is(err, model.ErrAccountRegistered) {
+		t.Fatal(err)
+	}
+
+	_, err = bs.Deposit(tx("tx10", later.Add(96*time.Hour)), addr, accountFlat, curr, amount(10000))
+	env.assert.NoError(err)
+
+	balance, err = bs.Fetch(tx("tx11", later.Add(192*time.Hour)), addr, accountFlat, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(amount(10000), balance)
+
+	// exponentiation of a long period does not lose the units to the rounding
+	exact := &model.AccrualRate{Rate: big.NewRat(1, 100), Period: 24 * time.Hour}
+	env.assert.Equal(
+		big.NewInt(37783),
+		exact.Apply(big.NewInt(1000), start, start.Add(365*24*time.Hour)),
+	)
+}
@@ -4,7 +4,7 @@ package controller
 
 import (
 	"context"
-	"math/big"
+	"time"
 
 	"github.com/anoideaopen/token/model"
 )
@@ -13,19 +13,76 @@ import (
 type Balance interface {
 	// Deposit method is intended to increase the balance of the 'to' account.
 	// The amount of increase is specified by 'val' parameter.
-	Deposit(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amt *big.Int) (bu model.BalanceUpdate, err error)
+	Deposit(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amount *model.Amount) (bu model.BalanceUpdate, err error)
 	// Withdraw method is intended to decrease the balance of the 'from' account.
 	// The amount of decrease is specified by 'val' parameter.
-	Withdraw(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amt *big.Int) (bu model.BalanceUpdate, err error)
+	Withdraw(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amount *model.Amount) (bu model.BalanceUpdate, err error)
 	// Transfer method is intended to move funds from one account to another.
 	// The amount of funds to be moved is specified by 'val' parameter.
-	Transfer(ctx context.Context, addrFrom, addrTo model.Address, acc model.Account, curr model.Currency, val *big.Int) ([2]model.BalanceUpdate, error)
+	Transfer(ctx context.Context, addrFrom, addrTo model.Address, acc model.Account, curr model.Currency, amount *model.Amount) (bu [2]model.BalanceUpdate, err error)
 	// InternalTransfer method is intended for transferring funds between two accounts
 	// under the same address. The amount of funds to be moved is specified by 'val' parameter.
-	InternalTransfer(ctx context.Context, addr model.Address, accFrom, accTo model.Account, curr model.Currency, val *big.Int) ([2]model.BalanceUpdate, error)
+	InternalTransfer(ctx context.Context, addr model.Address, accFrom, accTo model.Account, curr model.Currency, amount *model.Amount) (bu [2]model.BalanceUpdate, err error)
 	// Fetch retrieves the balance of a specific account for a given currency.
 	// It takes the context (ctx), the address (addr), the account (acc),
 	// and the currency (curr) as input parameters.
-	// It returns the balance as a *big.Int value and an error if something goes wrong.
-	Fetch(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*big.Int, error)
+	// It returns the balance as a *model.Amount value with the precision of the currency
+	// and an error if something goes wrong.
+	Fetch(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*model.Amount, error)
+	// Portfolio retrieves the non-zero balances of the address on all the declared
+	// accounts together with their totals per currency.
+	Portfolio(ctx context.Context, addr model.Address) (*model.Portfolio, error)
+	// Compact merges the credits accumulated as delta records into the balance of a
+	// specific account for a given currency.
+	Compact(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) error
+	// AvailableBalance retrieves the balance of a specific account for a given currency
+	// reduced by the funds reserved by its active authorization holds.
+	AvailableBalance(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*model.Amount, error)
+	// Snapshot method records a point in time, as of which the balances can be retrieved
+	// by BalanceAt. The balances are not copied: their values are captured before the
+	// first change after the snapshot.
+	Snapshot(ctx context.Context, id string) (*model.Snapshot, error)
+	// BalanceAt retrieves the balance of a specific account for a given currency as of
+	// the snapshot with given identifier.
+	BalanceAt(ctx context.Context, snapshotID string, addr model.Address, acc model.Account, curr model.Currency) (*model.Amount, error)
+	// Distribute method starts a pro-rata distribution of the amount from the source
+	// address to all the holders of the reference currency on the same account, e.g. a
+	// dividend or a coupon, moves the amount to the escrow of the distribution and credits
+	// the first batch of at most limit holders from it. The
+	// holdings are taken as of a snapshot made by the distribution. The remaining holders
+	// are credited by ContinueDistribution in subsequent transactions.
+	Distribute(ctx context.Context, id string, source model.Address, acc model.Account, curr, ref model.Currency, amount *model.Amount, limit int) (*model.Distribution, model.BalancesUpdate, error)
+	// ContinueDistribution method credits the next batch of at most limit holders of the
+	// distribution. The distribution is completed by the batch, which reaches the last
+	// holder, and the amount left in the escrow is returned to the source then.
+	ContinueDistribution(ctx context.Context, id string, limit int) (*model.Distribution, model.BalancesUpdate, error)
+	// FetchDistribution retrieves the pro-rata distribution.
+	FetchDistribution(ctx context.Context, id string) (*model.Distribution, error)
+	// Hold method reserves funds of the account for the merchant until the moment of
+	// expiry. Reserved funds can not be withdrawn or transferred, but they can be
+	// captured by the merchant in one or several parts.
+	Hold(ctx context.Context, id string, addr model.Address, acc model.Account, curr model.Currency, merchant model.Address, amount *model.Amount, expiresAt time.Time) (*model.Hold, error)
+	// Capture method transfers the amount reserved by the authorization hold to the
+	// merchant. The amount may be less than the reserved one, in which case the rest
+	// stays reserved and can be captured later. It can be executed only by the merchant
+	// of the hold.
+	Capture(ctx context.Context, merchant model.Address, id string, amount *model.Amount) (bu [2]model.BalanceUpdate, err error)
+	// Void method releases the funds reserved by the authorization hold. It can be
+	// executed only by the merchant or the owner of the funds.
+	Void(ctx context.Context, caller model.Address, id string) (*model.Hold, error)
+	// FetchHold retrieves the authorization hold. The status of an expired hold is
+	// reported as model.HoldStatusExpired even though it is not stored yet.
+	FetchHold(ctx context.Context, id string) (*model.Hold, error)
+	// ForcedTransfer method is intended for moving funds out of an address without
+	// the owner's consent, e.g. by a court order. It can be executed only by an address
+	// holding the model.RoleRegulator role, bypasses freeze checks and stores a
+	// model.NotificationTypeForcedTransfer record referencing the order.
+	ForcedTransfer(ctx context.Context, order model.ForcedTransferOrder, addrFrom, addrTo model.Address, acc model.Account, curr model.Currency, amount *model.Amount) (bu [2]model.BalanceUpdate, err error)
+	// Reverse method undoes the balance updates recorded by the notification of given type
+	// and identifier, applying the exact opposite changes to the same balances. An operation
+	// can be reversed only once. The reversal must be permitted on the accounts of the
+	// balances, and a debit may draw on the credit line of the address. It can be executed
+	// only by an address holding the model.RoleRegulator role and stores a
+	// model.NotificationTypeReversal record linked to the original notification.
+	Reverse(ctx context.Context, regulator model.Address, typ, id string) (bu model.BalancesUpdate, err error)
 }
@@ -6,8 +6,8 @@ package mock
 
 import (
 	context "context"
-	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/anoideaopen/token/model"
 	gomock "go.uber.org/mock/gomock"
@@ -36,26 +36,117 @@ func (m *MockBalance) EXPECT() *MockBalanceMockRecorder {
 	return m.recorder
 }
 
+// AvailableBalance mocks base method.
+func (m *MockBalance) AvailableBalance(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*model.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AvailableBalance", ctx, addr, acc, curr)
+	ret0, _ := ret[0].(*model.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AvailableBalance indicates an expected call of AvailableBalance.
+func (mr *MockBalanceMockRecorder) AvailableBalance(ctx, addr, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AvailableBalance", reflect.TypeOf((*MockBalance)(nil).AvailableBalance), ctx, addr, acc, curr)
+}
+
+// BalanceAt mocks base method.
+func (m *MockBalance) BalanceAt(ctx context.Context, snapshotID string, addr model.Address, acc model.Account, curr model.Currency) (*model.Amount, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BalanceAt", ctx, snapshotID, addr, acc, curr)
+	ret0, _ := ret[0].(*model.Amount)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BalanceAt indicates an expected call of BalanceAt.
+func (mr *MockBalanceMockRecorder) BalanceAt(ctx, snapshotID, addr, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BalanceAt", reflect.TypeOf((*MockBalance)(nil).BalanceAt), ctx, snapshotID, addr, acc, curr)
+}
+
+// Capture mocks base method.
+func (m *MockBalance) Capture(ctx context.Context, merchant model.Address, id string, amount *model.Amount) ([2]model.BalanceUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Capture", ctx, merchant, id, amount)
+	ret0, _ := ret[0].([2]model.BalanceUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Capture indicates an expected call of Capture.
+func (mr *MockBalanceMockRecorder) Capture(ctx, merchant, id, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Capture", reflect.TypeOf((*MockBalance)(nil).Capture), ctx, merchant, id, amount)
+}
+
+// Compact mocks base method.
+func (m *MockBalance) Compact(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", ctx, addr, acc, curr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockBalanceMockRecorder) Compact(ctx, addr, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockBalance)(nil).Compact), ctx, addr, acc, curr)
+}
+
+// ContinueDistribution mocks base method.
+func (m *MockBalance) ContinueDistribution(ctx context.Context, id string, limit int) (*model.Distribution, model.BalancesUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ContinueDistribution", ctx, id, limit)
+	ret0, _ := ret[0].(*model.Distribution)
+	ret1, _ := ret[1].(model.BalancesUpdate)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ContinueDistribution indicates an expected call of ContinueDistribution.
+func (mr *MockBalanceMockRecorder) ContinueDistribution(ctx, id, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ContinueDistribution", reflect.TypeOf((*MockBalance)(nil).ContinueDistribution), ctx, id, limit)
+}
+
 // Deposit mocks base method.
-func (m *MockBalance) Deposit(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amt *big.Int) (model.BalanceUpdate, error) {
+func (m *MockBalance) Deposit(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amount *model.Amount) (model.BalanceUpdate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Deposit", ctx, addr, acc, curr, amt)
+	ret := m.ctrl.Call(m, "Deposit", ctx, addr, acc, curr, amount)
 	ret0, _ := ret[0].(model.BalanceUpdate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Deposit indicates an expected call of Deposit.
-func (mr *MockBalanceMockRecorder) Deposit(ctx, addr, acc, curr, amt interface{}) *gomock.Call {
+func (mr *MockBalanceMockRecorder) Deposit(ctx, addr, acc, curr, amount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockBalance)(nil).Deposit), ctx, addr, acc, curr, amt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deposit", reflect.TypeOf((*MockBalance)(nil).Deposit), ctx, addr, acc, curr, amount)
+}
+
+// Distribute mocks base method.
+func (m *MockBalance) Distribute(ctx context.Context, id string, source model.Address, acc model.Account, curr, ref model.Currency, amount *model.Amount, limit int) (*model.Distribution, model.BalancesUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Distribute", ctx, id, source, acc, curr, ref, amount, limit)
+	ret0, _ := ret[0].(*model.Distribution)
+	ret1, _ := ret[1].(model.BalancesUpdate)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Distribute indicates an expected call of Distribute.
+func (mr *MockBalanceMockRecorder) Distribute(ctx, id, source, acc, curr, ref, amount, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Distribute", reflect.TypeOf((*MockBalance)(nil).Distribute), ctx, id, source, acc, curr, ref, amount, limit)
 }
 
 // Fetch mocks base method.
-func (m *MockBalance) Fetch(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*big.Int, error) {
+func (m *MockBalance) Fetch(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*model.Amount, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Fetch", ctx, addr, acc, curr)
-	ret0, _ := ret[0].(*big.Int)
+	ret0, _ := ret[0].(*model.Amount)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -66,47 +157,167 @@ func (mr *MockBalanceMockRecorder) Fetch(ctx, addr, acc, curr interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockBalance)(nil).Fetch), ctx, addr, acc, curr)
 }
 
+// FetchDistribution mocks base method.
+func (m *MockBalance) FetchDistribution(ctx context.Context, id string) (*model.Distribution, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchDistribution", ctx, id)
+	ret0, _ := ret[0].(*model.Distribution)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchDistribution indicates an expected call of FetchDistribution.
+func (mr *MockBalanceMockRecorder) FetchDistribution(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchDistribution", reflect.TypeOf((*MockBalance)(nil).FetchDistribution), ctx, id)
+}
+
+// FetchHold mocks base method.
+func (m *MockBalance) FetchHold(ctx context.Context, id string) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchHold", ctx, id)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchHold indicates an expected call of FetchHold.
+func (mr *MockBalanceMockRecorder) FetchHold(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchHold", reflect.TypeOf((*MockBalance)(nil).FetchHold), ctx, id)
+}
+
+// ForcedTransfer mocks base method.
+func (m *MockBalance) ForcedTransfer(ctx context.Context, order model.ForcedTransferOrder, addrFrom, addrTo model.Address, acc model.Account, curr model.Currency, amount *model.Amount) ([2]model.BalanceUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForcedTransfer", ctx, order, addrFrom, addrTo, acc, curr, amount)
+	ret0, _ := ret[0].([2]model.BalanceUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ForcedTransfer indicates an expected call of ForcedTransfer.
+func (mr *MockBalanceMockRecorder) ForcedTransfer(ctx, order, addrFrom, addrTo, acc, curr, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForcedTransfer", reflect.TypeOf((*MockBalance)(nil).ForcedTransfer), ctx, order, addrFrom, addrTo, acc, curr, amount)
+}
+
+// Hold mocks base method.
+func (m *MockBalance) Hold(ctx context.Context, id string, addr model.Address, acc model.Account, curr model.Currency, merchant model.Address, amount *model.Amount, expiresAt time.Time) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hold", ctx, id, addr, acc, curr, merchant, amount, expiresAt)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Hold indicates an expected call of Hold.
+func (mr *MockBalanceMockRecorder) Hold(ctx, id, addr, acc, curr, merchant, amount, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hold", reflect.TypeOf((*MockBalance)(nil).Hold), ctx, id, addr, acc, curr, merchant, amount, expiresAt)
+}
+
 // InternalTransfer mocks base method.
-func (m *MockBalance) InternalTransfer(ctx context.Context, addr model.Address, accFrom, accTo model.Account, curr model.Currency, val *big.Int) ([2]model.BalanceUpdate, error) {
+func (m *MockBalance) InternalTransfer(ctx context.Context, addr model.Address, accFrom, accTo model.Account, curr model.Currency, amount *model.Amount) ([2]model.BalanceUpdate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "InternalTransfer", ctx, addr, accFrom, accTo, curr, val)
+	ret := m.ctrl.Call(m, "InternalTransfer", ctx, addr, accFrom, accTo, curr, amount)
 	ret0, _ := ret[0].([2]model.BalanceUpdate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // InternalTransfer indicates an expected call of InternalTransfer.
-func (mr *MockBalanceMockRecorder) InternalTransfer(ctx, addr, accFrom, accTo, curr, val interface{}) *gomock.Call {
+func (mr *MockBalanceMockRecorder) InternalTransfer(ctx, addr, accFrom, accTo, curr, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalTransfer", reflect.TypeOf((*MockBalance)(nil).InternalTransfer), ctx, addr, accFrom, accTo, curr, amount)
+}
+
+// Portfolio mocks base method.
+func (m *MockBalance) Portfolio(ctx context.Context, addr model.Address) (*model.Portfolio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Portfolio", ctx, addr)
+	ret0, _ := ret[0].(*model.Portfolio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Portfolio indicates an expected call of Portfolio.
+func (mr *MockBalanceMockRecorder) Portfolio(ctx, addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Portfolio", reflect.TypeOf((*MockBalance)(nil).Portfolio), ctx, addr)
+}
+
+// Reverse mocks base method.
+func (m *MockBalance) Reverse(ctx context.Context, regulator model.Address, typ, id string) (model.BalancesUpdate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reverse", ctx, regulator, typ, id)
+	ret0, _ := ret[0].(model.BalancesUpdate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reverse indicates an expected call of Reverse.
+func (mr *MockBalanceMockRecorder) Reverse(ctx, regulator, typ, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InternalTransfer", reflect.TypeOf((*MockBalance)(nil).InternalTransfer), ctx, addr, accFrom, accTo, curr, val)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reverse", reflect.TypeOf((*MockBalance)(nil).Reverse), ctx, regulator, typ, id)
+}
+
+// Snapshot mocks base method.
+func (m *MockBalance) Snapshot(ctx context.Context, id string) (*model.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Snapshot", ctx, id)
+	ret0, _ := ret[0].(*model.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Snapshot indicates an expected call of Snapshot.
+func (mr *MockBalanceMockRecorder) Snapshot(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Snapshot", reflect.TypeOf((*MockBalance)(nil).Snapshot), ctx, id)
 }
 
 // Transfer mocks base method.
-func (m *MockBalance) Transfer(ctx context.Context, addrFrom, addrTo model.Address, acc model.Account, curr model.Currency, val *big.Int) ([2]model.BalanceUpdate, error) {
+func (m *MockBalance) Transfer(ctx context.Context, addrFrom, addrTo model.Address, acc model.Account, curr model.Currency, amount *model.Amount) ([2]model.BalanceUpdate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Transfer", ctx, addrFrom, addrTo, acc, curr, val)
+	ret := m.ctrl.Call(m, "Transfer", ctx, addrFrom, addrTo, acc, curr, amount)
 	ret0, _ := ret[0].([2]model.BalanceUpdate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Transfer indicates an expected call of Transfer.
-func (mr *MockBalanceMockRecorder) Transfer(ctx, addrFrom, addrTo, acc, curr, val interface{}) *gomock.Call {
+func (mr *MockBalanceMockRecorder) Transfer(ctx, addrFrom, addrTo, acc, curr, amount interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockBalance)(nil).Transfer), ctx, addrFrom, addrTo, acc, curr, amount)
+}
+
+// Void mocks base method.
+func (m *MockBalance) Void(ctx context.Context, caller model.Address, id string) (*model.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Void", ctx, caller, id)
+	ret0, _ := ret[0].(*model.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Void indicates an expected call of Void.
+func (mr *MockBalanceMockRecorder) Void(ctx, caller, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Transfer", reflect.TypeOf((*MockBalance)(nil).Transfer), ctx, addrFrom, addrTo, acc, curr, val)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Void", reflect.TypeOf((*MockBalance)(nil).Void), ctx, caller, id)
 }
 
 // Withdraw mocks base method.
-func (m *MockBalance) Withdraw(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amt *big.Int) (model.BalanceUpdate, error) {
+func (m *MockBalance) Withdraw(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, amount *model.Amount) (model.BalanceUpdate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Withdraw", ctx, addr, acc, curr, amt)
+	ret := m.ctrl.Call(m, "Withdraw", ctx, addr, acc, curr, amount)
 	ret0, _ := ret[0].(model.BalanceUpdate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Withdraw indicates an expected call of Withdraw.
-func (mr *MockBalanceMockRecorder) Withdraw(ctx, addr, acc, curr, amt interface{}) *gomock.Call {
+func (mr *MockBalanceMockRecorder) Withdraw(ctx, addr, acc, curr, amount interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalance)(nil).Withdraw), ctx, addr, acc, curr, amt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Withdraw", reflect.TypeOf((*MockBalance)(nil).Withdraw), ctx, addr, acc, curr, amount)
 }
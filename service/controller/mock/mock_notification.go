@@ -35,6 +35,36 @@ func (m *MockNotification) EXPECT() *MockNotificationMockRecorder {
 	return m.recorder
 }
 
+// Fetch mocks base method.
+func (m *MockNotification) Fetch(ctx context.Context, typ, id string) (*model.RawNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fetch", ctx, typ, id)
+	ret0, _ := ret[0].(*model.RawNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fetch indicates an expected call of Fetch.
+func (mr *MockNotificationMockRecorder) Fetch(ctx, typ, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fetch", reflect.TypeOf((*MockNotification)(nil).Fetch), ctx, typ, id)
+}
+
+// History mocks base method.
+func (m *MockNotification) History(ctx context.Context, q model.HistoryQuery) (*model.HistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, q)
+	ret0, _ := ret[0].(*model.HistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockNotificationMockRecorder) History(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockNotification)(nil).History), ctx, q)
+}
+
 // NotifyBalancesUpdate mocks base method.
 func (m *MockNotification) NotifyBalancesUpdate(ctx context.Context, bu model.Notification[model.BalancesUpdate]) error {
 	m.ctrl.T.Helper()
@@ -48,3 +78,33 @@ func (mr *MockNotificationMockRecorder) NotifyBalancesUpdate(ctx, bu interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyBalancesUpdate", reflect.TypeOf((*MockNotification)(nil).NotifyBalancesUpdate), ctx, bu)
 }
+
+// Query mocks base method.
+func (m *MockNotification) Query(ctx context.Context, q model.NotificationQuery) (*model.NotificationPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", ctx, q)
+	ret0, _ := ret[0].(*model.NotificationPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockNotificationMockRecorder) Query(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockNotification)(nil).Query), ctx, q)
+}
+
+// Verify mocks base method.
+func (m *MockNotification) Verify(ctx context.Context) (*model.LedgerBreak, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verify", ctx)
+	ret0, _ := ret[0].(*model.LedgerBreak)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Verify indicates an expected call of Verify.
+func (mr *MockNotificationMockRecorder) Verify(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verify", reflect.TypeOf((*MockNotification)(nil).Verify), ctx)
+}
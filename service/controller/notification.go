@@ -13,4 +13,20 @@ type Notification interface {
 	// NotifyBalancesUpdate добавляет новую запись в бухгалтерскую книгу, о движении средств
 	// пользователя или пользователей. Записи валидируются перед сохранением.
 	NotifyBalancesUpdate(ctx context.Context, bu model.Notification[model.BalancesUpdate]) error
+	// Fetch возвращает запись бухгалтерской книги по типу и идентификатору уведомления.
+	// Тело уведомления не декодируется, для этого используется model.DecodeNotification.
+	Fetch(ctx context.Context, typ, id string) (*model.RawNotification, error)
+	// Query возвращает страницу записей бухгалтерской книги, отобранных по типу, адресу и
+	// интервалу времени. Следующая страница запрашивается с закладкой, полученной вместе
+	// с предыдущей.
+	Query(ctx context.Context, q model.NotificationQuery) (*model.NotificationPage, error)
+	// History возвращает страницу изменений балансов адреса, начиная с последних, с
+	// возможностью отбора по валюте и времени. Следующая страница запрашивается с
+	// закладкой, полученной вместе с предыдущей.
+	History(ctx context.Context, q model.HistoryQuery) (*model.HistoryPage, error)
+	// Verify проверяет цепочку хешей бухгалтерской книги: последовательность номеров
+	// записей, связь каждой записи с предыдущей, хеши записей и тел уведомлений, а также
+	// соответствие головы цепочки последней записи. Возвращается описание первого
+	// нарушения или nil, если цепочка не нарушена.
+	Verify(ctx context.Context) (*model.LedgerBreak, error)
 }
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/service/controller"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// CorporateAction service errors.
+var (
+	// ErrCorporateActionRepository represents a generic error related to the repository operations.
+	ErrCorporateActionRepository = errors.New("corporate action repository error")
+
+	// ErrCorporateActionForbidden is returned when the caller has no rights to perform
+	// corporate actions.
+	ErrCorporateActionForbidden = errors.New("corporate action is forbidden")
+
+	// ErrCorporateActionInvalid is returned when the corporate action fails to validate.
+	ErrCorporateActionInvalid = errors.New("invalid corporate action")
+
+	// ErrCorporateActionExists is returned when a corporate action with the same
+	// identifier already exists.
+	ErrCorporateActionExists = errors.New("corporate action already exists")
+
+	// ErrCorporateActionNotFound is returned when the corporate action does not exist.
+	ErrCorporateActionNotFound = errors.New("corporate action not found")
+
+	// ErrCorporateActionBlocked is returned when the currency is involved in an active
+	// distribution, whose amounts would be mixed in the units before and after the
+	// action.
+	ErrCorporateActionBlocked = errors.New("corporate action is blocked")
+)
+
+// CorporateAction is a struct that provides methods to perform corporate actions,
+// e.g. splits and redenominations, which multiply all the balances of a currency.
+// An action is only stored, the balances are adjusted by storage.Balance with the
+// same storage of the actions, when they are read.
+//
+//go:generate ifacemaker -f corporate_action.go -o controller/corporate_action.go -i CorporateAction -s CorporateAction -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/corporate_action.go -destination controller/mock/mock_corporate_action.go
+type CorporateAction struct {
+	repository.CorporateAction
+
+	// Access is used to check that the caller holds the model.RoleRegulator role.
+	Access repository.Access
+
+	// Notification stores the accounting records of the actions.
+	Notification repository.Notification
+
+	// Ledger is used to append the accounting records of the actions to the hash
+	// chain. If it is nil, the chain is not kept.
+	Ledger repository.Ledger
+
+	// Outbox is used to deliver the accounting records of the actions to the off-chain
+	// consumers. If it is nil, no entries are made.
+	Outbox repository.Outbox
+
+	// Journal is used to post the adjustments of the balances made by the actions to
+	// the double-entry journal, see Journal.Adjust. Its balance repository must share
+	// the storage of the actions. If it is nil, nothing is posted.
+	Journal controller.Journal
+
+	// Balances is used to read the totals of the holder registry before and after the
+	// action, so the rounding remainder of the totals is recorded in the accounting
+	// record of the action. It must share the storage of the actions. If it is nil,
+	// the remainder is not recorded.
+	Balances repository.Balance
+
+	// Distributions is used to block the actions of the currencies involved in the
+	// active distributions. If it is nil, nothing is blocked.
+	Distributions repository.Distribution
+}
+
+// Perform method performs the corporate action and stores a
+// model.NotificationTypeCorporateAction record of it. It can be executed only by an
+// address holding the model.RoleRegulator role. The sequence number and the timestamp
+// of the action are assigned by the method.
+func (cs *CorporateAction) Perform(
+	ctx context.Context,
+	regulator model.Address,
+	action *model.CorporateAction,
+) error {
+	if cs.Access == nil || cs.Notification == nil {
+		return ErrCorporateActionForbidden
+	}
+
+	if err := action.Validate(); err != nil {
+		return cs.wrap(ErrCorporateActionInvalid, err)
+	}
+
+	ok, err := cs.Access.HasRole(ctx, regulator, model.RoleRegulator)
+	if err != nil {
+		return cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s is not a regulator", ErrCorporateActionForbidden, regulator)
+	}
+
+	existing, err := cs.CorporateAction.Load(ctx, action.ID)
+	if err != nil {
+		return cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	if existing != nil {
+		return fmt.Errorf("%w: %s", ErrCorporateActionExists, action.ID)
+	}
+
+	if err := cs.checkDistributions(ctx, action.Currency); err != nil {
+		return err
+	}
+
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		action.Timestamp = tx.Timestamp
+	}
+
+	if err := cs.append(ctx, action); err != nil {
+		return err
+	}
+
+	raw, err := model.EncodeNotification(model.Notification[model.CorporateAction]{
+		ID:   action.ID,
+		Type: model.NotificationTypeCorporateAction,
+		Body: *action,
+	})
+	if err != nil {
+		return cs.wrap(ErrCorporateActionInvalid, err)
+	}
+
+	if err := cs.Notification.SaveRaw(ctx, raw); err != nil {
+		return cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	if err := appendLedger(ctx, cs.Ledger, raw.Type, raw.ID, raw.Body); err != nil {
+		return cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	if err := publishOutbox(ctx, cs.Outbox, raw.Type, raw.ID); err != nil {
+		return cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	return nil
+}
+
+// Fetch retrieves the corporate action.
+func (cs *CorporateAction) Fetch(ctx context.Context, id string) (*model.CorporateAction, error) {
+	action, err := cs.CorporateAction.Load(ctx, id)
+	if err != nil {
+		return nil, cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	if action == nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorporateActionNotFound, id)
+	}
+
+	return action, nil
+}
+
+// Actions retrieves all the corporate actions of the currency in the order they were
+// performed.
+func (cs *CorporateAction) Actions(ctx context.Context, curr model.Currency) ([]*model.CorporateAction, error) {
+	actions, err := cs.CorporateAction.List(ctx, curr, 0)
+	if err != nil {
+		return nil, cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	return actions, nil
+}
+
+// append stores the action, posts the adjustments of the balances it makes and sets
+// the total rounding remainder of the action.
+func (cs *CorporateAction) append(ctx context.Context, action *model.CorporateAction) error {
+	before, err := cs.total(ctx, action.Currency)
+	if err != nil {
+		return err
+	}
+
+	apply := func() error {
+		if err := cs.CorporateAction.Append(ctx, action); err != nil {
+			return cs.wrap(ErrCorporateActionRepository, err)
+		}
+
+		return nil
+	}
+
+	if cs.Journal == nil {
+		err = apply()
+	} else {
+		_, err = cs.Journal.Adjust(ctx, model.NotificationTypeCorporateAction+":"+action.ID, action.Currency, apply)
+	}
+
+	if err != nil || before == nil {
+		return err
+	}
+
+	after, err := cs.total(ctx, action.Currency)
+	if err != nil {
+		return err
+	}
+
+	// remainder = before * ratio - after
+	action.Remainder = new(big.Rat).Mul(new(big.Rat).SetInt(before), action.Ratio)
+	action.Remainder.Sub(action.Remainder, new(big.Rat).SetInt(after))
+
+	return nil
+}
+
+// total sums the totals of the positive balances of the currency kept by the holder
+// registry, see storage.Holder.Supply. Without the balance repository nil is returned.
+func (cs *CorporateAction) total(ctx context.Context, curr model.Currency) (*big.Int, error) {
+	if cs.Balances == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	out := new(big.Int)
+	for _, t := range model.Accounts() {
+		supply, err := cs.Balances.Supply(ctx, t.Account, curr)
+		if err != nil {
+			return nil, cs.wrap(ErrCorporateActionRepository, err)
+		}
+
+		out.Add(out, supply)
+	}
+
+	return out, nil
+}
+
+// checkDistributions returns an error, if the currency is involved in an active
+// distribution.
+func (cs *CorporateAction) checkDistributions(ctx context.Context, curr model.Currency) error {
+	if cs.Distributions == nil {
+		return nil
+	}
+
+	active, err := cs.Distributions.Active(ctx, curr)
+	if err != nil {
+		return cs.wrap(ErrCorporateActionRepository, err)
+	}
+
+	if len(active) != 0 {
+		return fmt.Errorf("%w: distribution %s of %s is active", ErrCorporateActionBlocked, active[0], curr)
+	}
+
+	return nil
+}
+
+func (cs *CorporateAction) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/anoideaopen/token/keyvalue/cache"
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage"
+	"go.uber.org/mock/gomock"
+)
+
+func TestCorporateAction(t *testing.T) {
+	env := newEnvironment(t)
+
+	db := new(inmem.KeyValueDB)
+	actions := &storage.CorporateAction{Object: storage.Object{DB: db}}
+	notifications := &storage.Notification{Object: storage.Object{DB: db}}
+
+	cs := &CorporateAction{
+		CorporateAction: actions,
+		Access:          env.repoAccess,
+		Notification:    notifications,
+	}
+	balances := &storage.Balance{DB: db, Actions: actions}
+	bs := &Balance{Balance: balances}
+
+	const (
+		regulator = model.Address("regulator")
+		acc       = model.AccountToken
+		curr      = model.Currency("SEC")
+	)
+
+	tx := func(id string) context.Context {
+		return model.ContextWithTransaction(ctx, model.Transaction{ID: id})
+	}
+
+	env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil).AnyTimes()
+
+	_, err := bs.Deposit(tx("tx1"), "a", acc, curr, amount(1001))
+	env.assert.NoError(err)
+
+	balances.Accumulator = true
+	_, err = bs.Deposit(tx("tx2"), "b", acc, curr, amount(5))
+	env.assert.NoError(err)
+
+	// 2:1 split
+	env.assert.NoError(cs.Perform(tx("tx3"), regulator, &model.CorporateAction{
+		ID:       "split",
+		Currency: curr,
+		Ratio:    big.NewRat(2, 1),
+		Rounding: model.RoundingDown,
+		Reason:   "2:1 split",
+	}))
+
+	err = cs.Perform(tx("tx4"), regulator, &model.CorporateAction{
+		ID:       "split",
+		Currency: curr,
+		Ratio:    big.NewRat(2, 1),
+		Rounding: model.RoundingDown,
+		Reason:   "2:1 split",
+	})
+	env.assert.ErrorIs(err, ErrCorporateActionExists)
+
+	// the credit after the split is not adjusted by it
+	_, err = bs.Deposit(tx("tx5"), "b", acc, curr, amount(1))
+	env.assert.NoError(err)
+
+	for addr, expected := range map[model.Address]int64{"a": 2002, "b": 11} {
+		balance, err := bs.Fetch(ctx, addr, acc, curr)
+		env.assert.NoError(err)
+		env.assert.Equal(amount(expected), balance, addr)
+	}
+
+	// redenomination rounds the balances to the nearest unit
+	_, err = bs.Withdraw(tx("tx6"), "a", acc, curr, amount(2))
+	env.assert.NoError(err)
+
+	env.assert.NoError(cs.Perform(tx("tx7"), regulator, &model.CorporateAction{
+		ID:       "redenomination",
+		Currency: curr,
+		Ratio:    big.NewRat(1, 1000),
+		Rounding: model.RoundingHalfUp,
+		Reason:   "1000:1 redenomination",
+	}))
+
+	for addr, expected := range map[model.Address]int64{"a": 2, "b": 0} {
+		balance, err := bs.Fetch(ctx, addr, acc, curr)
+		env.assert.NoError(err)
+		env.assert.Equal(amount(expected), balance, addr)
+	}
+
+	list, err := cs.Actions(ctx, curr)
+	env.assert.NoError(err)
+	env.assert.Len(list, 2)
+	env.assert.Equal(uint64(2), list[1].Seq)
+
+	raw, err := notifications.Load(ctx, model.NotificationTypeCorporateAction, "redenomination")
+	env.assert.NoError(err)
+	env.assert.NotNil(raw)
+
+	_, err = cs.Fetch(ctx, "unknown")
+	env.assert.ErrorIs(err, ErrCorporateActionNotFound)
+
+	err = cs.Perform(tx("tx8"), regulator, &model.CorporateAction{ID: "bad", Currency: curr, Ratio: big.NewRat(-1, 2)})
+	env.assert.ErrorIs(err, ErrCorporateActionInvalid)
+
+	// the actions are refused without the roles and the records
+	err = (&CorporateAction{CorporateAction: cs.CorporateAction}).Perform(tx("tx9"), regulator, &model.CorporateAction{
+		ID:       "unchecked",
+		Currency: curr,
+		Ratio:    big.NewRat(2, 1),
+		Rounding: model.RoundingDown,
+		Reason:   "split",
+	})
+	env.assert.ErrorIs(err, ErrCorporateActionForbidden)
+}
+
+func TestCorporateAction_derived(t *testing.T) {
+	env := newEnvironment(t)
+
+	// the writes of a transaction are visible to it only through the cache, like in
+	// the chaincode
+	stub := new(stubDB)
+	db := &cache.KeyValueDB{DB: stub}
+
+	actions := &storage.CorporateAction{Object: storage.Object{DB: db}}
+	notifications := &storage.Notification{Object: storage.Object{DB: db}}
+	snapshots := &storage.Snapshot{Object: storage.Object{DB: db}, Actions: actions}
+	distributions := &storage.Distribution{Object: storage.Object{DB: db}}
+	credit := &storage.CreditLine{DB: db, Actions: actions}
+	balances := &storage.Balance{
+		DB:        db,
+		Snapshots: snapshots,
+		Registry:  &storage.Holder{DB: db, Actions: actions},
+		Actions:   actions,
+	}
+
+	cs := &CorporateAction{
+		CorporateAction: actions,
+		Access:          env.repoAccess,
+		Notification:    notifications,
+		Balances:        balances,
+		Distributions:   distributions,
+	}
+	bs := &Balance{Balance: balances, Snapshots: snapshots, Distributions: distributions}
+
+	const (
+		regulator = model.Address("regulator")
+		acc       = model.AccountToken
+		curr      = model.Currency("SEC")
+	)
+
+	tx := func(id string) context.Context {
+		return model.ContextWithTransaction(ctx, model.Transaction{ID: id})
+	}
+
+	env.repoAccess.EXPECT().HasRole(gomock.Any(), regulator, model.RoleRegulator).Return(true, nil).AnyTimes()
+
+	for addr, val := range map[model.Address]int64{"a": 1001, "b": 5} {
+		_, err := bs.Deposit(tx("tx1"), addr, acc, curr, amount(val))
+		env.assert.NoError(err)
+	}
+
+	env.assert.NoError(credit.Save(tx("tx1"), "a", acc, curr, big.NewInt(30)))
+	stub.commit(ctx)
+
+	snap, err := bs.Snapshot(tx("tx2"), "before")
+	env.assert.NoError(err)
+	stub.commit(ctx)
+
+	_, err = bs.Withdraw(tx("tx3"), "a", acc, curr, amount(1))
+	env.assert.NoError(err)
+	stub.commit(ctx)
+
+	// 1:10 consolidation rounding down
+	txCtx := tx("tx4")
+	env.assert.NoError(cs.Perform(txCtx, regulator, &model.CorporateAction{
+		ID:       "consolidation",
+		Currency: curr,
+		Ratio:    big.NewRat(1, 10),
+		Rounding: model.RoundingDown,
+		Reason:   "1:10 consolidation",
+	}))
+
+	// the action is visible to the transaction before it is committed
+	list, err := cs.Actions(txCtx, curr)
+	env.assert.NoError(err)
+	env.assert.Len(list, 1)
+
+	balance, err := bs.Fetch(txCtx, "a", acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(amount(100), balance)
+
+	stub.commit(ctx)
+
+	// (1000 + 5) / 10 - (100 + 0)
+	raw, err := notifications.Load(ctx, model.NotificationTypeCorporateAction, "consolidation")
+	env.assert.NoError(err)
+
+	action, err := model.DecodeNotification[model.CorporateAction](raw)
+	env.assert.NoError(err)
+	env.assert.Equal(big.NewRat(1, 2), action.Body.Remainder)
+
+	// the values derived from the balances are adjusted as well
+	supply, err := balances.Supply(ctx, acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(big.NewInt(100), supply)
+
+	held, err := balances.LoadAt(ctx, snap.Seq, "a", acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(big.NewInt(100), held)
+
+	limit, err := credit.Load(ctx, "a", acc, curr)
+	env.assert.NoError(err)
+	env.assert.Equal(big.NewInt(3), limit)
+
+	// no action can be performed while a distribution of the currency is active
+	env.assert.NoError(distributions.Save(tx("tx5"), &model.Distribution{
+		ID:          "dividend",
+		Source:      "issuer",
+		Account:     acc,
+		Currency:    "USD",
+		Reference:   curr,
+		Amount:      amount(10),
+		Supply:      amount(100),
+		SnapshotSeq: snap.Seq,
+		Counted:     amount(0),
+		Paid:        amount(0),
+		Status:      model.DistributionStatusActive,
+	}))
+	stub.commit(ctx)
+
+	err = cs.Perform(tx("tx6"), regulator, &model.CorporateAction{
+		ID:       "split",
+		Currency: curr,
+		Ratio:    big.NewRat(2, 1),
+		Rounding: model.RoundingDown,
+		Reason:   "2:1 split",
+	})
+	env.assert.ErrorIs(err, ErrCorporateActionBlocked)
+}
@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// CreditLine service errors.
+var (
+	// ErrCreditLineRepository represents a generic error related to the repository operations.
+	ErrCreditLineRepository = errors.New("credit line repository error")
+
+	// ErrCreditLineForbidden is returned when the caller has no rights to grant credit lines.
+	ErrCreditLineForbidden = errors.New("credit line modification is forbidden")
+
+	// ErrCreditLineInvalidAmount is returned when the credit limit is negative or
+	// exceeds the precision of the currency.
+	ErrCreditLineInvalidAmount = errors.New("invalid credit limit")
+)
+
+// CreditLine is a struct that provides methods to grant addresses the right to
+// overdraw their balances. The credit limits are enforced by the Balance service.
+//
+//go:generate ifacemaker -f credit_line.go -o controller/credit_line.go -i CreditLine -s CreditLine -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/credit_line.go -destination controller/mock/mock_credit_line.go
+type CreditLine struct {
+	repository.CreditLine
+
+	// Access is used to check that the caller holds the model.RoleRegulator role.
+	Access repository.Access
+}
+
+// Grant sets the amount by which Withdraw and Transfer may take the balance of the
+// address on the account in the currency below zero. The overdraft of every account is
+// limited separately. A zero amount revokes the credit line. It can be
+// executed only by an address holding the model.RoleRegulator role.
+func (cs *CreditLine) Grant(
+	ctx context.Context,
+	regulator model.Address,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	limit *model.Amount,
+) error {
+	if limit.Sign() < 0 {
+		return ErrCreditLineInvalidAmount
+	}
+
+	scaled, err := limit.Rescale(curr.Precision())
+	if err != nil {
+		return cs.wrap(ErrCreditLineInvalidAmount, err)
+	}
+
+	ok, err := cs.Access.HasRole(ctx, regulator, model.RoleRegulator)
+	if err != nil {
+		return cs.wrap(ErrCreditLineRepository, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s is not a regulator", ErrCreditLineForbidden, regulator)
+	}
+
+	if err := cs.CreditLine.Save(ctx, addr, acc, curr, scaled.Int()); err != nil {
+		return cs.wrap(ErrCreditLineRepository, err)
+	}
+
+	return nil
+}
+
+// Limit returns the credit limit of the address on the account in the currency.
+func (cs *CreditLine) Limit(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*model.Amount, error) {
+	limit, err := cs.CreditLine.Load(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, cs.wrap(ErrCreditLineRepository, err)
+	}
+
+	return curr.Amount(limit), nil
+}
+
+func (cs *CreditLine) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// Holder service errors.
+var (
+	// ErrHolderRepository represents a generic error related to the repository operations.
+	ErrHolderRepository = errors.New("holder repository error")
+
+	// ErrHolderInvalidQuery is returned when a holder query fails to validate.
+	ErrHolderInvalidQuery = errors.New("invalid holder query")
+
+	// ErrHolderNoBalances is returned when the registry is backfilled, but the balance
+	// repository is not configured.
+	ErrHolderNoBalances = errors.New("holder balances are not configured")
+)
+
+// Holder answers the questions about the holders of a currency using the registry of
+// the holders, which storage.Balance maintains when its Registry is set.
+//
+//go:generate ifacemaker -f holder.go -o controller/holder.go -i Holder -s Holder -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/holder.go -destination controller/mock/mock_holder.go
+type Holder struct {
+	repository.Holder
+
+	// Balances is used to backfill the registry with the balances saved before it was
+	// set. If it is nil, Backfill is not supported.
+	Balances repository.Balance
+}
+
+// Holders retrieves a page of the addresses with a non-zero balance of the currency on
+// the account in ascending order. The next page is requested with the bookmark
+// returned with the previous one.
+func (h *Holder) Holders(ctx context.Context, q model.HolderQuery) (*model.HolderPage, error) {
+	if err := q.Validate(); err != nil {
+		return nil, h.wrap(ErrHolderInvalidQuery, err)
+	}
+
+	page, err := h.Holder.List(ctx, q)
+	if err != nil {
+		return nil, h.wrap(ErrHolderRepository, err)
+	}
+
+	return page, nil
+}
+
+// TopHolders retrieves at most limit addresses with the largest balances of the
+// currency on the account in descending order of the balances.
+func (h *Holder) TopHolders(
+	ctx context.Context,
+	acc model.Account,
+	curr model.Currency,
+	limit int,
+) ([]*model.Holding, error) {
+	top, err := h.Holder.Top(ctx, acc, curr, limit)
+	if err != nil {
+		return nil, h.wrap(ErrHolderRepository, err)
+	}
+
+	return top, nil
+}
+
+// HolderCount retrieves the number of the addresses with a non-zero balance of the
+// currency on the account.
+func (h *Holder) HolderCount(ctx context.Context, acc model.Account, curr model.Currency) (uint64, error) {
+	n, err := h.Holder.Count(ctx, acc, curr)
+	if err != nil {
+		return 0, h.wrap(ErrHolderRepository, err)
+	}
+
+	return n, nil
+}
+
+// Backfill adds the balances of the addresses of the currency on the account saved
+// before the registry was set to it. The addresses are expected to be submitted in
+// batches, which fit into a transaction. Adding an address again changes nothing.
+func (h *Holder) Backfill(
+	ctx context.Context,
+	acc model.Account,
+	curr model.Currency,
+	addrs []model.Address,
+) error {
+	if h.Balances == nil {
+		return ErrHolderNoBalances
+	}
+
+	if err := h.Balances.Register(ctx, addrs, acc, curr); err != nil {
+		return h.wrap(ErrHolderRepository, err)
+	}
+
+	return nil
+}
+
+func (h *Holder) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
@@ -0,0 +1,461 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/service/controller"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// Journal service errors.
+var (
+	// ErrJournalRepository represents a generic error related to the repository operations.
+	ErrJournalRepository = errors.New("journal repository error")
+
+	// ErrJournalInvalidEntry is returned when a journal entry fails to validate,
+	// e.g. its debits do not equal its credits.
+	ErrJournalInvalidEntry = errors.New("invalid journal entry")
+
+	// ErrJournalEntryExists is returned when a journal entry with the same identifier
+	// already exists.
+	ErrJournalEntryExists = errors.New("journal entry already exists")
+
+	// ErrJournalNoBalances is returned when the stored balances are needed, but the
+	// balance repository is not configured.
+	ErrJournalNoBalances = errors.New("journal balances are not configured")
+)
+
+// Journal keeps the double-entry journal of the token. Balance updates are posted
+// to the journal accounts of the chart: credits of the balances are credits of their
+// accounts and debits of the balances are debits, while the difference of an operation
+// which creates or destroys tokens is posted to the counter account of the operation,
+// see model.Chart.Counter. The Balance service posts every operation it performs, and
+// the CorporateAction service posts the adjustments of the balances made by the
+// actions, so the journal is reconciled with the stored balances by Reconcile.
+//
+//go:generate ifacemaker -f journal.go -o controller/journal.go -i Journal -s Journal -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/journal.go -destination controller/mock/mock_journal.go
+type Journal struct {
+	repository.Journal
+
+	// Chart maps the balances to the journal accounts. If it is nil,
+	// model.DefaultChart is used.
+	Chart *model.Chart
+
+	// Balances is used to read the totals of the holder registry by Adjust and the
+	// stored balances of the holders by Reconcile. If it is nil, they are not
+	// supported.
+	Balances repository.Balance
+}
+
+// Record posts the balance updates of an operation as a balanced journal entry with
+// the identifier. An empty identifier is replaced by the next free one of the
+// transaction: its ID, then the ID with the number of the entry, e.g. "tx1:2". The
+// timestamp and the memo of the entry are taken from the context. If the updates
+// change no balances, nothing is recorded and nil is returned.
+func (j *Journal) Record(
+	ctx context.Context,
+	id string,
+	updates model.BalancesUpdate,
+) (*model.JournalEntry, error) {
+	chart := j.chart()
+
+	type counter struct {
+		account string
+		curr    model.Currency
+	}
+
+	var (
+		counters []counter
+		net      = make(map[counter]*big.Int)
+		entry    = &model.JournalEntry{ID: id, Memo: model.MemoFromContext(ctx)}
+	)
+
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		entry.Timestamp = tx.Timestamp
+	}
+
+	for _, u := range updates {
+		delta := u.SignedDelta()
+		if delta.Sign() == 0 {
+			continue
+		}
+
+		c := counter{account: chart.Counter(u.Operation), curr: u.Currency}
+		if _, ok := net[c]; !ok {
+			counters = append(counters, c)
+			net[c] = new(big.Int)
+		}
+
+		// net = credits - debits
+		net[c].Add(net[c], delta)
+
+		entry.Postings = append(entry.Postings, j.posting(chart.Holder(u.Address, u.Account), u.Address, u.Currency, delta))
+	}
+
+	// the difference is created or destroyed tokens
+	for _, c := range counters {
+		if diff := net[c]; diff.Sign() != 0 {
+			entry.Postings = append(entry.Postings, j.posting(c.account, "", c.curr, diff.Neg(diff)))
+		}
+	}
+
+	if len(entry.Postings) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	if entry.ID == "" {
+		var err error
+		if entry.ID, err = j.nextID(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := j.Post(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Adjust posts the changes of the totals of the currency made by apply as a journal
+// entry with the identifier, balanced by the adjustments account of the chart. The
+// totals of the positive balances are read from the holder registry before and after
+// apply, see storage.Holder.Supply, so the number of the holders does not matter. The
+// totals are adjusted by a corporate action bucket by bucket, so the entry may differ
+// from the sum of the adjustments of the balances by their rounding. If nothing
+// changes, nothing is recorded and nil is returned.
+func (j *Journal) Adjust(
+	ctx context.Context,
+	id string,
+	curr model.Currency,
+	apply func() error,
+) (*model.JournalEntry, error) {
+	before, err := j.totals(ctx, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := apply(); err != nil {
+		return nil, err
+	}
+
+	after, err := j.totals(ctx, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		chart = j.chart()
+		net   = new(big.Int)
+		entry = &model.JournalEntry{ID: id, Memo: model.MemoFromContext(ctx)}
+	)
+
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		entry.Timestamp = tx.Timestamp
+	}
+
+	for _, account := range sortedKeys(before, after) {
+		delta := new(big.Int).Sub(value(after, account), value(before, account))
+		if delta.Sign() == 0 {
+			continue
+		}
+
+		net.Add(net, delta)
+		entry.Postings = append(entry.Postings, j.posting(account, "", curr, delta))
+	}
+
+	if net.Sign() != 0 {
+		entry.Postings = append(entry.Postings, j.posting(chart.Adjustment(), "", curr, net.Neg(net)))
+	}
+
+	if len(entry.Postings) == 0 {
+		return nil, nil //nolint:nilnil
+	}
+
+	if err := j.Post(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// Reconcile compares the postings of the journal accounts of the holders in the
+// currency with the sums of the stored balances mapped to them by the chart. The
+// lines are sorted by the accounts, the journal is consistent with the balances if
+// every line matches. The balances of all the holders are read, so it is meant for
+// queries, and after the corporate actions the lines may differ by the rounding of
+// the balances, see Adjust.
+func (j *Journal) Reconcile(ctx context.Context, curr model.Currency) ([]model.ReconcileLine, error) {
+	stored, err := j.stored(ctx, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	chart := j.chart()
+	posted := make(map[string]*big.Int)
+
+	if err := j.Journal.Iter(ctx, func(e *model.JournalEntry) bool {
+		for _, p := range e.Postings {
+			if p.Currency != curr || chart.System(p.Account) {
+				continue
+			}
+
+			if posted[p.Account] == nil {
+				posted[p.Account] = new(big.Int)
+			}
+
+			// credits - debits
+			if p.Direction == model.DirectionCredit {
+				posted[p.Account].Add(posted[p.Account], p.Amount.Int())
+			} else {
+				posted[p.Account].Sub(posted[p.Account], p.Amount.Int())
+			}
+		}
+
+		return false
+	}); err != nil {
+		return nil, j.wrap(ErrJournalRepository, err)
+	}
+
+	var out []model.ReconcileLine
+	for _, account := range sortedKeys(posted, stored) {
+		out = append(out, model.ReconcileLine{
+			Account:  account,
+			Currency: curr,
+			Journal:  curr.Amount(value(posted, account)),
+			Stored:   curr.Amount(value(stored, account)),
+		})
+	}
+
+	return out, nil
+}
+
+// Post stores the journal entry made by the application, e.g. a fee posting. The
+// entry must be balanced in every currency.
+func (j *Journal) Post(ctx context.Context, entry *model.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return j.wrap(ErrJournalInvalidEntry, err)
+	}
+
+	existing, err := j.Journal.Load(ctx, entry.ID)
+	if err != nil {
+		return j.wrap(ErrJournalRepository, err)
+	}
+
+	if existing != nil {
+		return fmt.Errorf("%w: %s", ErrJournalEntryExists, entry.ID)
+	}
+
+	if err := j.Journal.Save(ctx, entry); err != nil {
+		return j.wrap(ErrJournalRepository, err)
+	}
+
+	return nil
+}
+
+// TrialBalance sums the postings of all the journal entries by the journal accounts.
+// The journal is consistent if the result is model.TrialBalance.Balanced.
+func (j *Journal) TrialBalance(ctx context.Context) (*model.TrialBalance, error) {
+	tb := new(model.TrialBalance)
+
+	if err := j.Journal.Iter(ctx, func(e *model.JournalEntry) bool {
+		for _, p := range e.Postings {
+			tb.Add(p)
+		}
+
+		return false
+	}); err != nil {
+		return nil, j.wrap(ErrJournalRepository, err)
+	}
+
+	return tb, nil
+}
+
+// posting creates a credit of the account for a positive delta and a debit for
+// a negative one.
+func (j *Journal) posting(
+	account string,
+	addr model.Address,
+	curr model.Currency,
+	delta *big.Int,
+) model.Posting {
+	dir := model.DirectionCredit
+	if delta.Sign() < 0 {
+		dir = model.DirectionDebit
+	}
+
+	return model.Posting{
+		Account:   account,
+		Address:   addr,
+		Currency:  curr,
+		Direction: dir,
+		Amount:    curr.Amount(new(big.Int).Abs(delta)),
+	}
+}
+
+// totals sums the totals of the positive balances of the currency kept by the holder
+// registry by the journal accounts of the holders. The balance of the fee collector is
+// read on its own and mapped to its account.
+func (j *Journal) totals(ctx context.Context, curr model.Currency) (map[string]*big.Int, error) {
+	if j.Balances == nil {
+		return nil, ErrJournalNoBalances
+	}
+
+	var (
+		chart = j.chart()
+		out   = make(map[string]*big.Int)
+	)
+
+	add := func(account string, val *big.Int) {
+		if out[account] == nil {
+			out[account] = new(big.Int)
+		}
+
+		out[account].Add(out[account], val)
+	}
+
+	for _, t := range model.Accounts() {
+		supply, err := j.Balances.Supply(ctx, t.Account, curr)
+		if err != nil {
+			return nil, j.wrap(ErrJournalRepository, err)
+		}
+
+		if collector := chart.FeeCollector; collector != "" && chart.Holder(collector, t.Account) != chart.Account(t.Account) {
+			fees, err := j.Balances.Load(ctx, collector, t.Account, curr)
+			if err != nil {
+				return nil, j.wrap(ErrJournalRepository, err)
+			}
+
+			if fees.Sign() > 0 {
+				supply.Sub(supply, fees)
+				add(chart.Holder(collector, t.Account), fees)
+			}
+		}
+
+		add(chart.Account(t.Account), supply)
+	}
+
+	return out, nil
+}
+
+// stored sums the stored balances of the currency by the journal accounts of the
+// holders.
+func (j *Journal) stored(ctx context.Context, curr model.Currency) (map[string]*big.Int, error) {
+	if j.Balances == nil {
+		return nil, ErrJournalNoBalances
+	}
+
+	var (
+		chart = j.chart()
+		out   = make(map[string]*big.Int)
+	)
+
+	for _, t := range model.Accounts() {
+		holders, err := j.Balances.Holders(ctx, t.Account, curr, "", 0)
+		if err != nil {
+			return nil, j.wrap(ErrJournalRepository, err)
+		}
+
+		for _, addr := range holders {
+			balance, err := j.Balances.Load(ctx, addr, t.Account, curr)
+			if err != nil {
+				return nil, j.wrap(ErrJournalRepository, err)
+			}
+
+			account := chart.Holder(addr, t.Account)
+			if out[account] == nil {
+				out[account] = new(big.Int)
+			}
+
+			out[account].Add(out[account], balance)
+		}
+	}
+
+	return out, nil
+}
+
+// nextID returns the next identifier of the transaction numbered by the journal
+// repository, skipping the ones taken by the entries posted with explicit identifiers.
+func (j *Journal) nextID(ctx context.Context) (string, error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok || tx.ID == "" {
+		return "", fmt.Errorf("%w: transaction information is missing", ErrJournalInvalidEntry)
+	}
+
+	for {
+		n, err := j.Journal.Next(ctx, tx.ID)
+		if err != nil {
+			return "", j.wrap(ErrJournalRepository, err)
+		}
+
+		id := tx.ID
+		if n > 1 {
+			id = fmt.Sprintf("%s:%d", tx.ID, n)
+		}
+
+		existing, err := j.Journal.Load(ctx, id)
+		if err != nil {
+			return "", j.wrap(ErrJournalRepository, err)
+		}
+
+		if existing == nil {
+			return id, nil
+		}
+	}
+}
+
+func (j *Journal) chart() model.Chart {
+	if j.Chart == nil {
+		return model.DefaultChart
+	}
+
+	return *j.Chart
+}
+
+func (j *Journal) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
+
+// postJournal records the balance updates of an operation to the journal with the
+// next identifier of the transaction. If the journal is nil, nothing is done.
+func postJournal(ctx context.Context, journal controller.Journal, updates ...model.BalanceUpdate) error {
+	if journal == nil {
+		return nil
+	}
+
+	_, err := journal.Record(ctx, "", updates)
+
+	return err
+}
+
+// sortedKeys returns the keys of the maps in ascending order.
+func sortedKeys(maps ...map[string]*big.Int) []string {
+	var keys []string
+	for _, m := range maps {
+		for k := range m {
+			if !slices.Contains(keys, k) {
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// value returns the value of the key or zero.
+func value(m map[string]*big.Int, key string) *big.Int {
+	if v, ok := m[key]; ok {
+		return v
+	}
+
+	return new(big.Int)
+}
@@ -0,0 +1,223 @@
+package service
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage"
+	"go.uber.org/mock/gomock"
+)
+
+func TestJournal(t *testing.T) {
+	env := newEnvironment(t)
+	j := &Journal{Journal: &storage.Journal{Object: storage.Object{DB: new(inmem.KeyValueDB)}}}
+
+	now := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1", Timestamp: now})
+
+	update := func(addr model.Address, before, after int64) model.BalanceUpdate {
+		delta := after - before
+		if delta < 0 {
+			delta = -delta
+		}
+
+		return model.BalanceUpdate{
+			Address:    addr,
+			Account:    model.AccountToken,
+			Currency:   "USD",
+			OldValue:   amount(before),
+			NewValue:   amount(after),
+			ValueDelta: amount(delta),
+		}
+	}
+
+	// deposit is balanced by the issuance account
+	entry, err := j.Record(txCtx, "tx1", model.BalancesUpdate{update(user1.address, 0, 100)})
+	env.assert.NoError(err)
+	env.assert.Equal([]model.Posting{
+		{Account: "AccountToken", Address: user1.address, Currency: "USD", Direction: model.DirectionCredit, Amount: amount(100)},
+		{Account: "Issuance", Currency: "USD", Direction: model.DirectionDebit, Amount: amount(100)},
+	}, entry.Postings)
+	env.assert.Equal(now, entry.Timestamp)
+
+	// transfer is balanced by itself
+	entry, err = j.Record(txCtx, "tx2", model.BalancesUpdate{
+		update(user1.address, 100, 60),
+		update(user2.address, 0, 40),
+	})
+	env.assert.NoError(err)
+	env.assert.Len(entry.Postings, 2)
+
+	_, err = j.Record(txCtx, "tx2", model.BalancesUpdate{update(user1.address, 60, 50)})
+	env.assert.ErrorIs(err, ErrJournalEntryExists)
+
+	// fee collected by the application
+	env.assert.NoError(j.Post(txCtx, &model.JournalEntry{
+		ID: "fee1",
+		Postings: []model.Posting{
+			{Account: "AccountToken", Address: user2.address, Currency: "USD", Direction: model.DirectionDebit, Amount: amount(1)},
+			{Account: model.DefaultChart.Fees, Currency: "USD", Direction: model.DirectionCredit, Amount: amount(1)},
+		},
+	}))
+
+	env.assert.ErrorIs(j.Post(txCtx, &model.JournalEntry{
+		ID: "fee2",
+		Postings: []model.Posting{
+			{Account: "AccountToken", Address: user2.address, Currency: "USD", Direction: model.DirectionDebit, Amount: amount(1)},
+			{Account: model.DefaultChart.Fees, Currency: "USD", Direction: model.DirectionCredit, Amount: amount(2)},
+		},
+	}), ErrJournalInvalidEntry)
+
+	tb, err := j.TrialBalance(ctx)
+	env.assert.NoError(err)
+	env.assert.True(tb.Balanced())
+	env.assert.Equal([]model.TrialBalanceLine{
+		{Account: "AccountToken", Currency: "USD", Debit: amount(41), Credit: amount(140)},
+		{Account: "Fees", Currency: "USD", Debit: amount(0), Credit: amount(1)},
+		{Account: "Issuance", Currency: "USD", Debit: amount(100), Credit: amount(0)},
+	}, tb.Lines)
+	env.assert.Equal([2]*model.Amount{amount(141), amount(141)}, tb.Totals()["USD"])
+}
+
+func TestJournal_Record(t *testing.T) {
+	env := newEnvironment(t)
+	j := &Journal{
+		Journal: &storage.Journal{Object: storage.Object{DB: new(inmem.KeyValueDB)}},
+		Chart: &model.Chart{
+			Issuance:     "Issuance",
+			Interest:     "Interest",
+			Fees:         "Fees",
+			FeeCollector: "collector",
+		},
+	}
+
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1"})
+
+	// accrual is balanced by the interest account and gets the identifier of the
+	// transaction
+	entry, err := j.Record(txCtx, "", model.BalancesUpdate{{
+		Address:    user1.address,
+		Account:    model.AccountToken,
+		Currency:   "USD",
+		ValueDelta: amount(5),
+		Direction:  model.DirectionCredit,
+		Operation:  model.OperationAccrual,
+	}})
+	env.assert.NoError(err)
+	env.assert.Equal("tx1", entry.ID)
+	env.assert.Equal("Interest", entry.Postings[1].Account)
+
+	// fee collected by the application is posted to the fees account, the next entry
+	// of the transaction is numbered
+	entry, err = j.Record(txCtx, "", model.BalancesUpdate{
+		{Address: user1.address, Account: model.AccountToken, Currency: "USD", ValueDelta: amount(1), Direction: model.DirectionDebit},
+		{Address: "collector", Account: model.AccountToken, Currency: "USD", ValueDelta: amount(1), Direction: model.DirectionCredit},
+	})
+	env.assert.NoError(err)
+	env.assert.Equal("tx1:2", entry.ID)
+	env.assert.Equal([]model.Posting{
+		{Account: "AccountToken", Address: user1.address, Currency: "USD", Direction: model.DirectionDebit, Amount: amount(1)},
+		{Account: "Fees", Address: "collector", Currency: "USD", Direction: model.DirectionCredit, Amount: amount(1)},
+	}, entry.Postings)
+
+	// the identifiers taken by the entries posted explicitly are skipped
+	fee := model.BalancesUpdate{
+		{Address: user1.address, Account: model.AccountToken, Currency: "USD", ValueDelta: amount(1), Direction: model.DirectionDebit},
+		{Address: "collector", Account: model.AccountToken, Currency: "USD", ValueDelta: amount(1), Direction: model.DirectionCredit},
+	}
+
+	entry, err = j.Record(txCtx, "tx1:3", fee)
+	env.assert.NoError(err)
+	env.assert.Equal("tx1:3", entry.ID)
+
+	entry, err = j.Record(txCtx, "", fee)
+	env.assert.NoError(err)
+	env.assert.Equal("tx1:4", entry.ID)
+
+	_, err = j.Record(ctx, "", model.BalancesUpdate{
+		{Address: user1.address, Account: model.AccountToken, Currency: "USD", ValueDelta: amount(1), Direction: model.DirectionDebit},
+	})
+	env.assert.ErrorIs(err, ErrJournalInvalidEntry)
+}
+
+func TestJournal_Reconcile(t *testing.T) {
+	env := newEnvironment(t)
+
+	db := new(inmem.KeyValueDB)
+	actions := &storage.CorporateAction{Object: storage.Object{DB: db}}
+	accruals := &storage.Accrual{Object: storage.Object{DB: db}}
+	balances := &storage.Balance{
+		DB:       db,
+		Registry: &storage.Holder{DB: db, Actions: actions},
+		Actions:  actions,
+	}
+
+	chart := model.DefaultChart
+	chart.FeeCollector = "collector"
+
+	j := &Journal{
+		Journal:  &storage.Journal{Object: storage.Object{DB: db}},
+		Chart:    &chart,
+		Balances: balances,
+	}
+	bs := &Balance{Balance: balances, Accruals: accruals, Journal: j}
+	cs := &CorporateAction{
+		CorporateAction: actions,
+		Access:          env.repoAccess,
+		Notification:    &storage.Notification{Object: storage.Object{DB: db}},
+		Journal:         j,
+	}
+
+	const (
+		acc  = model.AccountToken
+		curr = model.Currency("SEC")
+	)
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	tx := func(id string, ts time.Time) context.Context {
+		return model.ContextWithTransaction(ctx, model.Transaction{ID: id, Timestamp: ts})
+	}
+
+	env.repoAccess.EXPECT().HasRole(gomock.Any(), model.Address("regulator"), model.RoleRegulator).Return(true, nil)
+	env.assert.NoError(accruals.AppendRate(ctx, curr, &model.AccrualRate{Rate: big.NewRat(1, 10), Period: 24 * time.Hour}))
+
+	_, err := bs.Deposit(tx("tx1", start), "a", acc, curr, amount(1000))
+	env.assert.NoError(err)
+
+	// the interest of a day is accrued by the transfer
+	_, err = bs.Transfer(tx("tx2", start.Add(24*time.Hour)), "a", "collector", acc, curr, amount(10))
+	env.assert.NoError(err)
+
+	env.assert.NoError(cs.Perform(tx("tx3", start.Add(24*time.Hour)), "regulator", &model.CorporateAction{
+		ID:       "split",
+		Currency: curr,
+		Ratio:    big.NewRat(2, 1),
+		Rounding: model.RoundingDown,
+		Reason:   "2:1 split",
+	}))
+
+	lines, err := j.Reconcile(ctx, curr)
+	env.assert.NoError(err)
+	env.assert.Equal([]model.ReconcileLine{
+		{Account: "AccountToken", Currency: curr, Journal: amount(2180), Stored: amount(2180)},
+		{Account: "Fees", Currency: curr, Journal: amount(20), Stored: amount(20)},
+	}, lines)
+
+	tb, err := j.TrialBalance(ctx)
+	env.assert.NoError(err)
+	env.assert.True(tb.Balanced())
+
+	// the balance saved bypassing the service is not posted
+	env.assert.NoError(balances.Save(tx("tx4", start.Add(24*time.Hour)), "a", acc, curr, big.NewInt(2000)))
+
+	lines, err = j.Reconcile(ctx, curr)
+	env.assert.NoError(err)
+	env.assert.False(lines[0].Matches())
+
+	_, err = (&Journal{Journal: j.Journal}).Reconcile(ctx, curr)
+	env.assert.ErrorIs(err, ErrJournalNoBalances)
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// Limit service errors.
+var (
+	// ErrLimitRepository represents a generic error related to the repository operations.
+	ErrLimitRepository = errors.New("limit repository error")
+
+	// ErrLimitForbidden is returned when the caller has no rights to configure limits.
+	ErrLimitForbidden = errors.New("limit configuration is forbidden")
+
+	// ErrLimitInvalid is returned when the limit configuration fails to validate.
+	ErrLimitInvalid = errors.New("invalid limit configuration")
+)
+
+// Limit is a struct that provides methods to configure outflow limits of addresses
+// and currencies. The limits are enforced by the Balance service.
+//
+//go:generate ifacemaker -f limit.go -o controller/limit.go -i Limit -s Limit -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/limit.go -destination controller/mock/mock_limit.go
+type Limit struct {
+	repository.Limit
+
+	// Access is used to check that the caller holds the model.RoleRegulator role.
+	Access repository.Access
+}
+
+// SetLimit stores the limit configuration for given Address and Currency. An empty
+// address sets the default configuration of the currency, which is applied to all
+// addresses without their own configuration.
+func (ls *Limit) SetLimit(
+	ctx context.Context,
+	regulator model.Address,
+	addr model.Address,
+	curr model.Currency,
+	limit *model.Limit,
+) error {
+	if err := limit.Validate(); err != nil {
+		return ls.wrap(ErrLimitInvalid, err)
+	}
+
+	if err := ls.checkRegulator(ctx, regulator); err != nil {
+		return err
+	}
+
+	if err := ls.Limit.SaveConfig(ctx, addr, curr, limit); err != nil {
+		return ls.wrap(ErrLimitRepository, err)
+	}
+
+	return nil
+}
+
+// GetLimit returns the limit configuration applied to given Address and Currency.
+// If no limits are configured, nil is returned.
+func (ls *Limit) GetLimit(
+	ctx context.Context,
+	addr model.Address,
+	curr model.Currency,
+) (*model.Limit, error) {
+	limit, err := ls.Limit.LoadConfig(ctx, addr, curr)
+	if err != nil {
+		return nil, ls.wrap(ErrLimitRepository, err)
+	}
+
+	return limit, nil
+}
+
+// SetClassLimit stores the limit configuration for given Address and currency class.
+// The class limits apply to the total outflow in all the currencies of the class, in
+// addition to the limits of every currency. An empty address sets the default
+// configuration of the class.
+func (ls *Limit) SetClassLimit(
+	ctx context.Context,
+	regulator model.Address,
+	addr model.Address,
+	class model.CurrencyClass,
+	limit *model.Limit,
+) error {
+	if err := limit.Validate(); err != nil {
+		return ls.wrap(ErrLimitInvalid, err)
+	}
+
+	if err := ls.checkRegulator(ctx, regulator); err != nil {
+		return err
+	}
+
+	if err := ls.Limit.SaveClassConfig(ctx, addr, class, limit); err != nil {
+		return ls.wrap(ErrLimitRepository, err)
+	}
+
+	return nil
+}
+
+// GetClassLimit returns the limit configuration applied to given Address and currency
+// class. If no limits are configured, nil is returned.
+func (ls *Limit) GetClassLimit(
+	ctx context.Context,
+	addr model.Address,
+	class model.CurrencyClass,
+) (*model.Limit, error) {
+	limit, err := ls.Limit.LoadClassConfig(ctx, addr, class)
+	if err != nil {
+		return nil, ls.wrap(ErrLimitRepository, err)
+	}
+
+	return limit, nil
+}
+
+// checkRegulator returns ErrLimitForbidden if the address does not hold the
+// model.RoleRegulator role.
+func (ls *Limit) checkRegulator(ctx context.Context, regulator model.Address) error {
+	if ls.Access == nil {
+		return fmt.Errorf("%w: roles are not configured", ErrLimitForbidden)
+	}
+
+	ok, err := ls.Access.HasRole(ctx, regulator, model.RoleRegulator)
+	if err != nil {
+		return ls.wrap(ErrLimitRepository, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s is not a regulator", ErrLimitForbidden, regulator)
+	}
+
+	return nil
+}
+
+func (ls *Limit) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
@@ -0,0 +1,321 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// NFT service errors.
+var (
+	// ErrNFTRepository represents a generic error related to the repository operations.
+	ErrNFTRepository = errors.New("nft repository error")
+
+	// ErrNFTInvalid is returned when the token fails to validate.
+	ErrNFTInvalid = errors.New("invalid nft")
+
+	// ErrNFTForbidden is returned when the caller has no rights to mint tokens.
+	ErrNFTForbidden = errors.New("nft operation is forbidden")
+
+	// ErrNFTExists is returned when a token with the same identifier already exists in
+	// the collection.
+	ErrNFTExists = errors.New("nft already exists")
+
+	// ErrNFTNotFound is returned when the token does not exist.
+	ErrNFTNotFound = errors.New("nft not found")
+
+	// ErrNFTNotOwner is returned when the token is not owned by the address.
+	ErrNFTNotOwner = errors.New("nft is not owned by the address")
+
+	// ErrNFTAddressFrozen is returned when an operation touches a frozen address.
+	ErrNFTAddressFrozen = errors.New("address is frozen")
+
+	// ErrNFTNoTransaction is returned when the context does not carry the transaction
+	// information, which identifies the records of the changes of the tokens.
+	ErrNFTNoTransaction = errors.New("transaction information is missing")
+)
+
+// NFT is a struct that provides methods to mint, transfer and burn non-fungible
+// tokens, which exist alongside the fungible balances, and to look up their owners.
+// Every change of a token is stored as a notification of the model.NFTEvent.
+//
+//go:generate ifacemaker -f nft.go -o controller/nft.go -i NFT -s NFT -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/nft.go -destination controller/mock/mock_nft.go
+type NFT struct {
+	repository.NFT
+
+	// Access is used to check that the minter holds the model.RoleMinter role and that
+	// the addresses are not frozen.
+	Access repository.Access
+
+	// Notification stores the accounting records of the tokens.
+	Notification repository.Notification
+
+	// Ledger is used to append the accounting records of the tokens to the hash
+	// chain. If it is nil, the chain is not kept.
+	Ledger repository.Ledger
+
+	// Outbox is used to deliver the accounting records of the tokens to the off-chain
+	// consumers. If it is nil, no entries are made.
+	Outbox repository.Outbox
+}
+
+// Mint method creates the token owned by its Owner and stores a
+// model.NotificationTypeNFTMint record of it. It can be executed only by an address
+// holding the model.RoleMinter role. The mint timestamp is assigned by the method.
+func (ns *NFT) Mint(ctx context.Context, minter model.Address, token *model.NFT) error {
+	tx, err := ns.transaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := token.Validate(); err != nil {
+		return ns.wrap(ErrNFTInvalid, err)
+	}
+
+	ok, err := ns.Access.HasRole(ctx, minter, model.RoleMinter)
+	if err != nil {
+		return ns.wrap(ErrNFTRepository, err)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s is not a minter", ErrNFTForbidden, minter)
+	}
+
+	if err := ns.checkFrozen(ctx, token.Owner); err != nil {
+		return err
+	}
+
+	existing, err := ns.NFT.Load(ctx, token.Collection, token.TokenID)
+	if err != nil {
+		return ns.wrap(ErrNFTRepository, err)
+	}
+
+	if existing != nil {
+		return fmt.Errorf("%w: %s/%s", ErrNFTExists, token.Collection, token.TokenID)
+	}
+
+	token.MintedAt = tx.Timestamp
+
+	if err := ns.NFT.Save(ctx, token); err != nil {
+		return ns.wrap(ErrNFTRepository, err)
+	}
+
+	return ns.notify(ctx, model.NotificationTypeNFTMint, "", token.Owner, token)
+}
+
+// Transfer method passes the token from its owner to another address and stores a
+// model.NotificationTypeNFTTransfer record of it.
+func (ns *NFT) Transfer(
+	ctx context.Context,
+	addrFrom, addrTo model.Address,
+	collection, tokenID string,
+) (*model.NFT, error) {
+	if _, err := ns.transaction(ctx); err != nil {
+		return nil, err
+	}
+
+	if addrTo == "" {
+		return nil, fmt.Errorf("%w: empty recipient", ErrNFTInvalid)
+	}
+
+	token, err := ns.owned(ctx, addrFrom, collection, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ns.checkFrozen(ctx, addrFrom, addrTo); err != nil {
+		return nil, err
+	}
+
+	token.Owner = addrTo
+
+	if err := ns.NFT.Save(ctx, token); err != nil {
+		return nil, ns.wrap(ErrNFTRepository, err)
+	}
+
+	if err := ns.notify(ctx, model.NotificationTypeNFTTransfer, addrFrom, addrTo, token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// Burn method destroys the token of the owner and stores a
+// model.NotificationTypeNFTBurn record of it.
+func (ns *NFT) Burn(ctx context.Context, owner model.Address, collection, tokenID string) error {
+	if _, err := ns.transaction(ctx); err != nil {
+		return err
+	}
+
+	token, err := ns.owned(ctx, owner, collection, tokenID)
+	if err != nil {
+		return err
+	}
+
+	if err := ns.checkFrozen(ctx, owner); err != nil {
+		return err
+	}
+
+	if err := ns.NFT.Delete(ctx, collection, tokenID); err != nil {
+		return ns.wrap(ErrNFTRepository, err)
+	}
+
+	return ns.notify(ctx, model.NotificationTypeNFTBurn, owner, "", token)
+}
+
+// OwnerOf retrieves the token together with its owner.
+func (ns *NFT) OwnerOf(ctx context.Context, collection, tokenID string) (*model.NFT, error) {
+	token, err := ns.NFT.Load(ctx, collection, tokenID)
+	if err != nil {
+		return nil, ns.wrap(ErrNFTRepository, err)
+	}
+
+	if token == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNFTNotFound, collection, tokenID)
+	}
+
+	return token, nil
+}
+
+// TokensOf retrieves a page of the tokens of the owner in ascending order of their
+// collections and identifiers. The next page is requested with the bookmark returned
+// with the previous one.
+func (ns *NFT) TokensOf(
+	ctx context.Context,
+	owner model.Address,
+	bookmark string,
+	limit int,
+) (*model.NFTPage, error) {
+	page, err := ns.NFT.ByOwner(ctx, owner, bookmark, limit)
+	if err != nil {
+		return nil, ns.wrap(ErrNFTRepository, err)
+	}
+
+	return page, nil
+}
+
+// Collection retrieves a page of the tokens of the collection in ascending order of
+// their identifiers.
+func (ns *NFT) Collection(
+	ctx context.Context,
+	collection, bookmark string,
+	limit int,
+) (*model.NFTPage, error) {
+	page, err := ns.NFT.ByCollection(ctx, collection, bookmark, limit)
+	if err != nil {
+		return nil, ns.wrap(ErrNFTRepository, err)
+	}
+
+	return page, nil
+}
+
+// owned retrieves the token and checks that it is owned by the address.
+func (ns *NFT) owned(
+	ctx context.Context,
+	owner model.Address,
+	collection, tokenID string,
+) (*model.NFT, error) {
+	token, err := ns.OwnerOf(ctx, collection, tokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	if token.Owner != owner {
+		return nil, fmt.Errorf("%w: %s/%s, %s", ErrNFTNotOwner, collection, tokenID, owner)
+	}
+
+	return token, nil
+}
+
+// notify stores the accounting record of the change of the token with the first
+// identifier of the token in the transaction not taken by a record of the type.
+func (ns *NFT) notify(
+	ctx context.Context,
+	typ string,
+	addrFrom, addrTo model.Address,
+	token *model.NFT,
+) error {
+	tx, err := ns.transaction(ctx)
+	if err != nil {
+		return err
+	}
+
+	var id string
+	for seq := 1; ; seq++ {
+		id = model.NFTEventID(tx.ID, token.Collection, token.TokenID, seq)
+
+		existing, err := ns.Notification.Load(ctx, typ, id)
+		if err != nil {
+			return ns.wrap(ErrNFTRepository, err)
+		}
+
+		if existing == nil {
+			break
+		}
+	}
+
+	raw, err := model.EncodeNotification(model.Notification[model.NFTEvent]{
+		ID:   id,
+		Type: typ,
+		Body: model.NFTEvent{
+			Collection:   token.Collection,
+			TokenID:      token.TokenID,
+			From:         addrFrom,
+			To:           addrTo,
+			MetadataURI:  token.MetadataURI,
+			MetadataHash: token.MetadataHash,
+		},
+	})
+	if err != nil {
+		return ns.wrap(ErrNFTInvalid, err)
+	}
+
+	if err := ns.Notification.SaveRaw(ctx, raw); err != nil {
+		return ns.wrap(ErrNFTRepository, err)
+	}
+
+	if err := appendLedger(ctx, ns.Ledger, raw.Type, raw.ID, raw.Body); err != nil {
+		return ns.wrap(ErrNFTRepository, err)
+	}
+
+	if err := publishOutbox(ctx, ns.Outbox, raw.Type, raw.ID); err != nil {
+		return ns.wrap(ErrNFTRepository, err)
+	}
+
+	return nil
+}
+
+// transaction returns the transaction carried by the context, which must have an
+// identifier.
+func (ns *NFT) transaction(ctx context.Context) (model.Transaction, error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok || tx.ID == "" {
+		return tx, ErrNFTNoTransaction
+	}
+
+	return tx, nil
+}
+
+// checkFrozen returns ErrNFTAddressFrozen if any of the addresses is frozen.
+func (ns *NFT) checkFrozen(ctx context.Context, addrs ...model.Address) error {
+	for _, addr := range addrs {
+		frozen, err := ns.Access.IsFrozen(ctx, addr)
+		if err != nil {
+			return ns.wrap(ErrNFTRepository, err)
+		}
+
+		if frozen {
+			return fmt.Errorf("%w: %s", ErrNFTAddressFrozen, addr)
+		}
+	}
+
+	return nil
+}
+
+func (ns *NFT) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNFT(t *testing.T) {
+	env := newEnvironment(t)
+
+	db := new(inmem.KeyValueDB)
+	notifications := &storage.Notification{Object: storage.Object{DB: db}}
+
+	ns := &NFT{
+		NFT:          &storage.NFT{Object: storage.Object{DB: db}},
+		Access:       env.repoAccess,
+		Notification: notifications,
+	}
+
+	const (
+		minter = model.Address("minter")
+		alice  = model.Address("alice")
+		bob    = model.Address("bob")
+		carol  = model.Address("carol")
+		frozen = model.Address("frozen")
+	)
+
+	tx := func(id string) context.Context {
+		return model.ContextWithTransaction(ctx, model.Transaction{ID: id})
+	}
+
+	env.repoAccess.EXPECT().HasRole(gomock.Any(), minter, model.RoleMinter).Return(true, nil).AnyTimes()
+	env.repoAccess.EXPECT().HasRole(gomock.Any(), alice, model.RoleMinter).Return(false, nil).AnyTimes()
+	env.repoAccess.EXPECT().IsFrozen(gomock.Any(), frozen).Return(true, nil).AnyTimes()
+	env.repoAccess.EXPECT().IsFrozen(gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes()
+
+	mint := func(txID string, by model.Address, collection, id string, owner model.Address) error {
+		return ns.Mint(tx(txID), by, &model.NFT{
+			Collection:   collection,
+			TokenID:      id,
+			Owner:        owner,
+			MetadataURI:  "ipfs://" + collection + "/" + id,
+			MetadataHash: "hash-" + id,
+		})
+	}
+
+	env.assert.NoError(mint("tx1", minter, "art", "1", alice))
+	env.assert.NoError(mint("tx1", minter, "art", "2", alice))
+	env.assert.NoError(mint("tx1", minter, "music", "1", alice))
+	env.assert.NoError(mint("tx1", minter, "artist", "1", bob))
+
+	env.assert.ErrorIs(mint("tx2", minter, "art", "1", bob), ErrNFTExists)
+	env.assert.ErrorIs(mint("tx2", alice, "art", "3", alice), ErrNFTForbidden)
+	env.assert.ErrorIs(mint("tx2", minter, "art/x", "3", alice), ErrNFTInvalid)
+	env.assert.ErrorIs(mint("tx2", minter, "art", "3", frozen), ErrNFTAddressFrozen)
+
+	token, err := ns.OwnerOf(ctx, "art", "1")
+	env.assert.NoError(err)
+	env.assert.Equal(alice, token.Owner)
+	env.assert.Equal("hash-1", token.MetadataHash)
+
+	_, err = ns.OwnerOf(ctx, "art", "3")
+	env.assert.ErrorIs(err, ErrNFTNotFound)
+
+	// the tokens of the owner are paged in the order of collections and identifiers
+	page, err := ns.TokensOf(ctx, alice, "", 2)
+	env.assert.NoError(err)
+	env.assert.Len(page.Items, 2)
+	env.assert.Equal("art/2", page.Bookmark)
+
+	page, err = ns.TokensOf(ctx, alice, page.Bookmark, 2)
+	env.assert.NoError(err)
+	env.assert.Len(page.Items, 1)
+	env.assert.Equal("music", page.Items[0].Collection)
+	env.assert.Empty(page.Bookmark)
+
+	// the collections sharing the beginning are paged in the order of the ledger
+	env.assert.NoError(mint("tx1", minter, "a", "x", carol))
+	env.assert.NoError(mint("tx1", minter, "a-b", "1", carol))
+
+	page, err = ns.TokensOf(ctx, carol, "", 1)
+	env.assert.NoError(err)
+	env.assert.Equal("a/x", page.Bookmark)
+
+	page, err = ns.TokensOf(ctx, carol, page.Bookmark, 1)
+	env.assert.NoError(err)
+	env.assert.Len(page.Items, 1)
+	env.assert.Equal("a-b", page.Items[0].Collection)
+
+	// transfer
+	_, err = ns.Transfer(tx("tx3"), bob, alice, "art", "1")
+	env.assert.ErrorIs(err, ErrNFTNotOwner)
+
+	_, err = ns.Transfer(tx("tx3"), alice, frozen, "art", "1")
+	env.assert.ErrorIs(err, ErrNFTAddressFrozen)
+
+	token, err = ns.Transfer(tx("tx3"), alice, bob, "art", "1")
+	env.assert.NoError(err)
+	env.assert.Equal(bob, token.Owner)
+
+	_, err = ns.Transfer(tx("tx3"), alice, bob, "art", "1")
+	env.assert.ErrorIs(err, ErrNFTNotOwner)
+
+	page, err = ns.TokensOf(ctx, bob, "", 0)
+	env.assert.NoError(err)
+	env.assert.Len(page.Items, 2)
+	env.assert.Equal("art", page.Items[0].Collection)
+	env.assert.Equal("artist", page.Items[1].Collection)
+
+	page, err = ns.TokensOf(ctx, alice, "", 0)
+	env.assert.NoError(err)
+	env.assert.Len(page.Items, 2)
+
+	// the collection does not include the tokens of other collections sharing the
+	// same beginning
+	page, err = ns.Collection(ctx, "art", "", 0)
+	env.assert.NoError(err)
+	env.assert.Len(page.Items, 2)
+
+	// burn
+	env.assert.ErrorIs(ns.Burn(tx("tx4"), alice, "art", "1"), ErrNFTNotOwner)
+	env.assert.NoError(ns.Burn(tx("tx4"), bob, "art", "1"))
+	env.assert.ErrorIs(ns.Burn(tx("tx4"), bob, "art", "1"), ErrNFTNotFound)
+
+	page, err = ns.TokensOf(ctx, bob, "", 0)
+	env.assert.NoError(err)
+	env.assert.Len(page.Items, 1)
+
+	// the token passed twice in one transaction keeps both records
+	_, err = ns.Transfer(tx("tx5"), alice, bob, "art", "2")
+	env.assert.NoError(err)
+
+	_, err = ns.Transfer(tx("tx5"), bob, alice, "art", "2")
+	env.assert.NoError(err)
+
+	// the records are identified by the transaction
+	_, err = ns.Transfer(ctx, alice, bob, "art", "2")
+	env.assert.ErrorIs(err, ErrNFTNoTransaction)
+
+	_, err = ns.Transfer(tx(""), alice, bob, "art", "2")
+	env.assert.ErrorIs(err, ErrNFTNoTransaction)
+
+	// notifications
+	for typ, ids := range map[string][]string{
+		model.NotificationTypeNFTMint:     {"tx1:art:1:1"},
+		model.NotificationTypeNFTTransfer: {"tx3:art:1:1", "tx5:art:2:1", "tx5:art:2:2"},
+		model.NotificationTypeNFTBurn:     {"tx4:art:1:1"},
+	} {
+		for _, id := range ids {
+			raw, err := notifications.Load(ctx, typ, id)
+			env.assert.NoError(err)
+			env.assert.NotNil(raw, id)
+		}
+	}
+
+	raw, err := notifications.Load(ctx, model.NotificationTypeNFTTransfer, "tx5:art:2:2")
+	env.assert.NoError(err)
+
+	event, err := model.DecodeNotification[model.NFTEvent](raw)
+	env.assert.NoError(err)
+	env.assert.Equal(bob, event.Body.From)
+	env.assert.Equal(alice, event.Body.To)
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -16,6 +17,13 @@ var (
 	// ErrNotificationValidation сигнализирует о попытке записи уведомления в репозитарий
 	// сервиса, которое не прошло валидацию полей.
 	ErrNotificationValidation = errors.New("invalid notification validation")
+
+	// ErrNotificationNotFound сигнализирует об отсутствии запрошенного уведомления.
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	// ErrNotificationNoLedger сигнализирует о проверке бухгалтерской книги, которая
+	// не ведет цепочку хешей.
+	ErrNotificationNoLedger = errors.New("notification ledger is not configured")
 )
 
 // Notification отвечает за работу с различными бухгалтерскими структурами. Он сохраняет или
@@ -28,6 +36,15 @@ var (
 //go:generate mockgen -package mock -source controller/notification.go -destination controller/mock/mock_notification.go
 type Notification struct {
 	repository.Notification
+
+	// Ledger ведет цепочку хешей сохраненных уведомлений, которая позволяет доказать,
+	// что ни одно уведомление не было удалено или изменено. Если Ledger не задан,
+	// цепочка не ведется.
+	Ledger repository.Ledger
+
+	// Outbox получает записи о сохраненных уведомлениях для доставки потребителям
+	// внешних систем, см. Outbox. Если Outbox не задан, записи не создаются.
+	Outbox repository.Outbox
 }
 
 // NotifyBalancesUpdate добавляет новую запись в бухгалтерскую книгу, о движении средств
@@ -44,5 +61,226 @@ func (n *Notification) NotifyBalancesUpdate(
 		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
 	}
 
+	if err := appendLedger(ctx, n.Ledger, bu.Type, bu.ID, bu.Body); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	if err := publishOutbox(ctx, n.Outbox, bu.Type, bu.ID); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Fetch возвращает запись бухгалтерской книги по типу и идентификатору уведомления.
+// Тело уведомления не декодируется, для этого используется model.DecodeNotification.
+func (n *Notification) Fetch(ctx context.Context, typ, id string) (*model.RawNotification, error) {
+	raw, err := n.Notification.Load(ctx, typ, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	if raw == nil {
+		return nil, fmt.Errorf("%w: %s/%s", ErrNotificationNotFound, typ, id)
+	}
+
+	return raw, nil
+}
+
+// Query возвращает страницу записей бухгалтерской книги, отобранных по типу, адресу и
+// интервалу времени. Следующая страница запрашивается с закладкой, полученной вместе
+// с предыдущей.
+func (n *Notification) Query(
+	ctx context.Context,
+	q model.NotificationQuery,
+) (*model.NotificationPage, error) {
+	if err := q.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationValidation, err.Error())
+	}
+
+	page, err := n.Notification.Query(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	return page, nil
+}
+
+// Notify добавляет в бухгалтерскую книгу уведомление произвольного типа, который
+// зарегистрирован функцией model.RegisterNotification с телом типа T. Перед сохранением
+// уведомление проверяется валидатором, заданным при регистрации.
+func Notify[T any](ctx context.Context, n *Notification, nt model.Notification[T]) error {
+	if err := nt.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationValidation, err.Error())
+	}
+
+	raw, err := model.EncodeNotification(nt)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationValidation, err.Error())
+	}
+
+	if err := n.Notification.SaveRaw(ctx, raw); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	if err := appendLedger(ctx, n.Ledger, raw.Type, raw.ID, raw.Body); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	if err := publishOutbox(ctx, n.Outbox, raw.Type, raw.ID); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
 	return nil
 }
+
+// History возвращает страницу изменений балансов адреса, начиная с последних, с
+// возможностью отбора по валюте и времени. Следующая страница запрашивается с
+// закладкой, полученной вместе с предыдущей.
+func (n *Notification) History(ctx context.Context, q model.HistoryQuery) (*model.HistoryPage, error) {
+	if err := q.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationValidation, err.Error())
+	}
+
+	page, err := n.Notification.History(ctx, q)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	return page, nil
+}
+
+// Verify проверяет цепочку хешей бухгалтерской книги: последовательность номеров
+// записей, связь каждой записи с предыдущей, хеши записей и тел уведомлений, а также
+// соответствие головы цепочки последней записи. Возвращается описание первого
+// нарушения или nil, если цепочка не нарушена.
+func (n *Notification) Verify(ctx context.Context) (*model.LedgerBreak, error) {
+	if n.Ledger == nil {
+		return nil, ErrNotificationNoLedger
+	}
+
+	var (
+		prev    *model.LedgerEntry
+		broken  *model.LedgerBreak
+		loadErr error
+	)
+
+	if err := n.Ledger.Iter(ctx, func(e *model.LedgerEntry) bool {
+		var reason string
+		if reason, loadErr = n.checkEntry(ctx, prev, e); loadErr != nil {
+			return true
+		}
+
+		if reason != "" {
+			broken = &model.LedgerBreak{Seq: e.Seq, Type: e.Type, ID: e.ID, Reason: reason}
+			return true
+		}
+
+		prev = e
+
+		return false
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	if loadErr != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, loadErr.Error())
+	}
+
+	if broken != nil {
+		return broken, nil
+	}
+
+	// удаление последних записей обнаруживается по голове цепочки
+	head, err := n.Ledger.Head(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err.Error())
+	}
+
+	switch {
+	case head == nil && prev == nil:
+		return nil, nil //nolint:nilnil
+	case head == nil:
+		return &model.LedgerBreak{Seq: prev.Seq, Type: prev.Type, ID: prev.ID, Reason: "head of the chain is missing"}, nil
+	case prev == nil || head.Hash != prev.Hash:
+		return &model.LedgerBreak{Seq: head.Seq, Type: head.Type, ID: head.ID, Reason: "head does not match the last entry"}, nil
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// checkEntry проверяет запись цепочки и возвращает описание нарушения.
+func (n *Notification) checkEntry(
+	ctx context.Context,
+	prev, e *model.LedgerEntry,
+) (string, error) {
+	var (
+		seq      uint64 = 1
+		prevHash string
+	)
+
+	if prev != nil {
+		seq, prevHash = prev.Seq+1, prev.Hash
+	}
+
+	switch {
+	case e.Seq != seq:
+		return fmt.Sprintf("sequence number is %d, expected %d", e.Seq, seq), nil
+	case e.PrevHash != prevHash:
+		return "previous hash does not match the previous entry", nil
+	case e.Hash != e.ComputeHash():
+		return "entry hash does not match its contents", nil
+	}
+
+	raw, err := n.Notification.Load(ctx, e.Type, e.ID)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case raw == nil:
+		return "notification is missing", nil
+	case model.HashLedgerBody(raw.Body) != e.BodyHash:
+		return "notification body is altered", nil
+	}
+
+	return "", nil
+}
+
+// appendLedger добавляет в цепочку хешей запись о сохраненном уведомлении. Если
+// цепочка не ведется, ничего не делается.
+func appendLedger(
+	ctx context.Context,
+	ledger repository.Ledger,
+	typ, id string,
+	body any,
+) error {
+	if ledger == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	head, err := ledger.Head(ctx)
+	if err != nil {
+		return err
+	}
+
+	e := &model.LedgerEntry{
+		Seq:      1,
+		Type:     typ,
+		ID:       id,
+		BodyHash: model.HashLedgerBody(data),
+	}
+
+	if head != nil {
+		e.Seq, e.PrevHash = head.Seq+1, head.Hash
+	}
+
+	e.Hash = e.ComputeHash()
+
+	return ledger.Append(ctx, e)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/keyvalue/cache"
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_Verify(t *testing.T) {
+	db := new(inmem.KeyValueDB)
+	n := &Notification{
+		Notification: &storage.Notification{Object: storage.Object{DB: db}},
+		Ledger:       &storage.Ledger{Object: storage.Object{DB: db}},
+	}
+
+	now := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	for _, id := range []string{"tx1", "tx2", "tx3"} {
+		txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: id, Timestamp: now})
+		assert.NoError(t, n.NotifyBalancesUpdate(txCtx, model.Notification[model.BalancesUpdate]{
+			ID:   id,
+			Type: model.NotificationTypeBalancesUpdate,
+			Body: model.BalancesUpdate{{
+				Address:    user1.address,
+				Account:    user1.account1.account,
+				Currency:   user1.account1.currency,
+				ValueDelta: amount(100),
+			}},
+		}))
+	}
+
+	broken, err := n.Verify(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, broken)
+
+	// altering the body of the second notification
+	key := keyvalue.Key(keyvalue.Join(model.NotificationTypeBalancesUpdate, "tx2"))
+	raw, err := db.Get(ctx, key)
+	assert.NoError(t, err)
+
+	altered := model.RawNotification{}
+	assert.NoError(t, altered.UnmarshalBinary(raw))
+	altered.Body = []byte(`[]`)
+	data, _ := altered.MarshalBinary()
+	assert.NoError(t, db.Set(ctx, key, data))
+
+	broken, err = n.Verify(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.LedgerBreak{
+		Seq:    2,
+		Type:   model.NotificationTypeBalancesUpdate,
+		ID:     "tx2",
+		Reason: "notification body is altered",
+	}, broken)
+
+	// removing the first notification
+	assert.NoError(t, db.Del(ctx, keyvalue.Key(keyvalue.Join(model.NotificationTypeBalancesUpdate, "tx1"))))
+
+	broken, err = n.Verify(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), broken.Seq)
+	assert.Equal(t, "notification is missing", broken.Reason)
+
+	_, err = (&Notification{}).Verify(ctx)
+	assert.ErrorIs(t, err, ErrNotificationNoLedger)
+}
+
+func TestNotification_LedgerInstances(t *testing.T) {
+	stub := new(stubDB)
+	db := &cache.KeyValueDB{DB: stub}
+
+	// the services of the transaction keep their own ledger instances over the shared
+	// database, which must see the entries appended by each other
+	notifications := &storage.Notification{Object: storage.Object{DB: db}}
+	n := &Notification{Notification: notifications, Ledger: &storage.Ledger{Object: storage.Object{DB: db}}}
+	other := &Notification{Notification: notifications, Ledger: &storage.Ledger{Object: storage.Object{DB: db}}}
+
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1"})
+	for i, ns := range []*Notification{n, other} {
+		assert.NoError(t, ns.NotifyBalancesUpdate(txCtx, model.Notification[model.BalancesUpdate]{
+			ID:   fmt.Sprintf("tx1:%d", i),
+			Type: model.NotificationTypeBalancesUpdate,
+			Body: model.BalancesUpdate{{
+				Address:    user1.address,
+				Account:    user1.account1.account,
+				Currency:   user1.account1.currency,
+				ValueDelta: amount(100),
+			}},
+		}))
+	}
+
+	stub.commit(ctx)
+
+	head, err := n.Ledger.Head(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), head.Seq)
+
+	broken, err := n.Verify(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, broken)
+}
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage/repository"
+)
+
+// Outbox service errors.
+var (
+	// ErrOutboxRepository represents a generic error related to the repository operations.
+	ErrOutboxRepository = errors.New("outbox repository error")
+
+	// ErrOutboxConsumerNotFound is returned when the consumer is not registered.
+	ErrOutboxConsumerNotFound = errors.New("outbox consumer not found")
+
+	// ErrOutboxInvalidAck is returned when the acknowledged sequence number is not
+	// delivered to the consumer yet or is already acknowledged.
+	ErrOutboxInvalidAck = errors.New("invalid outbox acknowledgement")
+)
+
+// Outbox settings.
+const (
+	// OutboxFetchLimit is the number of entries returned by Fetch when no limit is set.
+	OutboxFetchLimit = 100
+
+	// OutboxPruneLimit is the maximum number of entries removed by a single Prune, so
+	// the transaction stays bounded when the consumers acknowledge many entries.
+	OutboxPruneLimit = 1000
+)
+
+// Outbox delivers stored notifications to the consumers of the off-chain systems
+// exactly once. Every notification stored by the Notification and Balance services
+// with the configured outbox gets a sequence-ordered entry. Consumers fetch the
+// entries after their cursors and acknowledge the processed ones, which moves the
+// cursors forward. Entries acknowledged by all the registered consumers are pruned.
+//
+//go:generate ifacemaker -f outbox.go -o controller/outbox.go -i Outbox -s Outbox -p controller -y "Controller describes methods, implemented by the service package."
+//go:generate mockgen -package mock -source controller/outbox.go -destination controller/mock/mock_outbox.go
+type Outbox struct {
+	repository.Outbox
+}
+
+// Publish appends an entry referencing the notification of given type and identifier.
+func (o *Outbox) Publish(ctx context.Context, typ, id string) error {
+	if err := publishOutbox(ctx, o.Outbox, typ, id); err != nil {
+		return o.wrap(ErrOutboxRepository, err)
+	}
+
+	return nil
+}
+
+// Register adds the consumer, which receives all the entries not pruned yet.
+// Registering an existing consumer keeps its cursor.
+func (o *Outbox) Register(ctx context.Context, consumer string) error {
+	c, err := o.Outbox.LoadConsumer(ctx, consumer)
+	if err != nil {
+		return o.wrap(ErrOutboxRepository, err)
+	}
+
+	if c != nil {
+		return nil
+	}
+
+	if err := o.Outbox.SaveConsumer(ctx, &model.OutboxConsumer{Name: consumer}); err != nil {
+		return o.wrap(ErrOutboxRepository, err)
+	}
+
+	return nil
+}
+
+// Unregister removes the consumer, so its cursor no longer holds back pruning.
+func (o *Outbox) Unregister(ctx context.Context, consumer string) error {
+	if _, err := o.load(ctx, consumer); err != nil {
+		return err
+	}
+
+	if err := o.Outbox.DeleteConsumer(ctx, consumer); err != nil {
+		return o.wrap(ErrOutboxRepository, err)
+	}
+
+	return nil
+}
+
+// Fetch retrieves up to limit entries after the cursor of the consumer. If limit is
+// not positive, OutboxFetchLimit is used. Fetching does not move the cursor, so the
+// same entries are returned until they are acknowledged.
+func (o *Outbox) Fetch(ctx context.Context, consumer string, limit int) ([]*model.OutboxEntry, error) {
+	c, err := o.load(ctx, consumer)
+	if err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = OutboxFetchLimit
+	}
+
+	entries, err := o.Outbox.List(ctx, c.Cursor, limit)
+	if err != nil {
+		return nil, o.wrap(ErrOutboxRepository, err)
+	}
+
+	return entries, nil
+}
+
+// Ack acknowledges all the entries of the consumer up to the sequence number
+// inclusive and moves its cursor to it.
+func (o *Outbox) Ack(ctx context.Context, consumer string, seq uint64) error {
+	c, err := o.load(ctx, consumer)
+	if err != nil {
+		return err
+	}
+
+	last, err := o.Outbox.Last(ctx)
+	if err != nil {
+		return o.wrap(ErrOutboxRepository, err)
+	}
+
+	if seq <= c.Cursor || seq > last {
+		return fmt.Errorf("%w: %d is out of range (%d, %d]", ErrOutboxInvalidAck, seq, c.Cursor, last)
+	}
+
+	c.Cursor = seq
+
+	if err := o.Outbox.SaveConsumer(ctx, c); err != nil {
+		return o.wrap(ErrOutboxRepository, err)
+	}
+
+	return nil
+}
+
+// Prune removes the entries acknowledged by all the registered consumers and returns
+// their number. At most OutboxPruneLimit entries are removed, the rest are removed by
+// the next calls. Nothing is removed while there are no consumers.
+func (o *Outbox) Prune(ctx context.Context) (int, error) {
+	consumers, err := o.Outbox.Consumers(ctx)
+	if err != nil {
+		return 0, o.wrap(ErrOutboxRepository, err)
+	}
+
+	if len(consumers) == 0 {
+		return 0, nil
+	}
+
+	var acked uint64 = math.MaxUint64
+	for _, c := range consumers {
+		acked = min(acked, c.Cursor)
+	}
+
+	pruned, err := o.Outbox.Pruned(ctx)
+	if err != nil {
+		return 0, o.wrap(ErrOutboxRepository, err)
+	}
+
+	if acked <= pruned {
+		return 0, nil
+	}
+
+	entries, err := o.Outbox.List(ctx, pruned, int(min(acked-pruned, OutboxPruneLimit)))
+	if err != nil {
+		return 0, o.wrap(ErrOutboxRepository, err)
+	}
+
+	for _, e := range entries {
+		if err := o.Outbox.Delete(ctx, e.Seq); err != nil {
+			return 0, o.wrap(ErrOutboxRepository, err)
+		}
+	}
+
+	if len(entries) != 0 {
+		if err := o.Outbox.SavePruned(ctx, entries[len(entries)-1]); err != nil {
+			return 0, o.wrap(ErrOutboxRepository, err)
+		}
+	}
+
+	return len(entries), nil
+}
+
+// load retrieves the registered consumer.
+func (o *Outbox) load(ctx context.Context, consumer string) (*model.OutboxConsumer, error) {
+	c, err := o.Outbox.LoadConsumer(ctx, consumer)
+	if err != nil {
+		return nil, o.wrap(ErrOutboxRepository, err)
+	}
+
+	if c == nil {
+		return nil, fmt.Errorf("%w: %s", ErrOutboxConsumerNotFound, consumer)
+	}
+
+	return c, nil
+}
+
+func (o *Outbox) wrap(err, cause error) error {
+	return fmt.Errorf("%w: %s", err, cause.Error())
+}
+
+// publishOutbox appends an entry referencing the notification to the outbox. If
+// the outbox is nil, nothing is done.
+func publishOutbox(ctx context.Context, outbox repository.Outbox, typ, id string) error {
+	if outbox == nil {
+		return nil
+	}
+
+	last, err := outbox.Last(ctx)
+	if err != nil {
+		return err
+	}
+
+	return outbox.Append(ctx, &model.OutboxEntry{Seq: last + 1, Type: typ, ID: id})
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/storage"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutbox(t *testing.T) {
+	db := new(inmem.KeyValueDB)
+	outbox := &Outbox{Outbox: &storage.Outbox{Object: storage.Object{DB: db}}}
+	n := &Notification{
+		Notification: &storage.Notification{Object: storage.Object{DB: db}},
+		Outbox:       outbox.Outbox,
+	}
+
+	assert.NoError(t, outbox.Register(ctx, "erp"))
+	assert.NoError(t, outbox.Register(ctx, "dwh"))
+
+	// two notifications of the same transaction get consecutive entries
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1"})
+	for _, id := range []string{"n1", "n2", "n3"} {
+		assert.NoError(t, n.NotifyBalancesUpdate(txCtx, model.Notification[model.BalancesUpdate]{
+			ID:   id,
+			Type: model.NotificationTypeBalancesUpdate,
+			Body: model.BalancesUpdate{{
+				Address:    user1.address,
+				Account:    user1.account1.account,
+				Currency:   user1.account1.currency,
+				ValueDelta: amount(100),
+			}},
+		}))
+	}
+
+	entries, err := outbox.Fetch(ctx, "erp", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.OutboxEntry{
+		{Seq: 1, Type: model.NotificationTypeBalancesUpdate, ID: "n1"},
+		{Seq: 2, Type: model.NotificationTypeBalancesUpdate, ID: "n2"},
+	}, entries)
+
+	assert.NoError(t, outbox.Ack(ctx, "erp", 2))
+	assert.ErrorIs(t, outbox.Ack(ctx, "erp", 2), ErrOutboxInvalidAck)
+	assert.ErrorIs(t, outbox.Ack(ctx, "erp", 4), ErrOutboxInvalidAck)
+	assert.NoError(t, outbox.Ack(ctx, "dwh", 1))
+
+	pruned, err := outbox.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	entries, err = outbox.Fetch(ctx, "erp", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.OutboxEntry{
+		{Seq: 3, Type: model.NotificationTypeBalancesUpdate, ID: "n3"},
+	}, entries)
+
+	entries, err = outbox.Fetch(ctx, "dwh", 0)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	assert.NoError(t, outbox.Unregister(ctx, "dwh"))
+
+	pruned, err = outbox.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, pruned)
+
+	_, err = outbox.Fetch(ctx, "dwh", 0)
+	assert.ErrorIs(t, err, ErrOutboxConsumerNotFound)
+
+	// a consumer registered after the pruning starts from the first entry not pruned
+	assert.NoError(t, outbox.Register(ctx, "audit"))
+	assert.NoError(t, outbox.Publish(ctx, model.NotificationTypeBalancesUpdate, "n4"))
+
+	entries, err = outbox.Fetch(ctx, "audit", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.OutboxEntry{
+		{Seq: 3, Type: model.NotificationTypeBalancesUpdate, ID: "n3"},
+		{Seq: 4, Type: model.NotificationTypeBalancesUpdate, ID: "n4"},
+	}, entries)
+
+	pruned, err = outbox.Prune(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, pruned)
+}
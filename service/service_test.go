@@ -5,6 +5,8 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/keyvalue/inmem"
 	"github.com/anoideaopen/token/model"
 	ctrl "github.com/anoideaopen/token/service/controller/mock"
 	repo "github.com/anoideaopen/token/storage/repository/mock"
@@ -61,14 +63,80 @@ type environment struct {
 	ctrlGomock  *gomock.Controller
 	repoBalance *repo.MockBalance
 	ctrlBalance *ctrl.MockBalance
+
+	ctrlNotification *ctrl.MockNotification
+
+	repoAccess       *repo.MockAccess
+	repoNotification *repo.MockNotification
+	repoLimit        *repo.MockLimit
+	repoAllowList    *repo.MockAllowList
+	repoCreditLine   *repo.MockCreditLine
+	repoHold         *repo.MockHold
 }
 
 func newEnvironment(t *testing.T) *environment {
 	ctrlGomock := gomock.NewController(t)
-	return &environment{
+	env := &environment{
 		assert:      assert.New(t),
 		ctrlGomock:  ctrlGomock,
 		repoBalance: repo.NewMockBalance(ctrlGomock),
 		ctrlBalance: ctrl.NewMockBalance(ctrlGomock),
+
+		ctrlNotification: ctrl.NewMockNotification(ctrlGomock),
+
+		repoAccess:       repo.NewMockAccess(ctrlGomock),
+		repoNotification: repo.NewMockNotification(ctrlGomock),
+		repoLimit:        repo.NewMockLimit(ctrlGomock),
+		repoAllowList:    repo.NewMockAllowList(ctrlGomock),
+		repoCreditLine:   repo.NewMockCreditLine(ctrlGomock),
+		repoHold:         repo.NewMockHold(ctrlGomock),
 	}
+
+	env.repoBalance.EXPECT().Accumulates().Return(false).AnyTimes()
+
+	return env
+}
+
+func amount(v int64) *model.Amount {
+	return model.NewAmount(big.NewInt(v), 0)
+}
+
+// stubDB behaves like the chaincode stub: the writes are not visible until they are
+// committed.
+type stubDB struct {
+	inmem.KeyValueDB
+
+	pending map[keyvalue.Key]*keyvalue.Value
+}
+
+func (db *stubDB) Set(_ context.Context, k keyvalue.Key, v keyvalue.Value) error {
+	if db.pending == nil {
+		db.pending = make(map[keyvalue.Key]*keyvalue.Value)
+	}
+
+	db.pending[k] = &v
+
+	return nil
+}
+
+func (db *stubDB) Del(_ context.Context, k keyvalue.Key) error {
+	if db.pending == nil {
+		db.pending = make(map[keyvalue.Key]*keyvalue.Value)
+	}
+
+	db.pending[k] = nil
+
+	return nil
+}
+
+func (db *stubDB) commit(ctx context.Context) {
+	for k, v := range db.pending {
+		if v == nil {
+			_ = db.KeyValueDB.Del(ctx, k)
+		} else {
+			_ = db.KeyValueDB.Set(ctx, k, *v)
+		}
+	}
+
+	db.pending = nil
 }
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/anoideaopen/token/model"
+	"github.com/anoideaopen/token/service/controller"
+)
+
+var _ controller.Balance = &UnitOfWork{}
+
+// Unit of work errors.
+var (
+	// ErrUnitOfWorkNoTransaction is returned when a balance operation of the unit of
+	// work is called with a context, which does not carry the transaction information.
+	ErrUnitOfWorkNoTransaction = errors.New("unit of work requires transaction information")
+
+	// ErrUnitOfWorkCommitted is returned when the updates of a transaction are committed
+	// after its record has been stored.
+	ErrUnitOfWorkCommitted = errors.New("unit of work is already committed")
+)
+
+// UnitOfWork wraps the balance service and collects every model.BalanceUpdate produced
+// by its operations within a transaction. Commit persists the collected updates as a
+// single model.NotificationTypeBalancesUpdate notification, whose identifier is the
+// transaction ID, so a transaction produces at most one such record and a second
+// Commit with new updates fails.
+//
+// The updates are grouped by the transaction ID, so a single UnitOfWork may be shared
+// by concurrently executed transactions. Operations which do not change balances, and
+// ForcedTransfer and Reverse, which store their own notifications, are passed to the
+// wrapped service as is. The journal is posted by the wrapped service, see
+// Balance.Journal.
+type UnitOfWork struct {
+	controller.Balance
+
+	// Notification is used to persist the collected updates.
+	Notification controller.Notification
+
+	pending map[string]model.BalancesUpdate
+	m       sync.Mutex
+}
+
+// Deposit calls Deposit of the wrapped service and collects the balance update.
+func (u *UnitOfWork) Deposit(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	amount *model.Amount,
+) (bu model.BalanceUpdate, err error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return bu, ErrUnitOfWorkNoTransaction
+	}
+
+	if bu, err = u.Balance.Deposit(ctx, addr, acc, curr, amount); err != nil {
+		return bu, err
+	}
+
+	u.collect(tx.ID, bu)
+
+	return bu, nil
+}
+
+// Withdraw calls Withdraw of the wrapped service and collects the balance update.
+func (u *UnitOfWork) Withdraw(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	amount *model.Amount,
+) (bu model.BalanceUpdate, err error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return bu, ErrUnitOfWorkNoTransaction
+	}
+
+	if bu, err = u.Balance.Withdraw(ctx, addr, acc, curr, amount); err != nil {
+		return bu, err
+	}
+
+	u.collect(tx.ID, bu)
+
+	return bu, nil
+}
+
+// Transfer calls Transfer of the wrapped service and collects the balance updates.
+func (u *UnitOfWork) Transfer(
+	ctx context.Context,
+	addrFrom, addrTo model.Address,
+	acc model.Account,
+	curr model.Currency,
+	amount *model.Amount,
+) (bu [2]model.BalanceUpdate, err error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return bu, ErrUnitOfWorkNoTransaction
+	}
+
+	if bu, err = u.Balance.Transfer(ctx, addrFrom, addrTo, acc, curr, amount); err != nil {
+		return bu, err
+	}
+
+	u.collect(tx.ID, bu[:]...)
+
+	return bu, nil
+}
+
+// InternalTransfer calls InternalTransfer of the wrapped service and collects the
+// balance updates.
+func (u *UnitOfWork) InternalTransfer(
+	ctx context.Context,
+	addr model.Address,
+	accFrom, accTo model.Account,
+	curr model.Currency,
+	amount *model.Amount,
+) (bu [2]model.BalanceUpdate, err error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return bu, ErrUnitOfWorkNoTransaction
+	}
+
+	if bu, err = u.Balance.InternalTransfer(ctx, addr, accFrom, accTo, curr, amount); err != nil {
+		return bu, err
+	}
+
+	u.collect(tx.ID, bu[:]...)
+
+	return bu, nil
+}
+
+// Capture calls Capture of the wrapped service and collects the balance updates.
+func (u *UnitOfWork) Capture(
+	ctx context.Context,
+	merchant model.Address,
+	id string,
+	amount *model.Amount,
+) (bu [2]model.BalanceUpdate, err error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return bu, ErrUnitOfWorkNoTransaction
+	}
+
+	if bu, err = u.Balance.Capture(ctx, merchant, id, amount); err != nil {
+		return bu, err
+	}
+
+	u.collect(tx.ID, bu[:]...)
+
+	return bu, nil
+}
+
+// Distribute calls Distribute of the wrapped service and collects the balance updates.
+func (u *UnitOfWork) Distribute(
+	ctx context.Context,
+	id string,
+	source model.Address,
+	acc model.Account,
+	curr, ref model.Currency,
+	amount *model.Amount,
+	limit int,
+) (*model.Distribution, model.BalancesUpdate, error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return nil, nil, ErrUnitOfWorkNoTransaction
+	}
+
+	dist, bu, err := u.Balance.Distribute(ctx, id, source, acc, curr, ref, amount, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u.collect(tx.ID, bu...)
+
+	return dist, bu, nil
+}
+
+// ContinueDistribution calls ContinueDistribution of the wrapped service and collects
+// the balance updates.
+func (u *UnitOfWork) ContinueDistribution(
+	ctx context.Context,
+	id string,
+	limit int,
+) (*model.Distribution, model.BalancesUpdate, error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return nil, nil, ErrUnitOfWorkNoTransaction
+	}
+
+	dist, bu, err := u.Balance.ContinueDistribution(ctx, id, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	u.collect(tx.ID, bu...)
+
+	return dist, bu, nil
+}
+
+// Commit persists the balance updates collected within the transaction as a single
+// notification and forgets them, whether it succeeds or not. If no balances were
+// updated, nothing is stored. If the record of the transaction is already stored,
+// ErrUnitOfWorkCommitted is returned; the record stored by the transaction is found
+// only if the storage returns the writes of the transaction, e.g. through
+// cache.KeyValueDB.
+func (u *UnitOfWork) Commit(ctx context.Context) error {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return ErrUnitOfWorkNoTransaction
+	}
+
+	updates := u.take(tx.ID)
+	if len(updates) == 0 {
+		return nil
+	}
+
+	existing, err := u.Notification.Fetch(ctx, model.NotificationTypeBalancesUpdate, tx.ID)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil {
+		return fmt.Errorf("%w: %s", ErrUnitOfWorkCommitted, tx.ID)
+	}
+
+	return u.Notification.NotifyBalancesUpdate(ctx, model.Notification[model.BalancesUpdate]{
+		ID:   tx.ID,
+		Type: model.NotificationTypeBalancesUpdate,
+		Body: updates,
+	})
+}
+
+// Discard forgets the balance updates collected within the transaction. It must be
+// called when the transaction fails before Commit, e.g. when one of its operations
+// returns an error, otherwise the updates of the operations done before are kept.
+func (u *UnitOfWork) Discard(ctx context.Context) {
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		u.take(tx.ID)
+	}
+}
+
+// Pending returns the balance updates collected within the transaction so far.
+func (u *UnitOfWork) Pending(ctx context.Context) model.BalancesUpdate {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	return append(model.BalancesUpdate(nil), u.pending[tx.ID]...)
+}
+
+func (u *UnitOfWork) collect(txID string, updates ...model.BalanceUpdate) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	if u.pending == nil {
+		u.pending = make(map[string]model.BalancesUpdate)
+	}
+
+	u.pending[txID] = append(u.pending[txID], updates...)
+}
+
+func (u *UnitOfWork) take(txID string) model.BalancesUpdate {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	updates := u.pending[txID]
+	delete(u.pending, txID)
+
+	return updates
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anoideaopen/token/model"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUnitOfWork_Commit(t *testing.T) {
+	now := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	tx1Ctx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1", Timestamp: now})
+	tx2Ctx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx2", Timestamp: now})
+
+	env := newEnvironment(t)
+	uow := &UnitOfWork{
+		Balance:      env.ctrlBalance,
+		Notification: env.ctrlNotification,
+	}
+
+	deposit := model.BalanceUpdate{
+		Address:    user1.address,
+		Account:    user1.account1.account,
+		Currency:   user1.account1.currency,
+		OldValue:   amount(100),
+		NewValue:   amount(200),
+		ValueDelta: amount(100),
+		Direction:  model.DirectionCredit,
+		Operation:  model.OperationDeposit,
+		TxID:       "tx1",
+		Timestamp:  now,
+	}
+	transfer := [2]model.BalanceUpdate{
+		{
+			Address:    user1.address,
+			Account:    user1.account1.account,
+			Currency:   user1.account1.currency,
+			OldValue:   amount(200),
+			NewValue:   amount(150),
+			ValueDelta: amount(50),
+			Direction:  model.DirectionDebit,
+			Operation:  model.OperationTransfer,
+			TxID:       "tx1",
+			Timestamp:  now,
+		},
+		{
+			Address:    user2.address,
+			Account:    user2.account1.account,
+			Currency:   user2.account1.currency,
+			OldValue:   amount(300),
+			NewValue:   amount(350),
+			ValueDelta: amount(50),
+			Direction:  model.DirectionCredit,
+			Operation:  model.OperationTransfer,
+			TxID:       "tx1",
+			Timestamp:  now,
+		},
+	}
+
+	gomock.InOrder(
+		env.ctrlBalance.EXPECT().Deposit(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(100)).
+			Return(deposit, nil),
+		env.ctrlBalance.EXPECT().Transfer(tx1Ctx, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(50)).
+			Return(transfer, nil),
+		env.ctrlNotification.EXPECT().Fetch(tx1Ctx, model.NotificationTypeBalancesUpdate, "tx1").Return(nil, nil),
+		env.ctrlNotification.EXPECT().NotifyBalancesUpdate(tx1Ctx, model.Notification[model.BalancesUpdate]{
+			ID:   "tx1",
+			Type: model.NotificationTypeBalancesUpdate,
+			Body: model.BalancesUpdate{deposit, transfer[0], transfer[1]},
+		}).Return(nil),
+	)
+
+	_, err := uow.Deposit(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+	env.assert.NoError(err)
+
+	_, err = uow.Transfer(tx1Ctx, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(50))
+	env.assert.NoError(err)
+
+	// updates of other transactions are kept apart
+	env.assert.Empty(uow.Pending(tx2Ctx))
+	env.assert.NoError(uow.Commit(tx2Ctx))
+
+	env.assert.Len(uow.Pending(tx1Ctx), 3)
+	env.assert.NoError(uow.Commit(tx1Ctx))
+
+	// the second commit has nothing to store
+	env.assert.NoError(uow.Commit(tx1Ctx))
+
+	// the updates made after the commit are not merged into the stored record
+	env.ctrlBalance.EXPECT().Deposit(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(100)).
+		Return(deposit, nil)
+	env.ctrlNotification.EXPECT().Fetch(tx1Ctx, model.NotificationTypeBalancesUpdate, "tx1").
+		Return(&model.RawNotification{ID: "tx1", Type: model.NotificationTypeBalancesUpdate}, nil)
+
+	_, err = uow.Deposit(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+	env.assert.NoError(err)
+	env.assert.ErrorIs(uow.Commit(tx1Ctx), ErrUnitOfWorkCommitted)
+	env.assert.Empty(uow.Pending(tx1Ctx))
+
+	// the updates of a failed transaction are discarded
+	env.ctrlBalance.EXPECT().Deposit(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(100)).
+		Return(deposit, nil)
+	env.ctrlBalance.EXPECT().Withdraw(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(500)).
+		Return(model.BalanceUpdate{}, ErrBalanceInsufficientFunds)
+
+	_, err = uow.Deposit(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+	env.assert.NoError(err)
+
+	_, err = uow.Withdraw(tx1Ctx, user1.address, user1.account1.account, user1.account1.currency, amount(500))
+	env.assert.ErrorIs(err, ErrBalanceInsufficientFunds)
+	env.assert.Len(uow.Pending(tx1Ctx), 1)
+
+	uow.Discard(tx1Ctx)
+	env.assert.Empty(uow.Pending(tx1Ctx))
+
+	_, err = uow.Deposit(ctx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+	env.assert.ErrorIs(err, ErrUnitOfWorkNoTransaction)
+}
+
+func TestUnitOfWork_CommitError(t *testing.T) {
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1"})
+
+	env := newEnvironment(t)
+	uow := &UnitOfWork{
+		Balance:      env.ctrlBalance,
+		Notification: env.ctrlNotification,
+	}
+
+	deposit := model.BalanceUpdate{
+		Address:    user1.address,
+		Account:    user1.account1.account,
+		Currency:   user1.account1.currency,
+		OldValue:   amount(100),
+		NewValue:   amount(200),
+		ValueDelta: amount(100),
+	}
+
+	env.ctrlBalance.EXPECT().Deposit(txCtx, user1.address, user1.account1.account, user1.account1.currency, amount(100)).
+		Return(deposit, nil).Times(2)
+
+	// the updates are forgotten if the record cannot be looked up
+	gomock.InOrder(
+		env.ctrlNotification.EXPECT().Fetch(txCtx, model.NotificationTypeBalancesUpdate, "tx1").
+			Return(nil, ErrNotificationDatabase),
+		env.ctrlNotification.EXPECT().Fetch(txCtx, model.NotificationTypeBalancesUpdate, "tx1").Return(nil, nil),
+		env.ctrlNotification.EXPECT().NotifyBalancesUpdate(txCtx, gomock.Any()).Return(ErrNotificationDatabase),
+	)
+
+	_, err := uow.Deposit(txCtx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+	env.assert.NoError(err)
+	env.assert.ErrorIs(uow.Commit(txCtx), ErrNotificationDatabase)
+	env.assert.Empty(uow.Pending(txCtx))
+
+	// or stored
+	_, err = uow.Deposit(txCtx, user1.address, user1.account1.account, user1.account1.currency, amount(100))
+	env.assert.NoError(err)
+	env.assert.ErrorIs(uow.Commit(txCtx), ErrNotificationDatabase)
+	env.assert.Empty(uow.Pending(txCtx))
+}
+
+func TestUnitOfWork_ForcedTransfer(t *testing.T) {
+	txCtx := model.ContextWithTransaction(ctx, model.Transaction{ID: "tx1"})
+
+	env := newEnvironment(t)
+	uow := &UnitOfWork{
+		Balance:      env.ctrlBalance,
+		Notification: env.ctrlNotification,
+	}
+
+	order := model.ForcedTransferOrder{ID: "order1"}
+	updates := [2]model.BalanceUpdate{
+		{Address: user1.address, ValueDelta: amount(50), Direction: model.DirectionDebit},
+		{Address: user2.address, ValueDelta: amount(50), Direction: model.DirectionCredit},
+	}
+
+	env.ctrlBalance.EXPECT().
+		ForcedTransfer(txCtx, order, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(50)).
+		Return(updates, nil)
+
+	_, err := uow.ForcedTransfer(txCtx, order, user1.address, user2.address, user1.account1.account, user1.account1.currency, amount(50))
+	env.assert.NoError(err)
+
+	// the forced transfer stores its own notification, so nothing is committed
+	env.assert.Empty(uow.Pending(txCtx))
+	env.assert.NoError(uow.Commit(txCtx))
+}
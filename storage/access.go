@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrAccessDatabase represents a generic error related to the database operations.
+var ErrAccessDatabase = errors.New("access database error")
+
+// Key prefixes of the access records.
+const (
+	accessRolePrefix   = "role"
+	accessFrozenPrefix = "frozen"
+)
+
+// accessFlag is stored as a value of the access records. Any value stored under
+// the key means that the flag is set.
+var accessFlag = keyvalue.Value{1}
+
+// Access is a structure which encapsulates the keyvalue.DB to interact with
+// roles and freezes of addresses in database.
+//
+//go:generate ifacemaker -f access.go -o repository/access.go -i Access -s Access -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/access.go -destination repository/mock/mock_access.go
+type Access struct {
+	keyvalue.DB
+}
+
+// HasRole checks whether the role is granted to the address.
+func (a *Access) HasRole(ctx context.Context, addr model.Address, role model.Role) (bool, error) {
+	return a.isSet(ctx, keyvalue.Join(accessRolePrefix, string(role), string(addr)))
+}
+
+// GrantRole grants the role to the address.
+func (a *Access) GrantRole(ctx context.Context, addr model.Address, role model.Role) error {
+	return a.set(ctx, keyvalue.Join(accessRolePrefix, string(role), string(addr)))
+}
+
+// RevokeRole revokes the role from the address.
+func (a *Access) RevokeRole(ctx context.Context, addr model.Address, role model.Role) error {
+	return a.del(ctx, keyvalue.Join(accessRolePrefix, string(role), string(addr)))
+}
+
+// IsFrozen checks whether the address is frozen.
+func (a *Access) IsFrozen(ctx context.Context, addr model.Address) (bool, error) {
+	return a.isSet(ctx, keyvalue.Join(accessFrozenPrefix, string(addr)))
+}
+
+// Freeze marks the address as frozen.
+func (a *Access) Freeze(ctx context.Context, addr model.Address) error {
+	return a.set(ctx, keyvalue.Join(accessFrozenPrefix, string(addr)))
+}
+
+// Unfreeze removes the frozen mark from the address.
+func (a *Access) Unfreeze(ctx context.Context, addr model.Address) error {
+	return a.del(ctx, keyvalue.Join(accessFrozenPrefix, string(addr)))
+}
+
+func (a *Access) isSet(ctx context.Context, key string) (bool, error) {
+	raw, err := a.DB.Get(ctx, keyvalue.Key(key))
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("%w: %s", ErrAccessDatabase, err.Error())
+	}
+
+	return len(raw) > 0, nil
+}
+
+func (a *Access) set(ctx context.Context, key string) error {
+	if err := a.DB.Set(ctx, keyvalue.Key(key), accessFlag); err != nil {
+		return fmt.Errorf("%w: %s", ErrAccessDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (a *Access) del(ctx context.Context, key string) error {
+	if err := a.DB.Del(ctx, keyvalue.Key(key)); err != nil {
+		return fmt.Errorf("%w: %s", ErrAccessDatabase, err.Error())
+	}
+
+	return nil
+}
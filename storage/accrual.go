@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrAccrualDatabase represents a generic error related to the database operations.
+var ErrAccrualDatabase = errors.New("accrual database error")
+
+// Keys of the accrual rates.
+const (
+	accrualPrefix      = "accrual"
+	accrualIndexPrefix = "accrualindex"
+
+	// accrualSeqLayout formats sequence numbers in the rate keys, so that their lexical
+	// order matches the numeric one.
+	accrualSeqLayout = "%020d"
+)
+
+// Accrual is a structure which encapsulates the keyvalue.DB to interact with the
+// accrual rates of currencies in database. The rates of every currency form an
+// append-only history, so the balances are adjusted at the rates in force when the
+// time passed, and the index of the currency, the sequence number of its last rate.
+//
+//go:generate ifacemaker -f accrual.go -o repository/accrual.go -i Accrual -s Accrual -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/accrual.go -destination repository/mock/mock_accrual.go
+type Accrual struct {
+	Object
+}
+
+// LoadRate retrieves the last accrual rate of the Currency. If no rate is configured,
+// nil is returned.
+func (a *Accrual) LoadRate(ctx context.Context, curr model.Currency) (*model.AccrualRate, error) {
+	index, err := a.index(ctx, curr)
+	if err != nil || index == 0 {
+		return nil, err
+	}
+
+	rate := new(model.AccrualRate)
+	if err := a.Object.Load(ctx, model.ObjectQuery(a.key(curr, index)), rate); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+	}
+
+	return rate, nil
+}
+
+// AppendRate stores the accrual rate of the Currency with the next sequence number and
+// makes it the last one.
+func (a *Accrual) AppendRate(ctx context.Context, curr model.Currency, rate *model.AccrualRate) error {
+	index, err := a.index(ctx, curr)
+	if err != nil {
+		return err
+	}
+
+	if err := a.Object.Save(ctx, model.ObjectQuery(a.key(curr, index+1)), rate); err != nil {
+		return fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+	}
+
+	err = a.Object.DB.Set(
+		ctx,
+		keyvalue.Key(keyvalue.Join(accrualIndexPrefix, string(curr))),
+		keyvalue.Value(strconv.FormatUint(index+1, 10)), //nolint:gomnd
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Rates retrieves the history of the accrual rates of the Currency in the order they
+// were appended.
+func (a *Accrual) Rates(ctx context.Context, curr model.Currency) ([]*model.AccrualRate, error) {
+	index, err := a.index(ctx, curr)
+	if err != nil || index == 0 {
+		return nil, err
+	}
+
+	prefix := keyvalue.Join(accrualPrefix, string(curr))
+
+	iter, err := a.Object.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var out []*model.AccrualRate
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+		}
+
+		// skip the rates of other currencies sharing the same beginning
+		if _, ok := strings.CutPrefix(string(k), prefix+keyvalue.KeySeparator); !ok {
+			continue
+		}
+
+		rate := new(model.AccrualRate)
+		if err := rate.UnmarshalBinary(v); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+		}
+
+		out = append(out, rate)
+	}
+
+	return out, nil
+}
+
+// index retrieves the sequence number of the last rate of the currency, zero if there
+// are no rates.
+func (a *Accrual) index(ctx context.Context, curr model.Currency) (uint64, error) {
+	raw, err := a.Object.DB.Get(ctx, keyvalue.Key(keyvalue.Join(accrualIndexPrefix, string(curr))))
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+	}
+
+	index, err := strconv.ParseUint(string(raw), 10, 64) //nolint:gomnd
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrAccrualDatabase, err.Error())
+	}
+
+	return index, nil
+}
+
+// key creates a key of the rate of the currency.
+// example: "accrual/currency/00000000000000000001"
+func (a *Accrual) key(curr model.Currency, seq uint64) string {
+	return keyvalue.Join(accrualPrefix, string(curr), fmt.Sprintf(accrualSeqLayout, seq))
+}
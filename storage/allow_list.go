@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrAllowListDatabase represents a generic error related to the database operations.
+var ErrAllowListDatabase = errors.New("allow-list database error")
+
+// allowListPrefix is a key prefix of the allow-list records.
+const allowListPrefix = "allowlist"
+
+// AllowList is a structure which encapsulates the keyvalue.DB to interact with
+// the registry of verified addresses in database.
+//
+//go:generate ifacemaker -f allow_list.go -o repository/allow_list.go -i AllowList -s AllowList -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/allow_list.go -destination repository/mock/mock_allow_list.go
+type AllowList struct {
+	Object
+}
+
+// Load retrieves the allow-list entry of the address. If the address is not
+// allow-listed, nil is returned.
+func (al *AllowList) Load(ctx context.Context, addr model.Address) (*model.AllowListEntry, error) {
+	entry := new(model.AllowListEntry)
+	if err := al.Object.Load(ctx, al.query(addr), entry); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrAllowListDatabase, err.Error())
+	}
+
+	return entry, nil
+}
+
+// Save stores the allow-list entry, replacing the previous one of the address.
+func (al *AllowList) Save(ctx context.Context, entry *model.AllowListEntry) error {
+	if err := al.Object.Save(ctx, al.query(entry.Address), entry); err != nil {
+		return fmt.Errorf("%w: %s", ErrAllowListDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Delete removes the address from the allow-list.
+func (al *AllowList) Delete(ctx context.Context, addr model.Address) error {
+	if err := al.Object.Delete(ctx, al.query(addr)); err != nil {
+		return fmt.Errorf("%w: %s", ErrAllowListDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (al *AllowList) query(addr model.Address) model.ObjectQuery {
+	return model.ObjectQuery(keyvalue.Join(allowListPrefix, string(addr)))
+}
@@ -1,12 +1,16 @@
 package storage
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/anoideaopen/token/keyvalue"
 	"github.com/anoideaopen/token/model"
@@ -18,10 +22,33 @@ var ErrBalanceDatabase = errors.New("balance database error")
 // Balance is a structure which encapsulates the keyvalue.DB to interact with
 // balances in database.
 //
+// In the accumulator mode credits are written as unique delta records under the
+// balance key instead of read-modify-write of the balance itself, so concurrent
+// credits of a hot account do not conflict. Load sums the balance and its deltas,
+// while Save and Compact merge the deltas back into the balance.
+//
+// With the snapshots set, the value of a balance is captured before its first change
+// after a snapshot, so LoadAt can retrieve it later.
+//
 //go:generate ifacemaker -f balance.go -o repository/balance.go -i Balance -s Balance -p repository -y "Repository describes methods, implemented by the storage package."
 //go:generate mockgen -package mock -source repository/balance.go -destination repository/mock/mock_balance.go
 type Balance struct {
 	keyvalue.DB
+
+	// Accumulator enables the accumulator mode.
+	Accumulator bool
+
+	// Snapshots is an optional storage of balance snapshots.
+	Snapshots *Snapshot
+
+	// Registry is an optional registry of the holders updated by Save and Credit.
+	Registry *Holder
+
+	// Actions is an optional storage of corporate actions. The index of the actions
+	// applied to a balance is stored with it, and the actions performed since then
+	// are applied when the balance is read. The Snapshots and the Registry must be set up with the
+	// same storage of the actions, so their values are adjusted as well.
+	Actions *CorporateAction
 }
 
 // Load retrieves the balance from the database for given BalanceType, Address, and Currency.
@@ -32,26 +59,50 @@ func (b *Balance) Load(
 	acc model.Account,
 	curr model.Currency,
 ) (*big.Int, error) {
+	val, _, err := b.LoadTouched(ctx, addr, acc, curr)
+	return val, err
+}
+
+// LoadTouched retrieves the balance like Load together with the timestamp of the
+// transaction which saved it last. The timestamp is zero if it is not stored.
+func (b *Balance) LoadTouched(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, time.Time, error) {
 	raw, err := b.DB.Get(
 		ctx,
 		keyvalue.Key(b.join(acc, addr, curr)),
 	)
+	if err != nil && !errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, time.Time{}, fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+	}
+
+	val, touched, err := b.decode(ctx, curr, raw)
 	if err != nil {
-		if errors.Is(err, keyvalue.ErrNotFound) {
-			return new(big.Int), nil
-		}
+		return nil, time.Time{}, err
+	}
 
-		return nil, fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+	if !b.Accumulator {
+		return val, touched, nil
 	}
 
-	if len(raw) == 0 {
-		return new(big.Int), nil
+	deltas, err := b.loadDeltas(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, time.Time{}, err
 	}
 
-	return new(big.Int).SetBytes(raw), nil
+	for _, d := range deltas {
+		val.Add(val, d)
+	}
+
+	return val, touched, nil
 }
 
 // Save saves the balance to the database for given BalanceType, Address, and Currency.
+// The value is stored in the signed encoding, so negative balances are preserved. If
+// the context carries the timestamp of the transaction, it is stored with the value.
 func (b *Balance) Save(
 	ctx context.Context,
 	addr model.Address,
@@ -59,17 +110,153 @@ func (b *Balance) Save(
 	curr model.Currency,
 	val *big.Int,
 ) error {
+	if err := b.capture(ctx, addr, acc, curr); err != nil {
+		return err
+	}
+
+	if b.Accumulator {
+		if err := b.dropDeltas(ctx, addr, acc, curr); err != nil {
+			return err
+		}
+	}
+
+	raw, err := b.encode(ctx, curr, val)
+	if err != nil {
+		return err
+	}
+
 	if err := b.DB.Set(
 		ctx,
 		keyvalue.Key(b.join(acc, addr, curr)),
-		keyvalue.Value(val.Bytes()),
+		raw,
 	); err != nil {
 		return fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
 	}
 
+	if b.Registry != nil {
+		return b.Registry.Save(ctx, addr, acc, curr, val)
+	}
+
 	return nil
 }
 
+// Credit increases the balance for given BalanceType, Address, and Currency. In the
+// accumulator mode it writes a delta record unique for the transaction without
+// reading the balance, otherwise the balance is loaded and saved.
+func (b *Balance) Credit(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	val *big.Int,
+) error {
+	if !b.Accumulator {
+		before, err := b.Load(ctx, addr, acc, curr)
+		if err != nil {
+			return err
+		}
+
+		return b.Save(ctx, addr, acc, curr, new(big.Int).Add(before, val))
+	}
+
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return fmt.Errorf("%w: transaction information is missing", ErrBalanceDatabase)
+	}
+
+	// the registry keeps the credit like the balance does, unless it changes the
+	// number of the holders, then the balance is saved
+	if b.Registry != nil {
+		kept, err := b.Registry.Credit(ctx, addr, acc, curr, val)
+		if err != nil {
+			return err
+		}
+
+		if !kept {
+			before, err := b.Load(ctx, addr, acc, curr)
+			if err != nil {
+				return err
+			}
+
+			return b.Save(ctx, addr, acc, curr, new(big.Int).Add(before, val))
+		}
+	}
+
+	if err := b.capture(ctx, addr, acc, curr); err != nil {
+		return err
+	}
+
+	key := keyvalue.Key(keyvalue.Join(b.join(acc, addr, curr), tx.ID))
+
+	// several credits of the same balance in one transaction share the record
+	prev, err := b.DB.Get(ctx, key)
+	if err != nil && !errors.Is(err, keyvalue.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+	}
+
+	delta, _, err := b.decode(ctx, curr, prev)
+	if err != nil {
+		return err
+	}
+
+	raw, err := b.encode(ctx, curr, delta.Add(delta, val))
+	if err != nil {
+		return err
+	}
+
+	if err := b.DB.Set(ctx, key, raw); err != nil {
+		return fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Compact merges the delta records of the balance for given BalanceType, Address,
+// and Currency into the balance itself. It does nothing outside the accumulator mode.
+func (b *Balance) Compact(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) error {
+	if !b.Accumulator {
+		return nil
+	}
+
+	val, err := b.Load(ctx, addr, acc, curr)
+	if err != nil {
+		return err
+	}
+
+	return b.Save(ctx, addr, acc, curr, val)
+}
+
+// Accumulates reports whether the accumulator mode is enabled, so Credit does not
+// read the balance.
+func (b *Balance) Accumulates() bool {
+	return b.Accumulator
+}
+
+// LoadAt retrieves the balance for given BalanceType, Address, and Currency as of the
+// snapshot with given sequence number. Without the snapshots the current balance is
+// returned.
+func (b *Balance) LoadAt(
+	ctx context.Context,
+	seq uint64,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, error) {
+	if b.Snapshots != nil {
+		val, err := b.Snapshots.ValueAt(ctx, seq, addr, acc, curr)
+		if err != nil || val != nil {
+			return val, err
+		}
+	}
+
+	return b.Load(ctx, addr, acc, curr)
+}
+
 // List retrieves all balances from the database for given BalanceType and Address,
 // returning them as a map where the key is the currency.
 func (b *Balance) List(
@@ -92,7 +279,7 @@ func (b *Balance) List(
 		}
 
 		keys := strings.Split(string(k), keyvalue.KeySeparator)
-		if len(keys) != 3 { //nolint:gomnd
+		if len(keys) != 3 && (!b.Accumulator || len(keys) != 4) { //nolint:gomnd
 			return nil, fmt.Errorf(
 				"%w: invalid iterator's key '%s'",
 				ErrBalanceDatabase,
@@ -100,17 +287,265 @@ func (b *Balance) List(
 			)
 		}
 
-		b := new(big.Int)
-		if len(v) > 0 {
-			b.SetBytes(v)
+		// skip the balances of other addresses sharing the prefix
+		if keys[1] != string(addr) {
+			continue
+		}
+
+		val, _, err := b.decode(ctx, model.Currency(keys[2]), v)
+		if err != nil {
+			return nil, err
+		}
+
+		// deltas are summed up with the balance
+		if sum, ok := out[model.Currency(keys[2])]; ok {
+			val.Add(val, sum)
+		}
+
+		out[model.Currency(keys[2])] = val
+	}
+
+	return out, nil
+}
+
+// Portfolio retrieves all balances of the Address on every account declared in the
+// model account registry, returning them as a map where the keys are the account and
+// the currency. The balance keys start with the account, so every account is scanned
+// separately.
+func (b *Balance) Portfolio(
+	ctx context.Context,
+	addr model.Address,
+) (map[model.Account]map[model.Currency]*big.Int, error) {
+	out := make(map[model.Account]map[model.Currency]*big.Int)
+	for _, t := range model.Accounts() {
+		balances, err := b.List(ctx, addr, t.Account)
+		if err != nil {
+			return nil, err
 		}
 
-		out[model.Currency(keys[2])] = b
+		if len(balances) != 0 {
+			out[t.Account] = balances
+		}
 	}
 
 	return out, nil
 }
 
+// Holders retrieves the addresses having a balance record of given BalanceType and
+// Currency. The addresses up to and including the bookmark are skipped, and at most
+// limit addresses are returned unless the limit is zero. With the registry set, the
+// addresses are read from it bucket by bucket, see Holder.Addresses, so a batch does
+// not read the preceding ones. Otherwise all the balances of the BalanceType are
+// scanned and the addresses are returned in ascending order.
+func (b *Balance) Holders(
+	ctx context.Context,
+	acc model.Account,
+	curr model.Currency,
+	bookmark model.Address,
+	limit int,
+) ([]model.Address, error) {
+	if b.Registry != nil {
+		return b.Registry.Addresses(ctx, acc, curr, bookmark, limit)
+	}
+
+	// example: "4f"
+	iter, err := b.DB.Iter(ctx, keyvalue.Prefix(b.join(acc, "", "")))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var (
+		out   []model.Address
+		last  = bookmark
+		found = bookmark == ""
+	)
+
+	for iter.HasNext() && (limit == 0 || len(out) < limit) {
+		k, _, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+		}
+
+		// skip the balances of other accounts and currencies sharing the prefix, an
+		// address is listed once for the balance and its deltas
+		keys := strings.Split(string(k), keyvalue.KeySeparator)
+		if len(keys) < 3 || len(keys) > 4 || keys[0] != b.hex(acc) || keys[2] != string(curr) { //nolint:gomnd
+			continue
+		}
+
+		addr := model.Address(keys[1])
+		if !found {
+			found = addr == bookmark
+			continue
+		}
+
+		if addr == last {
+			continue
+		}
+
+		out = append(out, addr)
+		last = addr
+	}
+
+	return out, nil
+}
+
+// Register adds the balances of the addresses for given BalanceType and Currency to
+// the registry, which must be set. It is used to backfill the registry with the
+// balances saved before it was set, the addresses are listed by the caller, e.g. with
+// Holders of a Balance without the registry in a read-only query, and registered in
+// batches. Registering an address again does not change the registry.
+func (b *Balance) Register(
+	ctx context.Context,
+	addrs []model.Address,
+	acc model.Account,
+	curr model.Currency,
+) error {
+	if b.Registry == nil {
+		return fmt.Errorf("%w: holder registry is not configured", ErrBalanceDatabase)
+	}
+
+	for _, addr := range addrs {
+		val, err := b.Load(ctx, addr, acc, curr)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Registry.Save(ctx, addr, acc, curr, val); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Supply retrieves the total of the positive balances of given BalanceType and
+// Currency maintained by the registry, which must be set.
+func (b *Balance) Supply(ctx context.Context, acc model.Account, curr model.Currency) (*big.Int, error) {
+	if b.Registry == nil {
+		return nil, fmt.Errorf("%w: holder registry is not configured", ErrBalanceDatabase)
+	}
+
+	return b.Registry.Supply(ctx, acc, curr)
+}
+
+// loadDeltas returns the delta records of the balance keyed by their database keys.
+func (b *Balance) loadDeltas(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (map[keyvalue.Key]*big.Int, error) {
+	iter, err := b.DB.Iter(ctx, keyvalue.Prefix(b.join(acc, addr, curr)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	out := make(map[keyvalue.Key]*big.Int)
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+		}
+
+		// skip the balance itself and the balances of other currencies sharing the prefix
+		keys := strings.Split(string(k), keyvalue.KeySeparator)
+		if len(keys) != 4 || keys[1] != string(addr) || keys[2] != string(curr) { //nolint:gomnd
+			continue
+		}
+
+		delta, _, err := b.decode(ctx, curr, v)
+		if err != nil {
+			return nil, err
+		}
+
+		out[k] = delta
+	}
+
+	return out, nil
+}
+
+// dropDeltas deletes the delta records of the balance.
+func (b *Balance) dropDeltas(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) error {
+	deltas, err := b.loadDeltas(ctx, addr, acc, curr)
+	if err != nil {
+		return err
+	}
+
+	for k := range deltas {
+		if err := b.DB.Del(ctx, k); err != nil {
+			return fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// encode encodes the balance record. The timestamp of the transaction and the index
+// of the corporate actions of the currency are stored with the value if they are known.
+func (b *Balance) encode(ctx context.Context, curr model.Currency, val *big.Int) (keyvalue.Value, error) {
+	tx, _ := model.TransactionFromContext(ctx)
+
+	if b.Actions != nil {
+		index, err := b.Actions.Index(ctx, curr)
+		if err != nil {
+			return nil, err
+		}
+
+		if index != 0 {
+			return encodeIndexedBalance(val, tx.Timestamp, index), nil
+		}
+	}
+
+	if !tx.Timestamp.IsZero() {
+		return encodeTouchedBalance(val, tx.Timestamp), nil
+	}
+
+	return encodeBalance(val), nil
+}
+
+// decode decodes the balance record and applies the corporate actions of the currency
+// performed since the record was saved.
+func (b *Balance) decode(ctx context.Context, curr model.Currency, raw keyvalue.Value) (*big.Int, time.Time, error) {
+	val, touched, index, err := decodeIndexedBalance(raw)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("%w: %s", ErrBalanceDatabase, err.Error())
+	}
+
+	if b.Actions == nil {
+		return val, touched, nil
+	}
+
+	if val, err = b.Actions.adjust(ctx, curr, val, index); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return val, touched, nil
+}
+
+// capture keeps the value of the balance before its first change after a snapshot.
+func (b *Balance) capture(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) error {
+	if b.Snapshots == nil {
+		return nil
+	}
+
+	return b.Snapshots.capture(ctx, addr, acc, curr, func() (*big.Int, error) {
+		return b.Load(ctx, addr, acc, curr)
+	})
+}
+
 // join creates a unique key for the database record based on the BalanceType,
 // Address, and Currency.
 // example: "4f/address/currency" or "4f/address"
@@ -127,5 +562,21 @@ func (b *Balance) join(
 }
 
 func (b *Balance) hex(acc model.Account) string {
-	return hex.EncodeToString([]byte{byte(acc)})
+	return encodeAccount(acc)
+}
+
+// encodeAccount encodes the account as a part of the key. Accounts fitting into one
+// byte keep the legacy two-character encoding, larger ones are encoded as minimal
+// big-endian bytes, so their keys are at least four characters long and never clash
+// with the legacy ones.
+// example: 43 -> "2b", 300 -> "012c"
+func encodeAccount(acc model.Account) string {
+	if acc >= 0 && acc <= math.MaxUint8 {
+		return hex.EncodeToString([]byte{byte(acc)})
+	}
+
+	raw := make([]byte, 8) //nolint:gomnd
+	binary.BigEndian.PutUint64(raw, uint64(acc))
+
+	return hex.EncodeToString(bytes.TrimLeft(raw, "\x00"))
 }
@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anoideaopen/token/keyvalue"
+)
+
+// ErrBalanceEncoding is returned when a stored balance can not be decoded.
+var ErrBalanceEncoding = errors.New("balance encoding error")
+
+// Balance encoding versions. The legacy encoding is the big-endian magnitude of a
+// non-negative value as returned by big.Int.Bytes, so it never starts with a zero
+// byte. Versioned encodings start with balanceEncodingMarker followed by the version.
+const (
+	balanceEncodingMarker  byte = 0x00
+	balanceEncodingSigned  byte = 0x01
+	balanceEncodingTouched byte = 0x02
+	balanceEncodingIndexed byte = 0x03
+)
+
+// Sizes of the timestamp of the touched balance encoding and of the index of the
+// corporate actions of the indexed one.
+const (
+	balanceTouchedSize = 8
+	balanceIndexSize   = 8
+)
+
+// Sign bytes of the signed balance encoding.
+const (
+	balanceSignPositive byte = 0x00
+	balanceSignNegative byte = 0x01
+)
+
+// encodeBalance encodes the value in the signed encoding:
+// [marker, version, sign, magnitude...].
+func encodeBalance(val *big.Int) keyvalue.Value {
+	sign := balanceSignPositive
+	if val.Sign() < 0 {
+		sign = balanceSignNegative
+	}
+
+	magnitude := new(big.Int).Abs(val).Bytes()
+
+	out := make(keyvalue.Value, 0, 3+len(magnitude)) //nolint:gomnd
+	out = append(out, balanceEncodingMarker, balanceEncodingSigned, sign)
+
+	return append(out, magnitude...)
+}
+
+// encodeTouchedBalance encodes the value together with the moment it was last
+// touched: [marker, version, sign, unix nanoseconds (8 bytes), magnitude...].
+func encodeTouchedBalance(val *big.Int, touched time.Time) keyvalue.Value {
+	signed := encodeBalance(val)
+
+	out := make(keyvalue.Value, 0, len(signed)+balanceTouchedSize)
+	out = append(out, balanceEncodingMarker, balanceEncodingTouched, signed[2])
+	out = binary.BigEndian.AppendUint64(out, uint64(touched.UnixNano()))
+
+	return append(out, signed[3:]...)
+}
+
+// encodeIndexedBalance encodes the value together with the moment it was last touched
+// and the index of the last corporate action applied to it:
+// [marker, version, sign, unix nanoseconds (8 bytes), index (8 bytes), magnitude...].
+func encodeIndexedBalance(val *big.Int, touched time.Time, index uint64) keyvalue.Value {
+	signed := encodeBalance(val)
+
+	var nanos int64
+	if !touched.IsZero() {
+		nanos = touched.UnixNano()
+	}
+
+	out := make(keyvalue.Value, 0, len(signed)+balanceTouchedSize+balanceIndexSize)
+	out = append(out, balanceEncodingMarker, balanceEncodingIndexed, signed[2])
+	out = binary.BigEndian.AppendUint64(out, uint64(nanos))
+	out = binary.BigEndian.AppendUint64(out, index)
+
+	return append(out, signed[3:]...)
+}
+
+// decodeBalance decodes the value stored in any of the encodings. An empty value is
+// decoded as zero.
+func decodeBalance(raw keyvalue.Value) (*big.Int, error) {
+	val, _, _, err := decodeIndexedBalance(raw)
+	return val, err
+}
+
+// decodeTouchedBalance decodes the value and the moment it was last touched. The
+// moment is zero for the encodings which do not store it.
+func decodeTouchedBalance(raw keyvalue.Value) (*big.Int, time.Time, error) {
+	val, touched, _, err := decodeIndexedBalance(raw)
+	return val, touched, err
+}
+
+// decodeIndexedBalance decodes the value, the moment it was last touched and the
+// index of the last corporate action applied to it. The moment and the index are zero
+// for the encodings which do not store them.
+func decodeIndexedBalance(raw keyvalue.Value) (*big.Int, time.Time, uint64, error) {
+	if len(raw) == 0 || raw[0] != balanceEncodingMarker {
+		return new(big.Int).SetBytes(raw), time.Time{}, 0, nil
+	}
+
+	if len(raw) < 3 { //nolint:gomnd
+		return nil, time.Time{}, 0, fmt.Errorf("%w: value is too short", ErrBalanceEncoding)
+	}
+
+	var (
+		touched   time.Time
+		index     uint64
+		magnitude = raw[3:]
+	)
+
+	switch raw[1] {
+	case balanceEncodingSigned:
+	case balanceEncodingTouched, balanceEncodingIndexed:
+		size := balanceTouchedSize
+		if raw[1] == balanceEncodingIndexed {
+			size += balanceIndexSize
+		}
+
+		if len(magnitude) < size {
+			return nil, time.Time{}, 0, fmt.Errorf("%w: value is too short", ErrBalanceEncoding)
+		}
+
+		if nanos := int64(binary.BigEndian.Uint64(magnitude)); nanos != 0 {
+			touched = time.Unix(0, nanos).UTC()
+		}
+
+		if raw[1] == balanceEncodingIndexed {
+			index = binary.BigEndian.Uint64(magnitude[balanceTouchedSize:])
+		}
+
+		magnitude = magnitude[size:]
+	default:
+		return nil, time.Time{}, 0, fmt.Errorf("%w: unknown version %d", ErrBalanceEncoding, raw[1])
+	}
+
+	val := new(big.Int).SetBytes(magnitude)
+
+	switch raw[2] {
+	case balanceSignPositive:
+		return val, touched, index, nil
+	case balanceSignNegative:
+		return val.Neg(val), touched, index, nil
+	default:
+		return nil, time.Time{}, 0, fmt.Errorf("%w: unknown sign %d", ErrBalanceEncoding, raw[2])
+	}
+}
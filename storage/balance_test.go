@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/keyvalue/inmem"
 	"github.com/anoideaopen/token/keyvalue/mock"
 	"github.com/anoideaopen/token/model"
 	"github.com/stretchr/testify/assert"
@@ -55,7 +57,7 @@ func TestBalance_Save(t *testing.T) {
 	mockDB.EXPECT().Set(
 		gomock.Any(),
 		keyvalue.Key(b.join(tt, a, c)),
-		gomock.Any(),
+		keyvalue.Value{0x00, 0x01, 0x00, 100},
 	).Return(nil)
 
 	err := b.Save(context.Background(), a, tt, c, big.NewInt(100))
@@ -96,3 +98,112 @@ func TestBalance_List(t *testing.T) {
 		c: big.NewInt(100),
 	}, res)
 }
+
+func TestBalance_List_sharedPrefix(t *testing.T) {
+	b := &Balance{DB: new(inmem.KeyValueDB)}
+
+	tt := model.AccountToken
+
+	// the keys of "ab" start with the prefix of "a"
+	assert.NoError(t, b.Save(context.Background(), "a", tt, "ETH", big.NewInt(1)))
+	assert.NoError(t, b.Save(context.Background(), "ab", tt, "ETH", big.NewInt(2)))
+	assert.NoError(t, b.Save(context.Background(), "ab", tt, "BTC", big.NewInt(3)))
+
+	res, err := b.List(context.Background(), "a", tt)
+	assert.NoError(t, err)
+	assert.Equal(t, map[model.Currency]*big.Int{"ETH": big.NewInt(1)}, res)
+}
+
+func TestBalance_hex(t *testing.T) {
+	b := new(Balance)
+
+	assert.Equal(t, "2b", b.hex(model.AccountToken))
+	assert.Equal(t, "2f", b.hex(model.AccountAllowedLocked))
+	assert.Equal(t, "00", b.hex(0))
+	assert.Equal(t, "ff", b.hex(255))
+	assert.Equal(t, "0100", b.hex(256))
+	assert.Equal(t, "012c", b.hex(300))
+	assert.Equal(t, "01000000", b.hex(1<<24))
+}
+
+func TestBalance_encoding(t *testing.T) {
+	for _, v := range []*big.Int{
+		big.NewInt(0),
+		big.NewInt(100),
+		big.NewInt(-100),
+		new(big.Int).Lsh(big.NewInt(-1), 100),
+	} {
+		got, err := decodeBalance(encodeBalance(v))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, v.Cmp(got), v.String())
+	}
+
+	// legacy encoding
+	got, err := decodeBalance(big.NewInt(300).Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(300), got)
+
+	got, err = decodeBalance(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, got.Sign())
+
+	_, err = decodeBalance(keyvalue.Value{0x00, 0x03, 0x00, 0x01})
+	assert.ErrorIs(t, err, ErrBalanceEncoding)
+
+	// touched encoding
+	touched := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+
+	got, ts, err := decodeTouchedBalance(encodeTouchedBalance(big.NewInt(-300), touched))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(-300), got)
+	assert.Equal(t, touched, ts)
+
+	_, err = decodeBalance(keyvalue.Value{0x00, 0x02, 0x00, 0x01})
+	assert.ErrorIs(t, err, ErrBalanceEncoding)
+}
+
+func TestBalance_Accumulator(t *testing.T) {
+	b := &Balance{
+		DB:          new(inmem.KeyValueDB),
+		Accumulator: true,
+	}
+
+	var (
+		tt = model.AccountToken
+		a  = model.Address("0x123")
+		c  = model.Currency("ETH")
+	)
+
+	tx1 := model.ContextWithTransaction(context.Background(), model.Transaction{ID: "tx1"})
+	tx2 := model.ContextWithTransaction(context.Background(), model.Transaction{ID: "tx2"})
+
+	assert.NoError(t, b.Save(tx1, a, tt, c, big.NewInt(100)))
+	assert.NoError(t, b.Credit(tx1, a, tt, c, big.NewInt(10)))
+	assert.NoError(t, b.Credit(tx1, a, tt, c, big.NewInt(20)))
+	assert.NoError(t, b.Credit(tx2, a, tt, c, big.NewInt(5)))
+	assert.NoError(t, b.Credit(tx2, a, tt, "BTC", big.NewInt(7)))
+
+	res, err := b.Load(tx2, a, tt, c)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(135), res)
+
+	list, err := b.List(tx2, a, tt)
+	assert.NoError(t, err)
+	assert.Equal(t, map[model.Currency]*big.Int{
+		c:     big.NewInt(135),
+		"BTC": big.NewInt(7),
+	}, list)
+
+	assert.NoError(t, b.Compact(tx2, a, tt, c))
+
+	deltas, err := b.loadDeltas(tx2, a, tt, c)
+	assert.NoError(t, err)
+	assert.Empty(t, deltas)
+
+	res, err = b.Load(tx2, a, tt, c)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(135), res)
+
+	_, err = b.DB.Get(tx2, keyvalue.Key(keyvalue.Join(b.join(tt, a, "BTC"), "tx2")))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,209 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrCorporateActionDatabase represents a generic error related to the database operations.
+var ErrCorporateActionDatabase = errors.New("corporate action database error")
+
+// Keys of the corporate action records.
+const (
+	corporateActionPrefix      = "corpaction"
+	corporateActionIndexPrefix = "corpactionindex"
+	corporateActionIDPrefix    = "corpactionid"
+
+	// corporateActionSeqLayout formats sequence numbers in the action keys, so that
+	// their lexical order matches the numeric one.
+	corporateActionSeqLayout = "%020d"
+)
+
+// CorporateAction is a structure which encapsulates the keyvalue.DB to interact with
+// corporate actions in database. Besides the actions it stores the index of every
+// currency, the sequence number of its last action. storage.Balance keeps the index
+// with every balance and applies the actions performed since then when it is read.
+//
+//go:generate ifacemaker -f corporate_action.go -o repository/corporate_action.go -i CorporateAction -s CorporateAction -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/corporate_action.go -destination repository/mock/mock_corporate_action.go
+type CorporateAction struct {
+	Object
+}
+
+// Index retrieves the sequence number of the last action of the Currency, zero if
+// there are no actions.
+func (c *CorporateAction) Index(ctx context.Context, curr model.Currency) (uint64, error) {
+	raw, err := c.Object.DB.Get(ctx, keyvalue.Key(keyvalue.Join(corporateActionIndexPrefix, string(curr))))
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+	}
+
+	index, err := strconv.ParseUint(string(raw), 10, 64) //nolint:gomnd
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+	}
+
+	return index, nil
+}
+
+// Append stores the action with the next sequence number of its currency and makes
+// it the last one.
+func (c *CorporateAction) Append(ctx context.Context, action *model.CorporateAction) error {
+	index, err := c.Index(ctx, action.Currency)
+	if err != nil {
+		return err
+	}
+
+	action.Seq = index + 1
+
+	if err := c.Object.Save(ctx, model.ObjectQuery(c.key(action.Currency, action.Seq)), action); err != nil {
+		return fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+	}
+
+	for key, val := range map[string]string{
+		keyvalue.Join(corporateActionIDPrefix, action.ID):                  c.key(action.Currency, action.Seq),
+		keyvalue.Join(corporateActionIndexPrefix, string(action.Currency)): strconv.FormatUint(action.Seq, 10), //nolint:gomnd
+	} {
+		if err := c.Object.DB.Set(ctx, keyvalue.Key(key), keyvalue.Value(val)); err != nil {
+			return fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Load retrieves the action by its identifier. If no action is found, nil is returned.
+func (c *CorporateAction) Load(ctx context.Context, id string) (*model.CorporateAction, error) {
+	ref, err := c.Object.DB.Get(ctx, keyvalue.Key(keyvalue.Join(corporateActionIDPrefix, id)))
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, nil //nolint:nilnil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+	}
+
+	action := new(model.CorporateAction)
+	if err := c.Object.Load(ctx, model.ObjectQuery(ref), action); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+	}
+
+	return action, nil
+}
+
+// List retrieves the actions of the Currency with the sequence numbers greater than
+// after in ascending order.
+func (c *CorporateAction) List(
+	ctx context.Context,
+	curr model.Currency,
+	after uint64,
+) ([]*model.CorporateAction, error) {
+	prefix := keyvalue.Join(corporateActionPrefix, string(curr))
+
+	iter, err := c.Object.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var out []*model.CorporateAction
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+		}
+
+		// skip the actions of other currencies sharing the same beginning
+		seq, ok := strings.CutPrefix(string(k), prefix+keyvalue.KeySeparator)
+		if !ok || seq <= fmt.Sprintf(corporateActionSeqLayout, after) {
+			continue
+		}
+
+		action := new(model.CorporateAction)
+		if err := action.UnmarshalBinary(v); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrCorporateActionDatabase, err.Error())
+		}
+
+		out = append(out, action)
+	}
+
+	return out, nil
+}
+
+// adjust applies the actions of the Currency performed after the one with given
+// index to the balance.
+func (c *CorporateAction) adjust(
+	ctx context.Context,
+	curr model.Currency,
+	val *big.Int,
+	index uint64,
+) (*big.Int, error) {
+	last, err := c.Index(ctx, curr)
+	if err != nil || last <= index {
+		return val, err
+	}
+
+	actions, err := c.List(ctx, curr, index)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, action := range actions {
+		if action.Seq > last {
+			break
+		}
+
+		val = action.Apply(val)
+	}
+
+	return val, nil
+}
+
+// encode encodes the value of the currency together with the index of its actions, so
+// decode adjusts it by the actions performed since then. Without the storage of the
+// actions or the actions of the currency the value is encoded as is.
+func (c *CorporateAction) encode(ctx context.Context, curr model.Currency, val *big.Int) (keyvalue.Value, error) {
+	if c == nil {
+		return encodeBalance(val), nil
+	}
+
+	index, err := c.Index(ctx, curr)
+	if err != nil || index == 0 {
+		return encodeBalance(val), err
+	}
+
+	return encodeIndexedBalance(val, time.Time{}, index), nil
+}
+
+// decode decodes the value of the currency encoded by encode and applies the actions
+// of the currency performed since then.
+func (c *CorporateAction) decode(ctx context.Context, curr model.Currency, raw keyvalue.Value) (*big.Int, error) {
+	val, _, index, err := decodeIndexedBalance(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBalanceEncoding, err.Error())
+	}
+
+	if c == nil {
+		return val, nil
+	}
+
+	return c.adjust(ctx, curr, val, index)
+}
+
+// key creates a key of the action of the currency.
+// example: "corpaction/currency/00000000000000000001"
+func (c *CorporateAction) key(curr model.Currency, seq uint64) string {
+	return keyvalue.Join(corporateActionPrefix, string(curr), fmt.Sprintf(corporateActionSeqLayout, seq))
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrCreditLineDatabase represents a generic error related to the database operations.
+var ErrCreditLineDatabase = errors.New("credit line database error")
+
+// creditLinePrefix is a key prefix of the credit line records.
+const creditLinePrefix = "creditline"
+
+// CreditLine is a structure which encapsulates the keyvalue.DB to interact with
+// credit limits of addresses in database. A credit limit is set for a single account,
+// so the overdrafts of the accounts of an address do not add up. With the corporate
+// actions set, the limits are adjusted by the actions like the balances.
+//
+//go:generate ifacemaker -f credit_line.go -o repository/credit_line.go -i CreditLine -s CreditLine -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/credit_line.go -destination repository/mock/mock_credit_line.go
+type CreditLine struct {
+	keyvalue.DB
+
+	// Actions is an optional storage of corporate actions.
+	Actions *CorporateAction
+}
+
+// Load retrieves the credit limit of the Address on the Account in the Currency.
+// If no record is found, a zero value is returned.
+func (cl *CreditLine) Load(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, error) {
+	raw, err := cl.DB.Get(ctx, cl.key(addr, acc, curr))
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return new(big.Int), nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrCreditLineDatabase, err.Error())
+	}
+
+	val, err := cl.Actions.decode(ctx, curr, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrCreditLineDatabase, err.Error())
+	}
+
+	return val, nil
+}
+
+// Save saves the credit limit of the Address on the Account in the Currency. A zero value removes
+// the credit line.
+func (cl *CreditLine) Save(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	val *big.Int,
+) error {
+	if val.Sign() == 0 {
+		if err := cl.DB.Del(ctx, cl.key(addr, acc, curr)); err != nil {
+			return fmt.Errorf("%w: %s", ErrCreditLineDatabase, err.Error())
+		}
+
+		return nil
+	}
+
+	raw, err := cl.Actions.encode(ctx, curr, val)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrCreditLineDatabase, err.Error())
+	}
+
+	if err := cl.DB.Set(ctx, cl.key(addr, acc, curr), raw); err != nil {
+		return fmt.Errorf("%w: %s", ErrCreditLineDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// key creates a key of the credit line.
+// example: "creditline/address/2b/currency"
+func (cl *CreditLine) key(addr model.Address, acc model.Account, curr model.Currency) keyvalue.Key {
+	return keyvalue.Key(keyvalue.Join(creditLinePrefix, string(addr), encodeAccount(acc), string(curr)))
+}
@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrDistributionDatabase represents a generic error related to the database operations.
+var ErrDistributionDatabase = errors.New("distribution database error")
+
+// Key prefixes of the distribution records.
+const (
+	distributionPrefix       = "distribution"
+	distributionActivePrefix = "distributionactive"
+)
+
+// Distribution is a structure which encapsulates the keyvalue.DB to interact with
+// pro-rata distributions in database. Besides the distributions themselves it
+// maintains an index of the active distributions of every currency, both paid in it
+// and paid to its holders.
+//
+//go:generate ifacemaker -f distribution.go -o repository/distribution.go -i Distribution -s Distribution -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/distribution.go -destination repository/mock/mock_distribution.go
+type Distribution struct {
+	Object
+}
+
+// Load retrieves the distribution by its identifier. If no distribution is found,
+// nil is returned.
+func (d *Distribution) Load(ctx context.Context, id string) (*model.Distribution, error) {
+	dist := new(model.Distribution)
+	if err := d.Object.Load(ctx, model.ObjectQuery(keyvalue.Join(distributionPrefix, id)), dist); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrDistributionDatabase, err.Error())
+	}
+
+	return dist, nil
+}
+
+// Save stores the distribution. Active distributions are added to the index of their
+// currencies, while completed ones are removed from it.
+func (d *Distribution) Save(ctx context.Context, dist *model.Distribution) error {
+	if err := d.Object.Save(ctx, model.ObjectQuery(keyvalue.Join(distributionPrefix, dist.ID)), dist); err != nil {
+		return fmt.Errorf("%w: %s", ErrDistributionDatabase, err.Error())
+	}
+
+	for _, curr := range []model.Currency{dist.Currency, dist.Reference} {
+		var (
+			key = keyvalue.Key(keyvalue.Join(distributionActivePrefix, string(curr), dist.ID))
+			err error
+		)
+
+		if dist.Status == model.DistributionStatusActive {
+			err = d.Object.DB.Set(ctx, key, keyvalue.Value(dist.ID))
+		} else {
+			err = d.Object.DB.Del(ctx, key)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrDistributionDatabase, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Active retrieves the identifiers of the active distributions paid in the Currency
+// or to its holders.
+func (d *Distribution) Active(ctx context.Context, curr model.Currency) ([]string, error) {
+	prefix := keyvalue.Join(distributionActivePrefix, string(curr))
+
+	iter, err := d.Object.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrDistributionDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var out []string
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrDistributionDatabase, err.Error())
+		}
+
+		// skip the distributions of other currencies sharing the same beginning
+		if !strings.HasPrefix(string(k), prefix+keyvalue.KeySeparator) {
+			continue
+		}
+
+		out = append(out, string(v))
+	}
+
+	return out, nil
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrHoldDatabase represents a generic error related to the database operations.
+var ErrHoldDatabase = errors.New("hold database error")
+
+// Key prefixes of the hold records.
+const (
+	holdPrefix      = "hold"
+	holdIndexPrefix = "holdidx"
+)
+
+// Hold is a structure which encapsulates the keyvalue.DB to interact with
+// authorization holds in database. Besides the holds themselves it maintains an
+// index of the active holds of every balance. With the corporate actions set, the
+// amounts of a hold are stored with the index of the actions of its currency and
+// adjusted by the actions performed since then when the hold is loaded, like the
+// balances.
+//
+//go:generate ifacemaker -f hold.go -o repository/hold.go -i Hold -s Hold -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/hold.go -destination repository/mock/mock_hold.go
+type Hold struct {
+	Object
+
+	// Actions is an optional storage of corporate actions.
+	Actions *CorporateAction
+}
+
+// Load retrieves the hold by its identifier. If no hold is found, nil is returned.
+func (h *Hold) Load(ctx context.Context, id string) (*model.Hold, error) {
+	hold := new(model.Hold)
+	if err := h.Object.Load(ctx, model.ObjectQuery(keyvalue.Join(holdPrefix, id)), hold); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrHoldDatabase, err.Error())
+	}
+
+	if h.Actions == nil {
+		return hold, nil
+	}
+
+	for _, amt := range []*model.Amount{hold.Amount, hold.Captured} {
+		val, err := h.Actions.adjust(ctx, hold.Currency, amt.Int(), hold.Index)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrHoldDatabase, err.Error())
+		}
+
+		*amt = *hold.Currency.Amount(val)
+	}
+
+	return hold, nil
+}
+
+// Save stores the hold. Active holds are added to the index of the balance, while
+// holds in a final status are removed from it.
+func (h *Hold) Save(ctx context.Context, hold *model.Hold) error {
+	if h.Actions != nil {
+		index, err := h.Actions.Index(ctx, hold.Currency)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrHoldDatabase, err.Error())
+		}
+
+		hold.Index = index
+	}
+
+	if err := h.Object.Save(ctx, model.ObjectQuery(keyvalue.Join(holdPrefix, hold.ID)), hold); err != nil {
+		return fmt.Errorf("%w: %s", ErrHoldDatabase, err.Error())
+	}
+
+	var (
+		key = h.index(hold.Address, hold.Account, hold.Currency, hold.ID)
+		err error
+	)
+
+	if hold.IsFinal() {
+		err = h.Object.DB.Del(ctx, key)
+	} else {
+		err = h.Object.DB.Set(ctx, key, keyvalue.Value(hold.ID))
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrHoldDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// List retrieves all the active holds of the balance for given Address, Account and
+// Currency. Expired holds are returned as well, since their status is stored lazily.
+func (h *Hold) List(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) ([]*model.Hold, error) {
+	prefix := h.index(addr, acc, curr, "")
+
+	iter, err := h.Object.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHoldDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var ids []string
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrHoldDatabase, err.Error())
+		}
+
+		// skip the holds of other balances sharing the prefix
+		if !strings.HasPrefix(string(k), string(prefix)+keyvalue.KeySeparator) {
+			continue
+		}
+
+		ids = append(ids, string(v))
+	}
+
+	out := make([]*model.Hold, 0, len(ids))
+	for _, id := range ids {
+		hold, err := h.Load(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if hold != nil {
+			out = append(out, hold)
+		}
+	}
+
+	return out, nil
+}
+
+// index creates a key of the hold in the index of the balance.
+// example: "holdidx/address/2b/currency/id"
+func (h *Hold) index(
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	id string,
+) keyvalue.Key {
+	return keyvalue.Key(keyvalue.Join(
+		holdIndexPrefix,
+		string(addr),
+		encodeAccount(acc),
+		string(curr),
+		id,
+	))
+}
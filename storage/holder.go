@@ -0,0 +1,722 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrHolderDatabase represents a generic error related to the database operations.
+var ErrHolderDatabase = errors.New("holder database error")
+
+// Keys of the holder registry.
+const (
+	holderPrefix       = "holder"
+	holderRankPrefix   = "holderrank"
+	holderCountPrefix  = "holdercount"
+	holderBucketPrefix = "holderbucket"
+	holderSupplyPrefix = "holdersupply"
+
+	// HolderBuckets is the number of the buckets the addresses of the holders are
+	// spread over by their hashes.
+	HolderBuckets = 256
+
+	// HolderPageLimit is the default size of a page of the holders.
+	HolderPageLimit = 100
+
+	// holderRankDigits is the maximum number of the decimal digits of a ranked balance.
+	holderRankDigits = 999
+)
+
+// Holder is a structure which encapsulates the keyvalue.DB to maintain the registry
+// of the holders, a reverse index of the non-zero balances by currency and account.
+// Besides the balances of the holders it keeps their number, the total of the positive
+// balances and a ranking of the positive balances in descending order.
+//
+// The addresses are also indexed by the buckets of their hashes, so Addresses reads
+// the holders in batches starting from any of them without scanning the preceding
+// ones: the partial key queries of the ledger in transactions, which update the state,
+// can not start from a key. Like the balance records, the index keeps the addresses
+// after their balances become zero, so the holders as of a snapshot are found. The
+// number and the total of the holders are kept in a record per bucket, so that the
+// transactions updating the balances of different buckets do not conflict on them.
+//
+// The registry is updated by Save, which storage.Balance calls for every balance it
+// saves, and by Credit for the credits of the accumulator mode. The credits of the
+// holders with positive balances are kept as records unique for the transaction, like
+// the deltas of the balances, and are merged by the next Save. The balances saved
+// before the registry is set are added to it by storage.Balance.Register.
+//
+// With the corporate actions set, the balances and the totals are adjusted by the
+// actions when they are read. The total of a bucket is adjusted as a whole, so after
+// an action it may differ from the sum of the adjusted balances by the rounding, and
+// an address, whose balance is rounded to zero by an action, is counted as a holder
+// till its balance is saved again.
+//
+//go:generate ifacemaker -f holder.go -o repository/holder.go -i Holder -s Holder -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/holder.go -destination repository/mock/mock_holder.go
+type Holder struct {
+	keyvalue.DB
+
+	// Actions is an optional storage of corporate actions. The registered balances,
+	// the credits and the totals are stored with the index of the actions and adjusted
+	// by the actions performed since then when they are read, like the balances.
+	Actions *CorporateAction
+}
+
+// Save updates the registry with the balance for given Address, Account, and Currency.
+// A zero balance removes the address from the holders. The credits of the address kept
+// since the previous Save are merged.
+func (h *Holder) Save(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	val *big.Int,
+) error {
+	key := keyvalue.Key(h.key(holderPrefix, curr, acc, string(addr)))
+
+	raw, err := h.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	// the ranking keeps the value as it is stored
+	stored, err := decodeBalance(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+
+	registered, err := h.decode(ctx, curr, raw)
+	if err != nil {
+		return err
+	}
+
+	// the credits are kept only for the positive balances
+	var credits map[keyvalue.Key]*big.Int
+	if registered.Sign() > 0 {
+		if credits, err = h.credits(ctx, addr, acc, curr); err != nil {
+			return err
+		}
+	}
+
+	if len(credits) == 0 && registered.Cmp(val) == 0 {
+		return nil
+	}
+
+	// before = registered + credits
+	before := new(big.Int).Set(registered)
+	for k, credit := range credits {
+		before.Add(before, credit)
+
+		if err := h.set(ctx, k, nil); err != nil {
+			return err
+		}
+	}
+
+	if stored.Sign() > 0 {
+		if err := h.set(ctx, h.rank(addr, acc, curr, stored), nil); err != nil {
+			return err
+		}
+	}
+
+	if val.Sign() > 0 {
+		if err := h.set(ctx, h.rank(addr, acc, curr, val), keyvalue.Value(addr)); err != nil {
+			return err
+		}
+	}
+
+	// holderbucket/currency/2b/bucket/address
+	bucket := keyvalue.Key(keyvalue.Join(
+		h.key(holderBucketPrefix, curr, acc, holderBucket(holderHash(addr))),
+		string(addr),
+	))
+
+	encoded, err := h.encode(ctx, curr, val)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case val.Sign() == 0:
+		err = h.set(ctx, key, nil)
+	case registered.Sign() == 0:
+		err = errors.Join(h.set(ctx, key, encoded), h.set(ctx, bucket, keyvalue.Value(addr)))
+	default:
+		err = h.set(ctx, key, encoded)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the total of the bucket includes the registered balance, and the credits are
+	// removed: supply = supply + max(val, 0) - max(registered, 0)
+	delta := new(big.Int).Sub(positive(val), positive(registered))
+	if delta.Sign() != 0 {
+		if err := h.supply(ctx, addr, acc, curr, delta); err != nil {
+			return err
+		}
+	}
+
+	// the number of the holders changes when the balance becomes zero or non-zero
+	switch {
+	case before.Sign() == 0 && val.Sign() != 0:
+		return h.count(ctx, addr, acc, curr, 1)
+	case before.Sign() != 0 && val.Sign() == 0:
+		return h.count(ctx, addr, acc, curr, -1)
+	default:
+		return nil
+	}
+}
+
+// Credit keeps the credit of the accumulator mode for given Address, Account, and
+// Currency without updating the records shared with other transactions. It returns
+// false and keeps nothing, unless the address holds a positive balance, so the credit
+// does not change the number of the holders. Then the balance must be saved instead.
+func (h *Holder) Credit(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	val *big.Int,
+) (bool, error) {
+	tx, ok := model.TransactionFromContext(ctx)
+	if !ok {
+		return false, fmt.Errorf("%w: transaction information is missing", ErrHolderDatabase)
+	}
+
+	raw, err := h.get(ctx, keyvalue.Key(h.key(holderPrefix, curr, acc, string(addr))))
+	if err != nil {
+		return false, err
+	}
+
+	registered, err := h.decode(ctx, curr, raw)
+	if err != nil {
+		return false, err
+	}
+
+	if registered.Sign() <= 0 {
+		return false, nil
+	}
+
+	// holdersupply/currency/2b/bucket/address/tx
+	key := keyvalue.Key(keyvalue.Join(h.creditPrefix(addr, acc, curr), tx.ID))
+
+	// several credits of the same address in one transaction share the record
+	prev, err := h.get(ctx, key)
+	if err != nil {
+		return false, err
+	}
+
+	credit, err := h.decode(ctx, curr, prev)
+	if err != nil {
+		return false, err
+	}
+
+	encoded, err := h.encode(ctx, curr, credit.Add(credit, val))
+	if err != nil {
+		return false, err
+	}
+
+	return true, h.set(ctx, key, encoded)
+}
+
+// Count retrieves the number of the addresses with a non-zero balance for given
+// Account and Currency.
+func (h *Holder) Count(ctx context.Context, acc model.Account, curr model.Currency) (uint64, error) {
+	// holdercount/currency/2b/bucket
+	prefix := h.key(holderCountPrefix, curr, acc, "")
+
+	iter, err := h.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var out uint64
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+
+		// skip the numbers of other accounts sharing the same beginning
+		if !strings.HasPrefix(string(k), prefix+keyvalue.KeySeparator) {
+			continue
+		}
+
+		n, err := strconv.ParseUint(string(v), 10, 64) //nolint:gomnd
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+
+		out += n
+	}
+
+	return out, nil
+}
+
+// Supply retrieves the total of the positive balances for given Account and Currency,
+// including the credits kept since they were saved.
+func (h *Holder) Supply(ctx context.Context, acc model.Account, curr model.Currency) (*big.Int, error) {
+	// holdersupply/currency/2b/bucket
+	prefix := h.key(holderSupplyPrefix, curr, acc, "")
+
+	iter, err := h.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	out := new(big.Int)
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+
+		// skip the totals of other accounts sharing the same beginning, the totals of
+		// the buckets and the credits are summed up
+		if !strings.HasPrefix(string(k), prefix+keyvalue.KeySeparator) {
+			continue
+		}
+
+		val, err := h.decode(ctx, curr, v)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Add(out, val)
+	}
+
+	return out, nil
+}
+
+// Addresses retrieves the addresses, which have ever held a balance of given Account
+// and Currency, bucket by bucket in ascending order of the addresses within a bucket.
+// The addresses up to and including the bookmark are skipped, and at most limit
+// addresses are returned unless the limit is zero. Only the bucket of the bookmark and
+// the following ones are read, so the cost of a batch is bounded by the limit and the
+// size of a bucket.
+func (h *Holder) Addresses(
+	ctx context.Context,
+	acc model.Account,
+	curr model.Currency,
+	bookmark model.Address,
+	limit int,
+) ([]model.Address, error) {
+	var first uint32
+	if bookmark != "" {
+		first = holderHash(bookmark)
+	}
+
+	var out []model.Address
+	for b := first; b < HolderBuckets && (limit == 0 || len(out) < limit); b++ {
+		// holderbucket/currency/2b/bucket/address
+		prefix := h.key(holderBucketPrefix, curr, acc, holderBucket(b))
+
+		iter, err := h.DB.Iter(ctx, keyvalue.Prefix(prefix))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+
+		for iter.HasNext() && (limit == 0 || len(out) < limit) {
+			k, _, err := iter.Next()
+			if err != nil {
+				_ = iter.Close()
+				return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+			}
+
+			// skip the holders of other accounts sharing the same beginning
+			addr, ok := strings.CutPrefix(string(k), prefix+keyvalue.KeySeparator)
+			if !ok || strings.Contains(addr, keyvalue.KeySeparator) {
+				continue
+			}
+
+			// the addresses are compared, so the bookmark holder may leave the registry
+			if b == first && bookmark != "" && addr <= string(bookmark) {
+				continue
+			}
+
+			out = append(out, model.Address(addr))
+		}
+
+		_ = iter.Close()
+	}
+
+	return out, nil
+}
+
+// List retrieves a page of the holders for given Account and Currency in ascending
+// order of their addresses.
+func (h *Holder) List(ctx context.Context, q model.HolderQuery) (*model.HolderPage, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = HolderPageLimit
+	}
+
+	// holder/currency/2b/address
+	prefix := h.key(holderPrefix, q.Currency, q.Account, "")
+
+	iter, err := h.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	page := new(model.HolderPage)
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+
+		// skip the holders of other accounts sharing the same beginning
+		addr, ok := strings.CutPrefix(string(k), prefix+keyvalue.KeySeparator)
+		if !ok || strings.Contains(addr, keyvalue.KeySeparator) {
+			continue
+		}
+
+		// the addresses are compared, so the bookmark holder may leave the registry
+		if q.Bookmark != "" && addr <= q.Bookmark {
+			continue
+		}
+
+		if len(page.Items) == limit {
+			page.Bookmark = string(page.Items[len(page.Items)-1].Address)
+			break
+		}
+
+		registered, err := h.decode(ctx, q.Currency, v)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := h.holding(ctx, model.Address(addr), q.Account, q.Currency, registered)
+		if err != nil {
+			return nil, err
+		}
+
+		page.Items = append(page.Items, &model.Holding{
+			Address:  model.Address(addr),
+			Account:  q.Account,
+			Currency: q.Currency,
+			Balance:  q.Currency.Amount(val),
+		})
+	}
+
+	return page, nil
+}
+
+// Top retrieves at most limit holders with the largest positive balances for given
+// Account and Currency in descending order of the balances. The holders are ranked by
+// the balances saved last, while the credits kept since then and the corporate actions
+// performed since then are included in the returned balances. The balances saved in
+// the units before a corporate action are ranked by their values in those units, so
+// the ranking is approximate until they are saved again.
+func (h *Holder) Top(
+	ctx context.Context,
+	acc model.Account,
+	curr model.Currency,
+	limit int,
+) ([]*model.Holding, error) {
+	if limit <= 0 {
+		limit = HolderPageLimit
+	}
+
+	// holderrank/currency/2b/rank/address
+	prefix := h.key(holderRankPrefix, curr, acc, "")
+
+	iter, err := h.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var addrs []model.Address
+	for iter.HasNext() && len(addrs) < limit {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+
+		// skip the ranks of other accounts sharing the same beginning
+		if !strings.HasPrefix(string(k), prefix+keyvalue.KeySeparator) {
+			continue
+		}
+
+		addrs = append(addrs, model.Address(v))
+	}
+
+	out := make([]*model.Holding, 0, len(addrs))
+	for _, addr := range addrs {
+		raw, err := h.get(ctx, keyvalue.Key(h.key(holderPrefix, curr, acc, string(addr))))
+		if err != nil {
+			return nil, err
+		}
+
+		registered, err := h.decode(ctx, curr, raw)
+		if err != nil {
+			return nil, err
+		}
+
+		val, err := h.holding(ctx, addr, acc, curr, registered)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, &model.Holding{
+			Address:  addr,
+			Account:  acc,
+			Currency: curr,
+			Balance:  curr.Amount(val),
+		})
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].Balance.Int().Cmp(out[j].Balance.Int()) > 0
+	})
+
+	return out, nil
+}
+
+// count changes the number of the holders of the bucket of the address by delta.
+func (h *Holder) count(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	delta int,
+) error {
+	key := keyvalue.Key(h.key(holderCountPrefix, curr, acc, holderBucket(holderHash(addr))))
+
+	raw, err := h.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	var n uint64
+	if raw != nil {
+		if n, err = strconv.ParseUint(string(raw), 10, 64); err != nil { //nolint:gomnd
+			return fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+	}
+
+	switch {
+	case delta < 0 && n > 0:
+		n--
+	case delta > 0:
+		n++
+	}
+
+	if n == 0 {
+		return h.set(ctx, key, nil)
+	}
+
+	return h.set(ctx, key, keyvalue.Value(strconv.FormatUint(n, 10))) //nolint:gomnd
+}
+
+// credits returns the credits of the address kept since it was saved, keyed by their
+// database keys.
+func (h *Holder) credits(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (map[keyvalue.Key]*big.Int, error) {
+	prefix := h.creditPrefix(addr, acc, curr)
+
+	iter, err := h.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	out := make(map[keyvalue.Key]*big.Int)
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+		}
+
+		// skip the credits of other addresses sharing the same beginning
+		tx, ok := strings.CutPrefix(string(k), prefix+keyvalue.KeySeparator)
+		if !ok || strings.Contains(tx, keyvalue.KeySeparator) {
+			continue
+		}
+
+		credit, err := h.decode(ctx, curr, v)
+		if err != nil {
+			return nil, err
+		}
+
+		out[k] = credit
+	}
+
+	return out, nil
+}
+
+// holding returns the balance of the address registered by Save with the credits kept
+// since then.
+func (h *Holder) holding(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	registered *big.Int,
+) (*big.Int, error) {
+	credits, err := h.credits(ctx, addr, acc, curr)
+	if err != nil {
+		return nil, err
+	}
+
+	out := new(big.Int).Set(registered)
+	for _, credit := range credits {
+		out.Add(out, credit)
+	}
+
+	return out, nil
+}
+
+// supply changes the total of the bucket of the address by delta.
+func (h *Holder) supply(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	delta *big.Int,
+) error {
+	key := keyvalue.Key(h.key(holderSupplyPrefix, curr, acc, holderBucket(holderHash(addr))))
+
+	raw, err := h.get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	val, err := h.decode(ctx, curr, raw)
+	if err != nil {
+		return err
+	}
+
+	if val.Add(val, delta).Sign() == 0 {
+		return h.set(ctx, key, nil)
+	}
+
+	encoded, err := h.encode(ctx, curr, val)
+	if err != nil {
+		return err
+	}
+
+	return h.set(ctx, key, encoded)
+}
+
+// decode decodes the registered value and applies the corporate actions of the
+// currency performed since it was saved.
+func (h *Holder) decode(ctx context.Context, curr model.Currency, raw keyvalue.Value) (*big.Int, error) {
+	val, err := h.Actions.decode(ctx, curr, raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+
+	return val, nil
+}
+
+// encode encodes the registered value together with the index of the corporate
+// actions of the currency.
+func (h *Holder) encode(ctx context.Context, curr model.Currency, val *big.Int) (keyvalue.Value, error) {
+	raw, err := h.Actions.encode(ctx, curr, val)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+
+	return raw, nil
+}
+
+// get retrieves the record. If no record is found, nil is returned.
+func (h *Holder) get(ctx context.Context, key keyvalue.Key) (keyvalue.Value, error) {
+	raw, err := h.DB.Get(ctx, key)
+	if errors.Is(err, keyvalue.ErrNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+
+	return raw, nil
+}
+
+// set writes the record or deletes it, if the value is nil.
+func (h *Holder) set(ctx context.Context, key keyvalue.Key, val keyvalue.Value) error {
+	var err error
+	if val == nil {
+		err = h.DB.Del(ctx, key)
+	} else {
+		err = h.DB.Set(ctx, key, val)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrHolderDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// rank creates a key of the positive balance in the ranking. Balances with more digits
+// get smaller prefixes and the digits are complemented, so that the lexical order of
+// the keys is the descending order of the balances.
+// example: 120 -> "holderrank/currency/2b/996879/address"
+func (h *Holder) rank(addr model.Address, acc model.Account, curr model.Currency, val *big.Int) keyvalue.Key {
+	digits := []byte(val.String())
+	for i, d := range digits {
+		digits[i] = '9' - d + '0'
+	}
+
+	rank := fmt.Sprintf("%03d%s", holderRankDigits-len(digits), digits)
+
+	return keyvalue.Key(keyvalue.Join(h.key(holderRankPrefix, curr, acc, rank), string(addr)))
+}
+
+// creditPrefix creates the prefix of the credit records of the address.
+// example: "holdersupply/currency/2b/0f/address"
+func (h *Holder) creditPrefix(addr model.Address, acc model.Account, curr model.Currency) string {
+	return keyvalue.Join(h.key(holderSupplyPrefix, curr, acc, holderBucket(holderHash(addr))), string(addr))
+}
+
+// key creates a key of the registry record with given prefix.
+// example: "holder/currency/2b/address"
+func (h *Holder) key(prefix string, curr model.Currency, acc model.Account, suffix string) string {
+	return keyvalue.Join(prefix, string(curr), encodeAccount(acc), suffix)
+}
+
+// holderHash returns the bucket number of the address.
+func holderHash(addr model.Address) uint32 {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(addr))
+
+	return hash.Sum32() % HolderBuckets
+}
+
+// holderBucket returns the bucket number as a part of the key.
+// example: 15 -> "0f"
+func holderBucket(n uint32) string {
+	return fmt.Sprintf("%02x", n)
+}
+
+// positive returns the value or zero, if the value is negative.
+func positive(val *big.Int) *big.Int {
+	if val.Sign() < 0 {
+		return new(big.Int)
+	}
+
+	return val
+}
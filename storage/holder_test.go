@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHolder(t *testing.T) {
+	db := new(inmem.KeyValueDB)
+	registry := &Holder{DB: db}
+	b := &Balance{DB: db, Registry: registry}
+
+	var (
+		tt = model.AccountToken
+		c  = model.Currency("ETH")
+	)
+
+	tx := func(id string) context.Context {
+		return model.ContextWithTransaction(context.Background(), model.Transaction{ID: id})
+	}
+
+	for addr, val := range map[model.Address]int64{"a": 5, "b": 120, "c": 9, "d": 120, "e": -3} {
+		require.NoError(t, b.Save(tx("tx1"), addr, tt, c, big.NewInt(val)))
+	}
+
+	// several saves of the same balance in one transaction keep a single rank
+	require.NoError(t, b.Save(tx("tx2"), "a", tt, c, big.NewInt(7)))
+	require.NoError(t, b.Save(tx("tx2"), "a", tt, c, big.NewInt(1000)))
+	require.NoError(t, b.Save(tx("tx2"), "c", tt, c, big.NewInt(0)))
+	require.NoError(t, b.Save(tx("tx2"), "f", tt, "BTC", big.NewInt(1)))
+
+	n, err := registry.Count(context.Background(), tt, c)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4), n)
+
+	top, err := registry.Top(context.Background(), tt, c, 10)
+	require.NoError(t, err)
+
+	var ranked []model.Address
+	for _, h := range top {
+		ranked = append(ranked, h.Address)
+	}
+
+	assert.Equal(t, []model.Address{"a", "b", "d"}, ranked)
+	assert.Equal(t, c.Amount(big.NewInt(1000)), top[0].Balance)
+
+	page, err := registry.List(context.Background(), model.HolderQuery{Currency: c, Account: tt, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, model.Address("a"), page.Items[0].Address)
+	assert.Equal(t, model.Address("b"), page.Items[1].Address)
+	assert.Equal(t, "b", page.Bookmark)
+
+	// the bookmark holder leaving the registry does not break the pagination
+	require.NoError(t, b.Save(tx("tx3"), "b", tt, c, big.NewInt(0)))
+
+	page, err = registry.List(context.Background(), model.HolderQuery{Currency: c, Account: tt, Limit: 2, Bookmark: page.Bookmark})
+	require.NoError(t, err)
+	require.Len(t, page.Items, 2)
+	assert.Equal(t, model.Address("d"), page.Items[0].Address)
+	assert.Equal(t, model.Address("e"), page.Items[1].Address)
+	assert.Equal(t, c.Amount(big.NewInt(-3)), page.Items[1].Balance)
+	assert.Empty(t, page.Bookmark)
+
+	n, err = registry.Count(context.Background(), tt, c)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), n)
+
+	supply, err := b.Supply(context.Background(), tt, c)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1120), supply)
+
+	// the addresses are read in batches, the ones with zero balances are kept
+	var (
+		addrs    []model.Address
+		bookmark model.Address
+	)
+
+	for {
+		batch, err := b.Holders(context.Background(), tt, c, bookmark, 2)
+		require.NoError(t, err)
+
+		if len(batch) == 0 {
+			break
+		}
+
+		addrs = append(addrs, batch...)
+		bookmark = batch[len(batch)-1]
+	}
+
+	all, err := b.Holders(context.Background(), tt, c, "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, all, addrs)
+	assert.ElementsMatch(t, []model.Address{"a", "b", "c", "d", "e"}, addrs)
+}
+
+func TestHolder_Credit(t *testing.T) {
+	db := new(inmem.KeyValueDB)
+	registry := &Holder{DB: db}
+	b := &Balance{DB: db, Accumulator: true}
+
+	var (
+		tt = model.AccountToken
+		c  = model.Currency("ETH")
+	)
+
+	tx := func(id string) context.Context {
+		return model.ContextWithTransaction(context.Background(), model.Transaction{ID: id})
+	}
+
+	check := func(count uint64, supply int64, holdings map[model.Address]int64) {
+		t.Helper()
+
+		n, err := registry.Count(context.Background(), tt, c)
+		require.NoError(t, err)
+		assert.Equal(t, count, n)
+
+		total, err := registry.Supply(context.Background(), tt, c)
+		require.NoError(t, err)
+		assert.Equal(t, big.NewInt(supply), total)
+
+		page, err := registry.List(context.Background(), model.HolderQuery{Currency: c, Account: tt})
+		require.NoError(t, err)
+
+		got := make(map[model.Address]int64)
+		for _, h := range page.Items {
+			got[h.Address] = h.Balance.Int().Int64()
+		}
+
+		assert.Equal(t, holdings, got)
+	}
+
+	// the balance saved before the registry is set is backfilled
+	require.NoError(t, b.Save(tx("tx1"), "a", tt, c, big.NewInt(5)))
+
+	b.Registry = registry
+	require.NoError(t, b.Register(tx("tx2"), []model.Address{"a", "x"}, tt, c))
+	require.NoError(t, b.Register(tx("tx3"), []model.Address{"a"}, tt, c))
+	check(1, 5, map[model.Address]int64{"a": 5})
+
+	// the first credit of a holder is saved, the next ones are kept until compaction
+	for _, id := range []string{"tx4", "tx5", "tx6"} {
+		require.NoError(t, b.Credit(tx(id), "b", tt, c, big.NewInt(10)))
+	}
+
+	check(2, 35, map[model.Address]int64{"a": 5, "b": 30})
+
+	require.NoError(t, b.Compact(tx("tx7"), "b", tt, c))
+	check(2, 35, map[model.Address]int64{"a": 5, "b": 30})
+
+	require.NoError(t, b.Credit(tx("tx8"), "b", tt, c, big.NewInt(1)))
+	require.NoError(t, b.Save(tx("tx9"), "b", tt, c, big.NewInt(0)))
+	check(1, 5, map[model.Address]int64{"a": 5})
+}
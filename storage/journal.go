@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrJournalDatabase represents a generic error related to the database operations.
+var ErrJournalDatabase = errors.New("journal database error")
+
+const (
+	journalPrefix      = "journal"
+	journalCountPrefix = "entrycount"
+)
+
+// Journal is a structure which encapsulates the keyvalue.DB to interact with the
+// entries of the double-entry journal in database.
+//
+//go:generate ifacemaker -f journal.go -o repository/journal.go -i Journal -s Journal -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/journal.go -destination repository/mock/mock_journal.go
+type Journal struct {
+	Object
+}
+
+// Load retrieves the journal entry by its identifier. If no entry is found, nil is
+// returned.
+func (j *Journal) Load(ctx context.Context, id string) (*model.JournalEntry, error) {
+	e := new(model.JournalEntry)
+	if err := j.Object.Load(ctx, model.ObjectQuery(keyvalue.Join(journalPrefix, id)), e); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrJournalDatabase, err.Error())
+	}
+
+	return e, nil
+}
+
+// Save stores the journal entry. Unbalanced entries fail to validate.
+func (j *Journal) Save(ctx context.Context, e *model.JournalEntry) error {
+	if err := j.Object.Save(ctx, model.ObjectQuery(keyvalue.Join(journalPrefix, e.ID)), e); err != nil {
+		return fmt.Errorf("%w: %s", ErrJournalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Iter iterates over all the journal entries. Iteration stops if cb returns true.
+func (j *Journal) Iter(ctx context.Context, cb func(e *model.JournalEntry) (stop bool)) error {
+	if err := j.Object.Iter(ctx, model.ObjectQuery(journalPrefix), new(model.JournalEntry), func(obj model.Object) bool {
+		return cb(obj.(*model.JournalEntry)) //nolint:forcetypeassert
+	}); err != nil {
+		return fmt.Errorf("%w: %s", ErrJournalDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Next increments the number of the journal entries numbered by the transaction and
+// returns it, so the first call for the transaction returns 1.
+func (j *Journal) Next(ctx context.Context, txID string) (uint64, error) {
+	key := keyvalue.Key(keyvalue.Join(journalCountPrefix, txID))
+
+	var n uint64
+
+	raw, err := j.Object.DB.Get(ctx, key)
+	switch {
+	case errors.Is(err, keyvalue.ErrNotFound):
+	case err != nil:
+		return 0, fmt.Errorf("%w: %s", ErrJournalDatabase, err.Error())
+	default:
+		if n, err = strconv.ParseUint(string(raw), 10, 64); err != nil { //nolint:gomnd
+			return 0, fmt.Errorf("%w: %s", ErrJournalDatabase, err.Error())
+		}
+	}
+
+	n++
+
+	if err := j.Object.DB.Set(ctx, key, keyvalue.Value(strconv.FormatUint(n, 10))); err != nil { //nolint:gomnd
+		return 0, fmt.Errorf("%w: %s", ErrJournalDatabase, err.Error())
+	}
+
+	return n, nil
+}
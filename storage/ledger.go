@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrLedgerDatabase represents a generic error related to the database operations.
+var ErrLedgerDatabase = errors.New("ledger database error")
+
+// Keys of the ledger records.
+const (
+	ledgerEntryPrefix = "ledgerentry"
+	ledgerHeadKey     = "ledgerhead"
+
+	// ledgerSeqLayout formats sequence numbers in the entry keys, so that their lexical
+	// order matches the numeric one.
+	ledgerSeqLayout = "%020d"
+)
+
+// Ledger is a structure which encapsulates the keyvalue.DB to interact with the hash
+// chain of the accounting ledger. Entries are stored in the order of their sequence
+// numbers, and the last one is also stored as the head of the chain. Every append
+// writes the head, so transactions appending to the ledger are serialized.
+//
+// The head is read from the database only, so the services appending to the chain
+// in one transaction may keep their own instances as long as they share the database.
+// In the chaincode it must be a keyvalue/cache.KeyValueDB, otherwise the instances do
+// not see the entries appended by each other and fork the chain.
+//
+//go:generate ifacemaker -f ledger.go -o repository/ledger.go -i Ledger -s Ledger -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/ledger.go -destination repository/mock/mock_ledger.go
+type Ledger struct {
+	Object
+}
+
+// Head retrieves the last entry of the chain. If the chain is empty, nil is returned.
+func (l *Ledger) Head(ctx context.Context) (*model.LedgerEntry, error) {
+	head := new(model.LedgerEntry)
+	if err := l.Object.Load(ctx, model.ObjectQuery(ledgerHeadKey), head); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrLedgerDatabase, err.Error())
+	}
+
+	return head, nil
+}
+
+// Append stores the entry and makes it the head of the chain.
+func (l *Ledger) Append(ctx context.Context, e *model.LedgerEntry) error {
+	key := keyvalue.Join(ledgerEntryPrefix, fmt.Sprintf(ledgerSeqLayout, e.Seq))
+
+	if err := l.Object.Save(ctx, model.ObjectQuery(key), e); err != nil {
+		return fmt.Errorf("%w: %s", ErrLedgerDatabase, err.Error())
+	}
+
+	if err := l.Object.Save(ctx, model.ObjectQuery(ledgerHeadKey), e); err != nil {
+		return fmt.Errorf("%w: %s", ErrLedgerDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Iter iterates over the entries of the chain in the order of their sequence numbers.
+// Iteration stops if cb returns true.
+func (l *Ledger) Iter(ctx context.Context, cb func(e *model.LedgerEntry) (stop bool)) error {
+	if err := l.Object.Iter(ctx, model.ObjectQuery(ledgerEntryPrefix), new(model.LedgerEntry), func(obj model.Object) bool {
+		return cb(obj.(*model.LedgerEntry)) //nolint:forcetypeassert
+	}); err != nil {
+		return fmt.Errorf("%w: %s", ErrLedgerDatabase, err.Error())
+	}
+
+	return nil
+}
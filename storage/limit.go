@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrLimitDatabase represents a generic error related to the database operations.
+var ErrLimitDatabase = errors.New("limit database error")
+
+// Key prefixes of the limit records.
+const (
+	limitConfigPrefix      = "limit"
+	limitSpentPrefix       = "limitspent"
+	limitClassConfigPrefix = "limitclass"
+	limitClassSpentPrefix  = "limitclassspent"
+)
+
+// Limit is a structure which encapsulates the keyvalue.DB to interact with limit
+// configurations and amounts spent by addresses in database. The limits are set either
+// for a currency or for a currency class, whose limits apply to the total outflow in
+// all the currencies of the class.
+//
+//go:generate ifacemaker -f limit.go -o repository/limit.go -i Limit -s Limit -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/limit.go -destination repository/mock/mock_limit.go
+type Limit struct {
+	Object
+}
+
+// LoadConfig retrieves the limit configuration for given Address and Currency.
+// If no configuration is stored for the address, the default configuration of the
+// currency is returned. If there is none either, nil is returned.
+func (l *Limit) LoadConfig(
+	ctx context.Context,
+	addr model.Address,
+	curr model.Currency,
+) (*model.Limit, error) {
+	return l.loadConfig(ctx, limitConfigPrefix, string(curr), addr)
+}
+
+// SaveConfig saves the limit configuration for given Address and Currency.
+// An empty address sets the default configuration of the currency.
+func (l *Limit) SaveConfig(
+	ctx context.Context,
+	addr model.Address,
+	curr model.Currency,
+	limit *model.Limit,
+) error {
+	return l.saveConfig(ctx, limitConfigPrefix, string(curr), addr, limit)
+}
+
+// LoadSpent retrieves the amount spent by the Address in the Currency during the
+// period identified by the key. If no record is found, a zero value is returned.
+func (l *Limit) LoadSpent(
+	ctx context.Context,
+	addr model.Address,
+	curr model.Currency,
+	period string,
+) (*big.Int, error) {
+	return l.loadSpent(ctx, limitSpentPrefix, string(curr), addr, period)
+}
+
+// SaveSpent saves the amount spent by the Address in the Currency during the
+// period identified by the key.
+func (l *Limit) SaveSpent(
+	ctx context.Context,
+	addr model.Address,
+	curr model.Currency,
+	period string,
+	val *big.Int,
+) error {
+	return l.saveSpent(ctx, limitSpentPrefix, string(curr), addr, period, val)
+}
+
+// LoadClassConfig retrieves the limit configuration for given Address and currency
+// class. If no configuration is stored for the address, the default configuration of
+// the class is returned. If there is none either, nil is returned.
+func (l *Limit) LoadClassConfig(
+	ctx context.Context,
+	addr model.Address,
+	class model.CurrencyClass,
+) (*model.Limit, error) {
+	return l.loadConfig(ctx, limitClassConfigPrefix, string(class), addr)
+}
+
+// SaveClassConfig saves the limit configuration for given Address and currency class.
+// An empty address sets the default configuration of the class. The limits are expressed
+// in the minimal units of the class, see model.CurrencyClass.Precision.
+func (l *Limit) SaveClassConfig(
+	ctx context.Context,
+	addr model.Address,
+	class model.CurrencyClass,
+	limit *model.Limit,
+) error {
+	return l.saveConfig(ctx, limitClassConfigPrefix, string(class), addr, limit)
+}
+
+// LoadClassSpent retrieves the amount spent by the Address in all the currencies of
+// the class during the period identified by the key. If no record is found, a zero
+// value is returned.
+func (l *Limit) LoadClassSpent(
+	ctx context.Context,
+	addr model.Address,
+	class model.CurrencyClass,
+	period string,
+) (*big.Int, error) {
+	return l.loadSpent(ctx, limitClassSpentPrefix, string(class), addr, period)
+}
+
+// SaveClassSpent saves the amount spent by the Address in all the currencies of the
+// class during the period identified by the key.
+func (l *Limit) SaveClassSpent(
+	ctx context.Context,
+	addr model.Address,
+	class model.CurrencyClass,
+	period string,
+	val *big.Int,
+) error {
+	return l.saveSpent(ctx, limitClassSpentPrefix, string(class), addr, period, val)
+}
+
+// loadConfig retrieves the limit configuration of the address in the scope, falling
+// back to the default configuration of the scope.
+func (l *Limit) loadConfig(
+	ctx context.Context,
+	prefix, scope string,
+	addr model.Address,
+) (*model.Limit, error) {
+	for _, q := range []string{
+		keyvalue.Join(prefix, scope, string(addr)),
+		keyvalue.Join(prefix, scope),
+	} {
+		limit := new(model.Limit)
+
+		err := l.Object.Load(ctx, model.ObjectQuery(q), limit)
+		if err == nil {
+			return limit, nil
+		}
+
+		if !errors.Is(err, ErrObjectNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrLimitDatabase, err.Error())
+		}
+	}
+
+	return nil, nil //nolint:nilnil
+}
+
+// saveConfig saves the limit configuration of the address in the scope.
+func (l *Limit) saveConfig(
+	ctx context.Context,
+	prefix, scope string,
+	addr model.Address,
+	limit *model.Limit,
+) error {
+	if err := l.Object.Save(
+		ctx,
+		model.ObjectQuery(keyvalue.Join(prefix, scope, string(addr))),
+		limit,
+	); err != nil {
+		return fmt.Errorf("%w: %s", ErrLimitDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// loadSpent retrieves the amount spent by the address in the scope during the period.
+func (l *Limit) loadSpent(
+	ctx context.Context,
+	prefix, scope string,
+	addr model.Address,
+	period string,
+) (*big.Int, error) {
+	raw, err := l.Object.DB.Get(
+		ctx,
+		keyvalue.Key(keyvalue.Join(prefix, string(addr), scope, period)),
+	)
+	if err != nil {
+		if errors.Is(err, keyvalue.ErrNotFound) {
+			return new(big.Int), nil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrLimitDatabase, err.Error())
+	}
+
+	return new(big.Int).SetBytes(raw), nil
+}
+
+// saveSpent saves the amount spent by the address in the scope during the period.
+func (l *Limit) saveSpent(
+	ctx context.Context,
+	prefix, scope string,
+	addr model.Address,
+	period string,
+	val *big.Int,
+) error {
+	if err := l.Object.DB.Set(
+		ctx,
+		keyvalue.Key(keyvalue.Join(prefix, string(addr), scope, period)),
+		keyvalue.Value(val.Bytes()),
+	); err != nil {
+		return fmt.Errorf("%w: %s", ErrLimitDatabase, err.Error())
+	}
+
+	return nil
+}
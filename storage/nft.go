@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrNFTDatabase represents a generic error related to the database operations.
+var ErrNFTDatabase = errors.New("nft database error")
+
+// Keys of the non-fungible token records.
+const (
+	nftPrefix      = "nft"
+	nftOwnerPrefix = "nftowner"
+
+	// NFTPageLimit is the default size of a page of the tokens.
+	NFTPageLimit = 100
+)
+
+// NFT is a structure which encapsulates the keyvalue.DB to interact with non-fungible
+// tokens in database. The tokens are stored by collection, so the records of a
+// collection form its index, and an owner index refers to the tokens of every owner.
+//
+//go:generate ifacemaker -f nft.go -o repository/nft.go -i NFT -s NFT -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/nft.go -destination repository/mock/mock_nft.go
+type NFT struct {
+	Object
+}
+
+// Load retrieves the token of the collection. If no token is found, nil is returned.
+func (n *NFT) Load(ctx context.Context, collection, tokenID string) (*model.NFT, error) {
+	token := new(model.NFT)
+
+	err := n.Object.Load(ctx, model.ObjectQuery(n.key(collection, tokenID)), token)
+	if errors.Is(err, ErrObjectNotFound) {
+		return nil, nil //nolint:nilnil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+	}
+
+	return token, nil
+}
+
+// Save stores the token and moves it in the owner index from the previous owner to
+// the current one.
+func (n *NFT) Save(ctx context.Context, token *model.NFT) error {
+	prev, err := n.Load(ctx, token.Collection, token.TokenID)
+	if err != nil {
+		return err
+	}
+
+	key := n.key(token.Collection, token.TokenID)
+
+	if err := n.Object.Save(ctx, model.ObjectQuery(key), token); err != nil {
+		return fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+	}
+
+	if prev != nil && prev.Owner != token.Owner {
+		if err := n.Object.DB.Del(ctx, n.ownerKey(prev)); err != nil {
+			return fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+		}
+	}
+
+	if err := n.Object.DB.Set(ctx, n.ownerKey(token), keyvalue.Value(key)); err != nil {
+		return fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Delete removes the token and its record in the owner index.
+func (n *NFT) Delete(ctx context.Context, collection, tokenID string) error {
+	prev, err := n.Load(ctx, collection, tokenID)
+	if err != nil || prev == nil {
+		return err
+	}
+
+	key := n.key(collection, tokenID)
+
+	for _, k := range []keyvalue.Key{key, n.ownerKey(prev)} {
+		if err := n.Object.DB.Del(ctx, k); err != nil {
+			return fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ByOwner retrieves a page of the tokens of the owner in ascending order of their
+// collections and identifiers. The bookmark is the last token of the previous page in
+// the form "collection/tokenID".
+func (n *NFT) ByOwner(
+	ctx context.Context,
+	owner model.Address,
+	bookmark string,
+	limit int,
+) (*model.NFTPage, error) {
+	// nftowner/owner/collection/tokenID
+	prefix := keyvalue.Join(nftOwnerPrefix, string(owner))
+
+	page := new(model.NFTPage)
+	err := n.iter(ctx, prefix, bookmark, limit, page, func(v keyvalue.Value) (*model.NFT, error) {
+		token := new(model.NFT)
+		if err := n.Object.Load(ctx, model.ObjectQuery(v), token); err != nil {
+			return nil, err
+		}
+
+		return token, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ByCollection retrieves a page of the tokens of the collection in ascending order of
+// their identifiers. The bookmark is the identifier of the last token of the previous
+// page.
+func (n *NFT) ByCollection(
+	ctx context.Context,
+	collection, bookmark string,
+	limit int,
+) (*model.NFTPage, error) {
+	// nft/collection/tokenID
+	prefix := keyvalue.Join(nftPrefix, collection)
+
+	page := new(model.NFTPage)
+	err := n.iter(ctx, prefix, bookmark, limit, page, func(v keyvalue.Value) (*model.NFT, error) {
+		token := new(model.NFT)
+		if err := token.UnmarshalBinary(v); err != nil {
+			return nil, err
+		}
+
+		return token, token.Validate()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// iter fills the page with the tokens decoded from the records under the prefix, which
+// follow the bookmark. The records are read from the key of the bookmark in pages of
+// the limit, if the database supports it, and the keys are compared by keyvalue.Less,
+// so the bookmarked token may be removed between the pages.
+func (n *NFT) iter(
+	ctx context.Context,
+	prefix, bookmark string,
+	limit int,
+	page *model.NFTPage,
+	decode func(v keyvalue.Value) (*model.NFT, error),
+) error {
+	if limit <= 0 {
+		limit = NFTPageLimit
+	}
+
+	var start keyvalue.Key
+	if bookmark != "" {
+		start = keyvalue.Key(keyvalue.Join(prefix, bookmark))
+	}
+
+	iter, err := keyvalue.IterFrom(ctx, n.Object.DB, keyvalue.Prefix(prefix), start, limit+1)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	var last string
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+		}
+
+		// skip the records of other owners or collections sharing the same beginning
+		suffix, ok := strings.CutPrefix(string(k), prefix+keyvalue.KeySeparator)
+		if !ok {
+			continue
+		}
+
+		if start != "" && !keyvalue.Less(start, k) {
+			continue
+		}
+
+		if len(page.Items) == limit {
+			page.Bookmark = last
+			break
+		}
+
+		token, err := decode(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrNFTDatabase, err.Error())
+		}
+
+		page.Items = append(page.Items, token)
+		last = suffix
+	}
+
+	return nil
+}
+
+// key creates a key of the token.
+// example: "nft/collection/tokenID"
+func (n *NFT) key(collection, tokenID string) keyvalue.Key {
+	return keyvalue.Key(keyvalue.Join(nftPrefix, collection, tokenID))
+}
+
+// ownerKey creates a key of the token in the owner index.
+// example: "nftowner/owner/collection/tokenID"
+func (n *NFT) ownerKey(token *model.NFT) keyvalue.Key {
+	return keyvalue.Key(keyvalue.Join(nftOwnerPrefix, string(token.Owner), token.Collection, token.TokenID))
+}
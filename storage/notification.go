@@ -2,18 +2,53 @@ package storage
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/anoideaopen/token/keyvalue"
 	"github.com/anoideaopen/token/model"
 )
 
-// ErrNotificationDatabase represents a generic error related to the database operations.
-var ErrNotificationDatabase = errors.New("notofocation database error")
+// Notification storage errors.
+var (
+	// ErrNotificationDatabase represents a generic error related to the database operations.
+	ErrNotificationDatabase = errors.New("notofocation database error")
+
+	// ErrNotificationBookmark is returned when the bookmark of a query does not refer
+	// to a key of the queried index.
+	ErrNotificationBookmark = errors.New("unknown bookmark")
+)
+
+// Key prefixes of the notification indexes.
+const (
+	notificationTimeIndexPrefix    = "notiftime"
+	notificationAddressIndexPrefix = "notifaddr"
+	notificationHistoryPrefix      = "history"
+)
+
+// Notification query settings.
+const (
+	// NotificationPageLimit is the page size used when the query does not set it.
+	NotificationPageLimit = 100
+
+	// notificationTimeLayout formats timestamps in the index keys, so that their lexical
+	// order matches the chronological one.
+	notificationTimeLayout = "2006-01-02T15:04:05.000000000Z"
+
+	// notificationHistoryLayout formats the inverted timestamps in the history keys.
+	notificationHistoryLayout = "%019d"
+)
 
 // Notification is a structure which encapsulates the keyvalue.DB to interact with
-// notification structures in database.
+// notification structures in database. Besides the notifications themselves it
+// maintains the indexes of notifications by the time of the transaction and by the
+// addresses whose balances were updated, and the history of the balance updates of
+// every address. The time is taken from the transaction information of the context.
 //
 //go:generate ifacemaker -f notification.go -o repository/notification.go -i Notification -s Notification -p repository -y "Repository describes methods, implemented by the storage package."
 //go:generate mockgen -package mock -source repository/notification.go -destination repository/mock/mock_notification.go
@@ -26,11 +61,513 @@ func (n *Notification) SaveBalancesUpdate(
 	ctx context.Context,
 	bu model.Notification[model.BalancesUpdate],
 ) error {
-	if err := n.Object.Save(ctx, model.ObjectQuery(
-		keyvalue.Join(bu.Type, bu.ID),
-	), &bu); err != nil {
+	return n.save(ctx, bu.Type, bu.ID, &bu, bu.Body)
+}
+
+// SaveForcedTransfer stores forced transfer record to the notification database.
+func (n *Notification) SaveForcedTransfer(
+	ctx context.Context,
+	ft model.Notification[model.ForcedTransfer],
+) error {
+	return n.save(ctx, ft.Type, ft.ID, &ft, ft.Body)
+}
+
+// SaveReversal stores reversal record to the notification database.
+func (n *Notification) SaveReversal(
+	ctx context.Context,
+	r model.Notification[model.Reversal],
+) error {
+	return n.save(ctx, r.Type, r.ID, &r, r.Body)
+}
+
+// LoadBalancesUpdate retrieves notification record of given type and identifier from the
+// notification database. If no record is found, nil is returned.
+func (n *Notification) LoadBalancesUpdate(
+	ctx context.Context,
+	typ, id string,
+) (*model.Notification[model.BalancesUpdate], error) {
+	bu := new(model.Notification[model.BalancesUpdate])
+	if err := n.load(ctx, typ, id, bu); err != nil || bu.ID == "" {
+		return nil, err
+	}
+
+	return bu, nil
+}
+
+// LoadReversal retrieves reversal record with given identifier from the notification
+// database. If no record is found, nil is returned.
+func (n *Notification) LoadReversal(
+	ctx context.Context,
+	id string,
+) (*model.Notification[model.Reversal], error) {
+	r := new(model.Notification[model.Reversal])
+	if err := n.load(ctx, model.NotificationTypeReversal, id, r); err != nil || r.ID == "" {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// SaveRaw stores notification record of a type registered by model.RegisterNotification.
+// The body is decoded and validated according to the registered type. If it
+// implements model.Addresser, the record is indexed by its addresses, and if it
+// implements model.BalanceUpdater, the updates are added to the address history.
+func (n *Notification) SaveRaw(ctx context.Context, raw *model.RawNotification) error {
+	body, err := model.DecodeNotificationBody(raw)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	return n.save(ctx, raw.Type, raw.ID, raw, body)
+}
+
+// Load retrieves notification record of given type and identifier with the undecoded
+// body. If no record is found, nil is returned.
+func (n *Notification) Load(ctx context.Context, typ, id string) (*model.RawNotification, error) {
+	raw := new(model.RawNotification)
+	if err := n.load(ctx, typ, id, raw); err != nil || raw.ID == "" {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// Iter iterates over all notification records of given type in the order of their
+// identifiers. Iteration stops if cb returns true.
+func (n *Notification) Iter(
+	ctx context.Context,
+	typ string,
+	cb func(raw *model.RawNotification) (stop bool),
+) error {
+	if err := n.Object.Iter(ctx, model.ObjectQuery(typ), new(model.RawNotification), func(obj model.Object) bool {
+		raw := obj.(*model.RawNotification) //nolint:forcetypeassert
+
+		// skip the records of other types sharing the prefix
+		if raw.Type != typ {
+			return false
+		}
+
+		return cb(raw)
+	}); err != nil {
 		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
 	}
 
 	return nil
 }
+
+// Query retrieves a page of notification records matching the query. Queries by
+// address use the address index, queries by time range use the time index, and
+// queries only by type iterate over the records of the type.
+func (n *Notification) Query(
+	ctx context.Context,
+	q model.NotificationQuery,
+) (*model.NotificationPage, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = NotificationPageLimit
+	}
+
+	var (
+		prefix string
+		match  func(parts []string) bool
+
+		// position of the timestamp in the keys of the time-ordered indexes
+		at = -1
+	)
+
+	inRange := func(ts string) bool {
+		return (q.From.IsZero() || ts >= n.timestamp(q.From)) &&
+			(q.To.IsZero() || ts < n.timestamp(q.To))
+	}
+
+	switch {
+	case q.Address != "":
+		// notifaddr/address/timestamp/type/id
+		prefix, at = keyvalue.Join(notificationAddressIndexPrefix, string(q.Address)), 2
+		match = func(parts []string) bool {
+			return len(parts) == 5 && inRange(parts[2]) && //nolint:gomnd
+				(q.Type == "" || parts[3] == q.Type)
+		}
+	case !q.From.IsZero() || !q.To.IsZero() || q.Type == "":
+		// notiftime/timestamp/type/id
+		prefix, at = notificationTimeIndexPrefix, 1
+		match = func(parts []string) bool {
+			return len(parts) == 4 && inRange(parts[1]) && //nolint:gomnd
+				(q.Type == "" || parts[2] == q.Type)
+		}
+	default:
+		// type/id
+		prefix = q.Type
+		match = func(parts []string) bool {
+			return len(parts) == 2 //nolint:gomnd
+		}
+	}
+
+	// the time-ordered indexes are read from the beginning of the time range
+	var start string
+	if at != -1 && !q.From.IsZero() {
+		start = keyvalue.Join(prefix, n.timestamp(q.From))
+	}
+
+	iter, err := n.page(ctx, prefix, start, q.Bookmark, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var (
+		page = new(model.NotificationPage)
+		refs []string
+		last string
+	)
+
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+		}
+
+		key := string(k)
+
+		// skip the keys of other prefixes sharing the same beginning
+		if !strings.HasPrefix(key, prefix+keyvalue.KeySeparator) {
+			continue
+		}
+
+		parts := strings.Split(key, keyvalue.KeySeparator)
+
+		// the rest of the time-ordered index is past the end of the time range
+		if at != -1 && !q.To.IsZero() && len(parts) > at && parts[at] >= n.timestamp(q.To) {
+			break
+		}
+
+		if !match(parts) {
+			continue
+		}
+
+		if len(refs) == limit {
+			page.Bookmark = last
+			break
+		}
+
+		// index entries reference the record, while the records of a type are
+		// referenced by their own keys
+		ref := string(v)
+		if prefix == q.Type {
+			ref = key
+		}
+
+		refs = append(refs, ref)
+		last = key
+	}
+
+	for _, ref := range refs {
+		typ, id, _ := strings.Cut(ref, keyvalue.KeySeparator)
+
+		raw, err := n.Load(ctx, typ, id)
+		if err != nil {
+			return nil, err
+		}
+
+		if raw != nil {
+			page.Items = append(page.Items, raw)
+		}
+	}
+
+	return page, nil
+}
+
+// save stores notification record and adds it to the indexes by the time of the
+// transaction and by the addresses, and the balance updates of the body to the
+// history of their addresses.
+func (n *Notification) save(
+	ctx context.Context,
+	typ, id string,
+	obj model.Object,
+	body any,
+) error {
+	ref := keyvalue.Join(typ, id)
+
+	if err := n.Object.Save(ctx, model.ObjectQuery(ref), obj); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	var ts time.Time
+	if tx, ok := model.TransactionFromContext(ctx); ok {
+		ts = tx.Timestamp
+	}
+
+	keys := []string{
+		keyvalue.Join(notificationTimeIndexPrefix, n.timestamp(ts), typ, id),
+	}
+
+	var addrs []model.Address
+	if a, ok := body.(model.Addresser); ok {
+		addrs = a.Addresses()
+	}
+
+	for _, addr := range addrs {
+		keys = append(keys, keyvalue.Join(
+			notificationAddressIndexPrefix,
+			string(addr),
+			n.timestamp(ts),
+			typ,
+			id,
+		))
+	}
+
+	for _, key := range keys {
+		if err := n.Object.DB.Set(ctx, keyvalue.Key(key), keyvalue.Value(ref)); err != nil {
+			return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+		}
+	}
+
+	if u, ok := body.(model.BalanceUpdater); ok {
+		return n.saveHistory(ctx, typ, id, ts, u.BalanceUpdates())
+	}
+
+	return nil
+}
+
+// saveHistory adds the balance updates to the history of their addresses. The
+// timestamps of the updates are preferred to the one of the transaction.
+func (n *Notification) saveHistory(
+	ctx context.Context,
+	typ, id string,
+	ts time.Time,
+	updates model.BalancesUpdate,
+) error {
+	for i, u := range updates {
+		at := u.Timestamp
+		if at.IsZero() {
+			at = ts
+		}
+
+		data, err := json.Marshal(model.HistoryEntry{Type: typ, ID: id, Update: u})
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+		}
+
+		key := keyvalue.Join(
+			notificationHistoryPrefix,
+			string(u.Address),
+			n.newestFirst(at),
+			typ,
+			id,
+			strconv.Itoa(i),
+		)
+
+		if err := n.Object.DB.Set(ctx, keyvalue.Key(key), data); err != nil {
+			return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+		}
+	}
+
+	return nil
+}
+
+// History retrieves a page of the balance updates of the address, newest first.
+func (n *Notification) History(ctx context.Context, q model.HistoryQuery) (*model.HistoryPage, error) {
+	limit := q.Limit
+	if limit == 0 {
+		limit = NotificationPageLimit
+	}
+
+	// history/address/time/type/id/index
+	prefix := keyvalue.Join(notificationHistoryPrefix, string(q.Address))
+
+	// the keys of the updates made before the time follow the key of the moment
+	// preceding it, as the time is inverted
+	var start string
+	if !q.Before.IsZero() {
+		start = keyvalue.Join(prefix, n.newestFirst(q.Before.Add(-time.Nanosecond)))
+	}
+
+	iter, err := n.page(ctx, prefix, start, q.Bookmark, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var (
+		page = new(model.HistoryPage)
+		last string
+	)
+
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+		}
+
+		key := string(k)
+
+		// skip the keys of other addresses sharing the same beginning
+		if !strings.HasPrefix(key, prefix+keyvalue.KeySeparator) {
+			continue
+		}
+
+		entry := new(model.HistoryEntry)
+		if err := json.Unmarshal(v, entry); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+		}
+
+		if q.Currency != "" && entry.Update.Currency != q.Currency {
+			continue
+		}
+
+		if len(page.Items) == limit {
+			page.Bookmark = last
+			break
+		}
+
+		page.Items = append(page.Items, entry)
+		last = key
+	}
+
+	return page, nil
+}
+
+// page returns an iterator over the keys with the prefix, which continues the previous
+// page from its bookmark. The bookmark is the last key of the previous page, so it is
+// read first and skipped. Without a bookmark the keys are read from the start key, or
+// from the beginning of the prefix if it is empty. The keys are read in pages of the
+// size, if the database supports it.
+func (n *Notification) page(
+	ctx context.Context,
+	prefix, start, bookmark string,
+	size int,
+) (keyvalue.Iterator, error) {
+	if bookmark != "" {
+		if !strings.HasPrefix(bookmark, prefix+keyvalue.KeySeparator) {
+			return nil, fmt.Errorf("%w: %s", ErrNotificationBookmark, bookmark)
+		}
+
+		start = bookmark
+	}
+
+	iter, err := keyvalue.IterFrom(ctx, n.Object.DB, keyvalue.Prefix(prefix), keyvalue.Key(start), size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	if bookmark == "" {
+		return iter, nil
+	}
+
+	if !iter.HasNext() {
+		_ = iter.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotificationBookmark, bookmark)
+	}
+
+	k, _, err := iter.Next()
+	if err != nil {
+		_ = iter.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	if string(k) != bookmark {
+		_ = iter.Close()
+		return nil, fmt.Errorf("%w: %s", ErrNotificationBookmark, bookmark)
+	}
+
+	return iter, nil
+}
+
+// newestFirst formats the time for the index keys, so that their lexical order is
+// the reverse chronological one.
+func (n *Notification) newestFirst(ts time.Time) string {
+	var nanos int64
+	if ts.After(time.Unix(0, 0)) {
+		nanos = ts.UnixNano()
+	}
+
+	return fmt.Sprintf(notificationHistoryLayout, math.MaxInt64-nanos)
+}
+
+// timestamp formats the time for the index keys.
+func (n *Notification) timestamp(ts time.Time) string {
+	return ts.UTC().Format(notificationTimeLayout)
+}
+
+// load retrieves notification record into obj. Missing records leave obj untouched.
+func (n *Notification) load(ctx context.Context, typ, id string, obj model.Object) error {
+	if err := n.Object.Load(ctx, model.ObjectQuery(
+		keyvalue.Join(typ, id),
+	), obj); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	return nil
+}
+
+// NotificationDB is the part of the notification repository used by the generic
+// functions, which store and retrieve notifications of any registered type. It is
+// implemented by Notification and by the repository interface.
+type NotificationDB interface {
+	SaveRaw(ctx context.Context, raw *model.RawNotification) error
+	Load(ctx context.Context, typ, id string) (*model.RawNotification, error)
+	Iter(ctx context.Context, typ string, cb func(raw *model.RawNotification) (stop bool)) error
+}
+
+// SaveNotification stores notification record, whose type is registered with the body
+// of type T by model.RegisterNotification.
+func SaveNotification[T any](ctx context.Context, db NotificationDB, n model.Notification[T]) error {
+	raw, err := model.EncodeNotification(n)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	return db.SaveRaw(ctx, raw)
+}
+
+// LoadNotification retrieves notification record of given type and identifier with
+// the body of type T. If no record is found, nil is returned.
+func LoadNotification[T any](
+	ctx context.Context,
+	db NotificationDB,
+	typ, id string,
+) (*model.Notification[T], error) {
+	raw, err := db.Load(ctx, typ, id)
+	if err != nil || raw == nil {
+		return nil, err
+	}
+
+	n, err := model.DecodeNotification[T](raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	return n, nil
+}
+
+// IterNotifications iterates over all notification records of given type with the body
+// of type T. Iteration stops if cb returns true.
+func IterNotifications[T any](
+	ctx context.Context,
+	db NotificationDB,
+	typ string,
+	cb func(n *model.Notification[T]) (stop bool),
+) error {
+	if err := model.CheckNotificationType[T](typ); err != nil {
+		return fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+	}
+
+	var decodeErr error
+	if err := db.Iter(ctx, typ, func(raw *model.RawNotification) bool {
+		n, err := model.DecodeNotification[T](raw)
+		if err != nil {
+			decodeErr = fmt.Errorf("%w: %s", ErrNotificationDatabase, err)
+			return true
+		}
+
+		return cb(n)
+	}); err != nil {
+		return err
+	}
+
+	return decodeErr
+}
@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotification_Query(t *testing.T) {
+	n := &Notification{Object: Object{DB: new(inmem.KeyValueDB)}}
+
+	var (
+		a    = model.Address("0x123")
+		b    = model.Address("0x456")
+		day  = time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+		txAt = func(id string, hour int) context.Context {
+			return model.ContextWithTransaction(context.Background(), model.Transaction{
+				ID:        id,
+				Timestamp: day.Add(time.Duration(hour) * time.Hour),
+			})
+		}
+		update = func(addr model.Address) model.BalanceUpdate {
+			return model.BalanceUpdate{
+				Address:    addr,
+				Account:    model.AccountToken,
+				Currency:   "USD",
+				ValueDelta: model.NewAmount(nil, 0),
+			}
+		}
+	)
+
+	assert.NoError(t, n.SaveBalancesUpdate(txAt("tx1", 1), model.Notification[model.BalancesUpdate]{
+		ID:   "tx1",
+		Type: model.NotificationTypeBalancesUpdate,
+		Body: model.BalancesUpdate{update(a), update(b)},
+	}))
+	assert.NoError(t, n.SaveBalancesUpdate(txAt("tx2", 2), model.Notification[model.BalancesUpdate]{
+		ID:   "tx2",
+		Type: model.NotificationTypeBalancesUpdate,
+		Body: model.BalancesUpdate{update(b)},
+	}))
+	assert.NoError(t, n.SaveReversal(txAt("tx3", 3), model.Notification[model.Reversal]{
+		ID:   "BalancesUpdate:tx1",
+		Type: model.NotificationTypeReversal,
+		Body: model.Reversal{
+			OriginalType: model.NotificationTypeBalancesUpdate,
+			OriginalID:   "tx1",
+			Updates:      model.BalancesUpdate{update(a), update(b)},
+		},
+	}))
+
+	ids := func(page *model.NotificationPage) (out []string) {
+		for _, item := range page.Items {
+			out = append(out, item.ID)
+		}
+		return out
+	}
+
+	raw, err := n.Load(context.Background(), model.NotificationTypeBalancesUpdate, "tx2")
+	assert.NoError(t, err)
+	bu, err := model.DecodeNotification[model.BalancesUpdate](raw)
+	assert.NoError(t, err)
+	assert.Equal(t, b, bu.Body[0].Address)
+
+	raw, err = n.Load(context.Background(), model.NotificationTypeBalancesUpdate, "tx4")
+	assert.NoError(t, err)
+	assert.Nil(t, raw)
+
+	// by type with pagination
+	page, err := n.Query(context.Background(), model.NotificationQuery{
+		Type:  model.NotificationTypeBalancesUpdate,
+		Limit: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1"}, ids(page))
+	assert.NotEmpty(t, page.Bookmark)
+
+	page, err = n.Query(context.Background(), model.NotificationQuery{
+		Type:     model.NotificationTypeBalancesUpdate,
+		Bookmark: page.Bookmark,
+		Limit:    1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx2"}, ids(page))
+	assert.Empty(t, page.Bookmark)
+
+	// the page of an unknown bookmark is not silently empty
+	for _, bookmark := range []string{"BalancesUpdate/tx0", "notiftime/x"} {
+		_, err = n.Query(context.Background(), model.NotificationQuery{
+			Type:     model.NotificationTypeBalancesUpdate,
+			Bookmark: bookmark,
+		})
+		assert.ErrorIs(t, err, ErrNotificationBookmark)
+	}
+
+	// by address
+	page, err = n.Query(context.Background(), model.NotificationQuery{Address: a})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1", "BalancesUpdate:tx1"}, ids(page))
+
+	// by address and time range
+	page, err = n.Query(context.Background(), model.NotificationQuery{
+		Address: b,
+		From:    day.Add(2 * time.Hour),
+		To:      day.Add(3 * time.Hour),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx2"}, ids(page))
+
+	// by time range and type
+	page, err = n.Query(context.Background(), model.NotificationQuery{
+		Type: model.NotificationTypeReversal,
+		From: day,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"BalancesUpdate:tx1"}, ids(page))
+}
+
+type testFee struct {
+	Payer  model.Address `validate:"required"`
+	Amount string        `validate:"required"`
+}
+
+func (f testFee) Addresses() []model.Address {
+	return []model.Address{f.Payer}
+}
+
+func TestNotification_Generic(t *testing.T) {
+	const typ = "TestFee"
+
+	err := model.RegisterNotification(typ, func(f testFee) error {
+		return model.NewValidator().Struct(f)
+	})
+	if !errors.Is(err, model.ErrNotificationRegistered) {
+		assert.NoError(t, err)
+	}
+
+	var (
+		ctx = context.Background()
+		n   = &Notification{Object: Object{DB: new(inmem.KeyValueDB)}}
+		fee = model.Notification[testFee]{
+			ID:   "fee1",
+			Type: typ,
+			Body: testFee{Payer: "0x123", Amount: "10"},
+		}
+	)
+
+	assert.NoError(t, SaveNotification(ctx, n, fee))
+	assert.Error(t, SaveNotification(ctx, n, model.Notification[testFee]{ID: "fee2", Type: typ, Body: testFee{Payer: "0x123"}}))
+	assert.ErrorIs(t, SaveNotification(ctx, n, model.Notification[string]{ID: "fee3", Type: typ, Body: "10"}), ErrNotificationDatabase)
+
+	got, err := LoadNotification[testFee](ctx, n, typ, "fee1")
+	assert.NoError(t, err)
+	assert.Equal(t, &fee, got)
+
+	var all []*model.Notification[testFee]
+	assert.NoError(t, IterNotifications(ctx, n, typ, func(f *model.Notification[testFee]) bool {
+		all = append(all, f)
+		return false
+	}))
+	assert.Equal(t, []*model.Notification[testFee]{&fee}, all)
+
+	page, err := n.Query(ctx, model.NotificationQuery{Address: "0x123"})
+	assert.NoError(t, err)
+	assert.Len(t, page.Items, 1)
+
+	_, err = LoadNotification[model.Reversal](ctx, n, typ, "fee1")
+	assert.ErrorIs(t, err, ErrNotificationDatabase)
+}
+
+func TestNotification_History(t *testing.T) {
+	n := &Notification{Object: Object{DB: new(inmem.KeyValueDB)}}
+
+	var (
+		a   = model.Address("0x123")
+		b   = model.Address("0x1234")
+		day = time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+	)
+
+	for i, curr := range []model.Currency{"USD", "EUR", "USD"} {
+		ts := day.Add(time.Duration(i) * time.Hour)
+		ctx := model.ContextWithTransaction(context.Background(), model.Transaction{ID: "tx", Timestamp: ts})
+		id := fmt.Sprintf("tx%d", i+1)
+
+		assert.NoError(t, n.SaveBalancesUpdate(ctx, model.Notification[model.BalancesUpdate]{
+			ID:   id,
+			Type: model.NotificationTypeBalancesUpdate,
+			Body: model.BalancesUpdate{
+				{Address: a, Account: model.AccountToken, Currency: curr, ValueDelta: model.NewAmount(nil, 0), TxID: id},
+				{Address: b, Account: model.AccountToken, Currency: curr, ValueDelta: model.NewAmount(nil, 0), TxID: id},
+			},
+		}))
+	}
+
+	txIDs := func(page *model.HistoryPage) (out []string) {
+		for _, e := range page.Items {
+			assert.Equal(t, e.ID, e.Update.TxID)
+			out = append(out, e.Update.TxID)
+		}
+		return out
+	}
+
+	page, err := n.History(context.Background(), model.HistoryQuery{Address: a, Limit: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx3", "tx2"}, txIDs(page))
+	assert.Equal(t, a, page.Items[0].Update.Address)
+
+	page, err = n.History(context.Background(), model.HistoryQuery{Address: a, Limit: 2, Bookmark: page.Bookmark})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx1"}, txIDs(page))
+	assert.Empty(t, page.Bookmark)
+
+	_, err = n.History(context.Background(), model.HistoryQuery{Address: a, Bookmark: "history/" + string(a) + "/0"})
+	assert.ErrorIs(t, err, ErrNotificationBookmark)
+
+	page, err = n.History(context.Background(), model.HistoryQuery{Address: b, Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tx3", "tx1"}, txIDs(page))
+	assert.Equal(t, b, page.Items[1].Update.Address)
+}
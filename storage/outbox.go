@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrOutboxDatabase represents a generic error related to the database operations.
+var ErrOutboxDatabase = errors.New("outbox database error")
+
+// Keys of the outbox records.
+const (
+	outboxEntryPrefix    = "outboxentry"
+	outboxConsumerPrefix = "outboxconsumer"
+	outboxLastKey        = "outboxlast"
+	outboxPrunedKey      = "outboxpruned"
+
+	// outboxSeqLayout formats sequence numbers in the entry keys, so that their lexical
+	// order matches the numeric one.
+	outboxSeqLayout = "%020d"
+)
+
+// Outbox is a structure which encapsulates the keyvalue.DB to interact with the
+// notification outbox in database. Entries are stored in the order of their sequence
+// numbers, and the last one is stored separately, so the sequence continues after the
+// entries are pruned. Every append writes the last entry, so transactions appending to
+// the outbox are serialized. The entries are pruned in the order of their sequence
+// numbers and the last pruned one is stored too, so the entries are read by their keys
+// from the first one not pruned without iterating over the database.
+//
+//go:generate ifacemaker -f outbox.go -o repository/outbox.go -i Outbox -s Outbox -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/outbox.go -destination repository/mock/mock_outbox.go
+type Outbox struct {
+	Object
+}
+
+// Last retrieves the sequence number of the last appended entry, including the pruned
+// ones. If nothing was appended, zero is returned.
+func (o *Outbox) Last(ctx context.Context) (uint64, error) {
+	last := new(model.OutboxEntry)
+	if err := o.Object.Load(ctx, model.ObjectQuery(outboxLastKey), last); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return last.Seq, nil
+}
+
+// Append stores the entry and makes it the last one.
+func (o *Outbox) Append(ctx context.Context, e *model.OutboxEntry) error {
+	if err := o.Object.Save(ctx, o.entry(e.Seq), e); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	if err := o.Object.Save(ctx, model.ObjectQuery(outboxLastKey), e); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Pruned retrieves the sequence number of the last pruned entry. If nothing was
+// pruned, zero is returned.
+func (o *Outbox) Pruned(ctx context.Context) (uint64, error) {
+	pruned := new(model.OutboxEntry)
+	if err := o.Object.Load(ctx, model.ObjectQuery(outboxPrunedKey), pruned); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return 0, nil
+		}
+
+		return 0, fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return pruned.Seq, nil
+}
+
+// SavePruned stores the entry as the last pruned one.
+func (o *Outbox) SavePruned(ctx context.Context, e *model.OutboxEntry) error {
+	if err := o.Object.Save(ctx, model.ObjectQuery(outboxPrunedKey), e); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// List retrieves up to limit entries with sequence numbers greater than after, in
+// the order of their sequence numbers. The entries are read by their keys starting
+// after the last pruned one, up to the last appended one.
+func (o *Outbox) List(ctx context.Context, after uint64, limit int) ([]*model.OutboxEntry, error) {
+	pruned, err := o.Pruned(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*model.OutboxEntry
+
+	for seq := max(after, pruned) + 1; len(out) < limit; seq++ {
+		e := new(model.OutboxEntry)
+		if err := o.Object.Load(ctx, o.entry(seq), e); err != nil {
+			if errors.Is(err, ErrObjectNotFound) {
+				break
+			}
+
+			return nil, fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+		}
+
+		out = append(out, e)
+	}
+
+	return out, nil
+}
+
+// Delete removes the entry with given sequence number.
+func (o *Outbox) Delete(ctx context.Context, seq uint64) error {
+	if err := o.Object.Delete(ctx, o.entry(seq)); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// LoadConsumer retrieves the consumer by its name. If no consumer is found, nil is
+// returned.
+func (o *Outbox) LoadConsumer(ctx context.Context, name string) (*model.OutboxConsumer, error) {
+	c := new(model.OutboxConsumer)
+	if err := o.Object.Load(ctx, o.consumer(name), c); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return c, nil
+}
+
+// SaveConsumer stores the consumer with its cursor.
+func (o *Outbox) SaveConsumer(ctx context.Context, c *model.OutboxConsumer) error {
+	if err := o.Object.Save(ctx, o.consumer(c.Name), c); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// DeleteConsumer removes the consumer.
+func (o *Outbox) DeleteConsumer(ctx context.Context, name string) error {
+	if err := o.Object.Delete(ctx, o.consumer(name)); err != nil {
+		return fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return nil
+}
+
+// Consumers retrieves all the registered consumers.
+func (o *Outbox) Consumers(ctx context.Context) ([]*model.OutboxConsumer, error) {
+	var out []*model.OutboxConsumer
+
+	if err := o.Object.Iter(ctx, model.ObjectQuery(outboxConsumerPrefix), new(model.OutboxConsumer), func(obj model.Object) bool {
+		out = append(out, obj.(*model.OutboxConsumer)) //nolint:forcetypeassert
+		return false
+	}); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrOutboxDatabase, err.Error())
+	}
+
+	return out, nil
+}
+
+// entry creates a key of the outbox entry.
+// example: "outboxentry/00000000000000000001"
+func (o *Outbox) entry(seq uint64) model.ObjectQuery {
+	return model.ObjectQuery(keyvalue.Join(outboxEntryPrefix, fmt.Sprintf(outboxSeqLayout, seq)))
+}
+
+// consumer creates a key of the outbox consumer.
+// example: "outboxconsumer/name"
+func (o *Outbox) consumer(name string) model.ObjectQuery {
+	return model.ObjectQuery(keyvalue.Join(outboxConsumerPrefix, name))
+}
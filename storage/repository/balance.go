@@ -5,6 +5,7 @@ package repository
 import (
 	"context"
 	"math/big"
+	"time"
 
 	"github.com/anoideaopen/token/model"
 )
@@ -14,9 +15,49 @@ type Balance interface {
 	// Load retrieves the balance from the database for given BalanceType, Address, and Currency.
 	// If no record is found, a zero value is returned.
 	Load(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*big.Int, error)
+	// LoadTouched retrieves the balance like Load together with the timestamp of the
+	// transaction which saved it last. The timestamp is zero if it is not stored.
+	LoadTouched(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*big.Int, time.Time, error)
 	// Save saves the balance to the database for given BalanceType, Address, and Currency.
+	// The value is stored in the signed encoding, so negative balances are preserved. If
+	// the context carries the timestamp of the transaction, it is stored with the value.
 	Save(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, val *big.Int) error
+	// Credit increases the balance for given BalanceType, Address, and Currency. In the
+	// accumulator mode it writes a delta record unique for the transaction without
+	// reading the balance, otherwise the balance is loaded and saved.
+	Credit(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, val *big.Int) error
+	// Compact merges the delta records of the balance for given BalanceType, Address,
+	// and Currency into the balance itself. It does nothing outside the accumulator mode.
+	Compact(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) error
+	// Accumulates reports whether the accumulator mode is enabled, so Credit does not
+	// read the balance.
+	Accumulates() bool
+	// LoadAt retrieves the balance for given BalanceType, Address, and Currency as of the
+	// snapshot with given sequence number. Without the snapshots the current balance is
+	// returned.
+	LoadAt(ctx context.Context, seq uint64, addr model.Address, acc model.Account, curr model.Currency) (*big.Int, error)
 	// List retrieves all balances from the database for given BalanceType and Address,
 	// returning them as a map where the key is the currency.
 	List(ctx context.Context, addr model.Address, acc model.Account) (map[model.Currency]*big.Int, error)
+	// Portfolio retrieves all balances of the Address on every account declared in the
+	// model account registry, returning them as a map where the keys are the account and
+	// the currency. The balance keys start with the account, so every account is scanned
+	// separately.
+	Portfolio(ctx context.Context, addr model.Address) (map[model.Account]map[model.Currency]*big.Int, error)
+	// Holders retrieves the addresses having a balance record of given BalanceType and
+	// Currency. The addresses up to and including the bookmark are skipped, and at most
+	// limit addresses are returned unless the limit is zero. With the registry set, the
+	// addresses are read from it bucket by bucket, see Holder.Addresses, so a batch does
+	// not read the preceding ones. Otherwise all the balances of the BalanceType are
+	// scanned and the addresses are returned in ascending order.
+	Holders(ctx context.Context, acc model.Account, curr model.Currency, bookmark model.Address, limit int) ([]model.Address, error)
+	// Register adds the balances of the addresses for given BalanceType and Currency to
+	// the registry, which must be set. It is used to backfill the registry with the
+	// balances saved before it was set, the addresses are listed by the caller, e.g. with
+	// Holders of a Balance without the registry in a read-only query, and registered in
+	// batches. Registering an address again does not change the registry.
+	Register(ctx context.Context, addrs []model.Address, acc model.Account, curr model.Currency) error
+	// Supply retrieves the total of the positive balances of given BalanceType and
+	// Currency maintained by the registry, which must be set.
+	Supply(ctx context.Context, acc model.Account, curr model.Currency) (*big.Int, error)
 }
@@ -8,6 +8,7 @@ import (
 	context "context"
 	big "math/big"
 	reflect "reflect"
+	time "time"
 
 	model "github.com/anoideaopen/token/model"
 	gomock "go.uber.org/mock/gomock"
@@ -36,6 +37,63 @@ func (m *MockBalance) EXPECT() *MockBalanceMockRecorder {
 	return m.recorder
 }
 
+// Accumulates mocks base method.
+func (m *MockBalance) Accumulates() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Accumulates")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Accumulates indicates an expected call of Accumulates.
+func (mr *MockBalanceMockRecorder) Accumulates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accumulates", reflect.TypeOf((*MockBalance)(nil).Accumulates))
+}
+
+// Compact mocks base method.
+func (m *MockBalance) Compact(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compact", ctx, addr, acc, curr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Compact indicates an expected call of Compact.
+func (mr *MockBalanceMockRecorder) Compact(ctx, addr, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compact", reflect.TypeOf((*MockBalance)(nil).Compact), ctx, addr, acc, curr)
+}
+
+// Credit mocks base method.
+func (m *MockBalance) Credit(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, val *big.Int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Credit", ctx, addr, acc, curr, val)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Credit indicates an expected call of Credit.
+func (mr *MockBalanceMockRecorder) Credit(ctx, addr, acc, curr, val interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Credit", reflect.TypeOf((*MockBalance)(nil).Credit), ctx, addr, acc, curr, val)
+}
+
+// Holders mocks base method.
+func (m *MockBalance) Holders(ctx context.Context, acc model.Account, curr model.Currency, bookmark model.Address, limit int) ([]model.Address, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Holders", ctx, acc, curr, bookmark, limit)
+	ret0, _ := ret[0].([]model.Address)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Holders indicates an expected call of Holders.
+func (mr *MockBalanceMockRecorder) Holders(ctx, acc, curr, bookmark, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Holders", reflect.TypeOf((*MockBalance)(nil).Holders), ctx, acc, curr, bookmark, limit)
+}
+
 // List mocks base method.
 func (m *MockBalance) List(ctx context.Context, addr model.Address, acc model.Account) (map[model.Currency]*big.Int, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +124,66 @@ func (mr *MockBalanceMockRecorder) Load(ctx, addr, acc, curr interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockBalance)(nil).Load), ctx, addr, acc, curr)
 }
 
+// LoadAt mocks base method.
+func (m *MockBalance) LoadAt(ctx context.Context, seq uint64, addr model.Address, acc model.Account, curr model.Currency) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadAt", ctx, seq, addr, acc, curr)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadAt indicates an expected call of LoadAt.
+func (mr *MockBalanceMockRecorder) LoadAt(ctx, seq, addr, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadAt", reflect.TypeOf((*MockBalance)(nil).LoadAt), ctx, seq, addr, acc, curr)
+}
+
+// LoadTouched mocks base method.
+func (m *MockBalance) LoadTouched(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency) (*big.Int, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTouched", ctx, addr, acc, curr)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// LoadTouched indicates an expected call of LoadTouched.
+func (mr *MockBalanceMockRecorder) LoadTouched(ctx, addr, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTouched", reflect.TypeOf((*MockBalance)(nil).LoadTouched), ctx, addr, acc, curr)
+}
+
+// Portfolio mocks base method.
+func (m *MockBalance) Portfolio(ctx context.Context, addr model.Address) (map[model.Account]map[model.Currency]*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Portfolio", ctx, addr)
+	ret0, _ := ret[0].(map[model.Account]map[model.Currency]*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Portfolio indicates an expected call of Portfolio.
+func (mr *MockBalanceMockRecorder) Portfolio(ctx, addr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Portfolio", reflect.TypeOf((*MockBalance)(nil).Portfolio), ctx, addr)
+}
+
+// Register mocks base method.
+func (m *MockBalance) Register(ctx context.Context, addrs []model.Address, acc model.Account, curr model.Currency) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Register", ctx, addrs, acc, curr)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Register indicates an expected call of Register.
+func (mr *MockBalanceMockRecorder) Register(ctx, addrs, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockBalance)(nil).Register), ctx, addrs, acc, curr)
+}
+
 // Save mocks base method.
 func (m *MockBalance) Save(ctx context.Context, addr model.Address, acc model.Account, curr model.Currency, val *big.Int) error {
 	m.ctrl.T.Helper()
@@ -79,3 +197,18 @@ func (mr *MockBalanceMockRecorder) Save(ctx, addr, acc, curr, val interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockBalance)(nil).Save), ctx, addr, acc, curr, val)
 }
+
+// Supply mocks base method.
+func (m *MockBalance) Supply(ctx context.Context, acc model.Account, curr model.Currency) (*big.Int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Supply", ctx, acc, curr)
+	ret0, _ := ret[0].(*big.Int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Supply indicates an expected call of Supply.
+func (mr *MockBalanceMockRecorder) Supply(ctx, acc, curr interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Supply", reflect.TypeOf((*MockBalance)(nil).Supply), ctx, acc, curr)
+}
@@ -35,6 +35,95 @@ func (m *MockNotification) EXPECT() *MockNotificationMockRecorder {
 	return m.recorder
 }
 
+// History mocks base method.
+func (m *MockNotification) History(ctx context.Context, q model.HistoryQuery) (*model.HistoryPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, q)
+	ret0, _ := ret[0].(*model.HistoryPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockNotificationMockRecorder) History(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockNotification)(nil).History), ctx, q)
+}
+
+// Iter mocks base method.
+func (m *MockNotification) Iter(ctx context.Context, typ string, cb func(*model.RawNotification) bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Iter", ctx, typ, cb)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Iter indicates an expected call of Iter.
+func (mr *MockNotificationMockRecorder) Iter(ctx, typ, cb interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Iter", reflect.TypeOf((*MockNotification)(nil).Iter), ctx, typ, cb)
+}
+
+// Load mocks base method.
+func (m *MockNotification) Load(ctx context.Context, typ, id string) (*model.RawNotification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Load", ctx, typ, id)
+	ret0, _ := ret[0].(*model.RawNotification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Load indicates an expected call of Load.
+func (mr *MockNotificationMockRecorder) Load(ctx, typ, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Load", reflect.TypeOf((*MockNotification)(nil).Load), ctx, typ, id)
+}
+
+// LoadBalancesUpdate mocks base method.
+func (m *MockNotification) LoadBalancesUpdate(ctx context.Context, typ, id string) (*model.Notification[model.BalancesUpdate], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadBalancesUpdate", ctx, typ, id)
+	ret0, _ := ret[0].(*model.Notification[model.BalancesUpdate])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadBalancesUpdate indicates an expected call of LoadBalancesUpdate.
+func (mr *MockNotificationMockRecorder) LoadBalancesUpdate(ctx, typ, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadBalancesUpdate", reflect.TypeOf((*MockNotification)(nil).LoadBalancesUpdate), ctx, typ, id)
+}
+
+// LoadReversal mocks base method.
+func (m *MockNotification) LoadReversal(ctx context.Context, id string) (*model.Notification[model.Reversal], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadReversal", ctx, id)
+	ret0, _ := ret[0].(*model.Notification[model.Reversal])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadReversal indicates an expected call of LoadReversal.
+func (mr *MockNotificationMockRecorder) LoadReversal(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadReversal", reflect.TypeOf((*MockNotification)(nil).LoadReversal), ctx, id)
+}
+
+// Query mocks base method.
+func (m *MockNotification) Query(ctx context.Context, q model.NotificationQuery) (*model.NotificationPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Query", ctx, q)
+	ret0, _ := ret[0].(*model.NotificationPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Query indicates an expected call of Query.
+func (mr *MockNotificationMockRecorder) Query(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Query", reflect.TypeOf((*MockNotification)(nil).Query), ctx, q)
+}
+
 // SaveBalancesUpdate mocks base method.
 func (m *MockNotification) SaveBalancesUpdate(ctx context.Context, bu model.Notification[model.BalancesUpdate]) error {
 	m.ctrl.T.Helper()
@@ -48,3 +137,45 @@ func (mr *MockNotificationMockRecorder) SaveBalancesUpdate(ctx, bu interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBalancesUpdate", reflect.TypeOf((*MockNotification)(nil).SaveBalancesUpdate), ctx, bu)
 }
+
+// SaveForcedTransfer mocks base method.
+func (m *MockNotification) SaveForcedTransfer(ctx context.Context, ft model.Notification[model.ForcedTransfer]) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveForcedTransfer", ctx, ft)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveForcedTransfer indicates an expected call of SaveForcedTransfer.
+func (mr *MockNotificationMockRecorder) SaveForcedTransfer(ctx, ft interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveForcedTransfer", reflect.TypeOf((*MockNotification)(nil).SaveForcedTransfer), ctx, ft)
+}
+
+// SaveRaw mocks base method.
+func (m *MockNotification) SaveRaw(ctx context.Context, raw *model.RawNotification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRaw", ctx, raw)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRaw indicates an expected call of SaveRaw.
+func (mr *MockNotificationMockRecorder) SaveRaw(ctx, raw interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRaw", reflect.TypeOf((*MockNotification)(nil).SaveRaw), ctx, raw)
+}
+
+// SaveReversal mocks base method.
+func (m *MockNotification) SaveReversal(ctx context.Context, r model.Notification[model.Reversal]) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReversal", ctx, r)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveReversal indicates an expected call of SaveReversal.
+func (mr *MockNotificationMockRecorder) SaveReversal(ctx, r interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReversal", reflect.TypeOf((*MockNotification)(nil).SaveReversal), ctx, r)
+}
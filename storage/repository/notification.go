@@ -12,4 +12,31 @@ import (
 type Notification interface {
 	// SaveBalancesUpdate stores notification record to the notification database.
 	SaveBalancesUpdate(ctx context.Context, bu model.Notification[model.BalancesUpdate]) error
+	// SaveForcedTransfer stores forced transfer record to the notification database.
+	SaveForcedTransfer(ctx context.Context, ft model.Notification[model.ForcedTransfer]) error
+	// SaveReversal stores reversal record to the notification database.
+	SaveReversal(ctx context.Context, r model.Notification[model.Reversal]) error
+	// LoadBalancesUpdate retrieves notification record of given type and identifier from the
+	// notification database. If no record is found, nil is returned.
+	LoadBalancesUpdate(ctx context.Context, typ, id string) (*model.Notification[model.BalancesUpdate], error)
+	// LoadReversal retrieves reversal record with given identifier from the notification
+	// database. If no record is found, nil is returned.
+	LoadReversal(ctx context.Context, id string) (*model.Notification[model.Reversal], error)
+	// SaveRaw stores notification record of a type registered by model.RegisterNotification.
+	// The body is decoded and validated according to the registered type. If it
+	// implements model.Addresser, the record is indexed by its addresses, and if it
+	// implements model.BalanceUpdater, the updates are added to the address history.
+	SaveRaw(ctx context.Context, raw *model.RawNotification) error
+	// Load retrieves notification record of given type and identifier with the undecoded
+	// body. If no record is found, nil is returned.
+	Load(ctx context.Context, typ, id string) (*model.RawNotification, error)
+	// Iter iterates over all notification records of given type in the order of their
+	// identifiers. Iteration stops if cb returns true.
+	Iter(ctx context.Context, typ string, cb func(raw *model.RawNotification) (stop bool)) error
+	// Query retrieves a page of notification records matching the query. Queries by
+	// address use the address index, queries by time range use the time index, and
+	// queries only by type iterate over the records of the type.
+	Query(ctx context.Context, q model.NotificationQuery) (*model.NotificationPage, error)
+	// History retrieves a page of the balance updates of the address, newest first.
+	History(ctx context.Context, q model.HistoryQuery) (*model.HistoryPage, error)
 }
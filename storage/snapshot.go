@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/anoideaopen/token/keyvalue"
+	"github.com/anoideaopen/token/model"
+)
+
+// ErrSnapshotDatabase represents a generic error related to the database operations.
+var ErrSnapshotDatabase = errors.New("snapshot database error")
+
+// Keys of the snapshot records.
+const (
+	snapshotPrefix      = "snapshot"
+	snapshotCurrentKey  = "snapshotcurrent"
+	snapshotValuePrefix = "snapshotvalue"
+	snapshotLastPrefix  = "snapshotlast"
+
+	// snapshotSeqLayout formats sequence numbers in the value keys, so that their
+	// lexical order matches the numeric one.
+	snapshotSeqLayout = "%020d"
+)
+
+// Snapshot is a structure which encapsulates the keyvalue.DB to interact with
+// balance snapshots in database. Besides the snapshots themselves it stores the
+// values of the balances captured before their first change after a snapshot and,
+// for every balance, the sequence number of the last capture.
+//
+// A snapshot reflects the balances as of the moment it is taken, also when they are
+// changed later by the same transaction. With the corporate actions set, the captured
+// values are stored with the index of the actions and adjusted by the actions
+// performed since then when they are read, so they are expressed in the current units
+// of the currency like the balances, which have not changed since the snapshot.
+//
+//go:generate ifacemaker -f snapshot.go -o repository/snapshot.go -i Snapshot -s Snapshot -p repository -y "Repository describes methods, implemented by the storage package."
+//go:generate mockgen -package mock -source repository/snapshot.go -destination repository/mock/mock_snapshot.go
+type Snapshot struct {
+	Object
+
+	// Actions is an optional storage of corporate actions.
+	Actions *CorporateAction
+}
+
+// Load retrieves the snapshot by its identifier. If no snapshot is found, nil is
+// returned.
+func (s *Snapshot) Load(ctx context.Context, id string) (*model.Snapshot, error) {
+	return s.load(ctx, model.ObjectQuery(keyvalue.Join(snapshotPrefix, id)))
+}
+
+// Current retrieves the last taken snapshot. If no snapshot was taken, nil is returned.
+func (s *Snapshot) Current(ctx context.Context) (*model.Snapshot, error) {
+	return s.load(ctx, model.ObjectQuery(snapshotCurrentKey))
+}
+
+// Take stores the snapshot with the next sequence number and makes it the current one.
+func (s *Snapshot) Take(ctx context.Context, snap *model.Snapshot) error {
+	current, err := s.Current(ctx)
+	if err != nil {
+		return err
+	}
+
+	snap.Seq = 1
+	if current != nil {
+		snap.Seq = current.Seq + 1
+	}
+
+	for _, key := range []string{keyvalue.Join(snapshotPrefix, snap.ID), snapshotCurrentKey} {
+		if err := s.Object.Save(ctx, model.ObjectQuery(key), snap); err != nil {
+			return fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// ValueAt retrieves the value of the balance as of the snapshot with given sequence
+// number, adjusted by the corporate actions performed since it was captured. If the
+// balance has not changed since the snapshot, nil is returned and the current value
+// of the balance applies.
+func (s *Snapshot) ValueAt(
+	ctx context.Context,
+	seq uint64,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+) (*big.Int, error) {
+	prefix := s.key(snapshotValuePrefix, addr, acc, curr)
+
+	iter, err := s.Object.DB.Iter(ctx, keyvalue.Prefix(prefix))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+	}
+	defer iter.Close()
+
+	// the first value captured at or after the snapshot is the value as of it
+	for iter.HasNext() {
+		k, v, err := iter.Next()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+		}
+
+		rest, ok := strings.CutPrefix(string(k), prefix+keyvalue.KeySeparator)
+		if !ok || strings.Contains(rest, keyvalue.KeySeparator) {
+			continue
+		}
+
+		captured, err := strconv.ParseUint(rest, 10, 64) //nolint:gomnd
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+		}
+
+		if captured < seq {
+			continue
+		}
+
+		val, err := s.Actions.decode(ctx, curr, v)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+		}
+
+		return val, nil
+	}
+
+	return nil, nil
+}
+
+// capture stores the value of the balance before its first change after the current
+// snapshot. The value is loaded only if it is not captured yet.
+func (s *Snapshot) capture(
+	ctx context.Context,
+	addr model.Address,
+	acc model.Account,
+	curr model.Currency,
+	load func() (*big.Int, error),
+) error {
+	current, err := s.Current(ctx)
+	if err != nil || current == nil {
+		return err
+	}
+
+	// later changes after the capture must not overwrite the captured value
+	lastKey := keyvalue.Key(s.key(snapshotLastPrefix, addr, acc, curr))
+
+	raw, err := s.Object.DB.Get(ctx, lastKey)
+	if err != nil && !errors.Is(err, keyvalue.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+	}
+
+	if err == nil {
+		last, err := strconv.ParseUint(string(raw), 10, 64) //nolint:gomnd
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+		}
+
+		if last >= current.Seq {
+			return nil
+		}
+	}
+
+	val, err := load()
+	if err != nil {
+		return err
+	}
+
+	captured, err := s.Actions.encode(ctx, curr, val)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+	}
+
+	seq := fmt.Sprintf(snapshotSeqLayout, current.Seq)
+	if err := s.Object.DB.Set(ctx, keyvalue.Key(keyvalue.Join(s.key(snapshotValuePrefix, addr, acc, curr), seq)), captured); err != nil {
+		return fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+	}
+
+	if err := s.Object.DB.Set(ctx, lastKey, keyvalue.Value(strconv.FormatUint(current.Seq, 10))); err != nil { //nolint:gomnd
+		return fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+	}
+
+	return nil
+}
+
+func (s *Snapshot) load(ctx context.Context, q model.ObjectQuery) (*model.Snapshot, error) {
+	snap := new(model.Snapshot)
+	if err := s.Object.Load(ctx, q, snap); err != nil {
+		if errors.Is(err, ErrObjectNotFound) {
+			return nil, nil //nolint:nilnil
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotDatabase, err.Error())
+	}
+
+	return snap, nil
+}
+
+// key creates a key of the balance record with given prefix.
+// example: "snapshotvalue/address/2b/currency"
+func (s *Snapshot) key(prefix string, addr model.Address, acc model.Account, curr model.Currency) string {
+	return keyvalue.Join(prefix, string(addr), encodeAccount(acc), string(curr))
+}
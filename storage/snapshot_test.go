@@ -0,0 +1,73 @@
+package storage
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/anoideaopen/token/keyvalue/inmem"
+	"github.com/anoideaopen/token/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_LoadAt(t *testing.T) {
+	for _, accumulator := range []bool{false, true} {
+		db := new(inmem.KeyValueDB)
+		snapshots := &Snapshot{Object: Object{DB: db}}
+		b := &Balance{DB: db, Accumulator: accumulator, Snapshots: snapshots}
+
+		var (
+			tt = model.AccountToken
+			a  = model.Address("0x123")
+			c  = model.Currency("ETH")
+		)
+
+		tx := func(id string) context.Context {
+			return model.ContextWithTransaction(context.Background(), model.Transaction{ID: id})
+		}
+
+		require.NoError(t, b.Save(tx("tx1"), a, tt, c, big.NewInt(100)))
+		require.NoError(t, b.Save(tx("tx1"), a, tt, "BTC", big.NewInt(7)))
+
+		s1 := &model.Snapshot{ID: "s1"}
+		require.NoError(t, snapshots.Take(tx("tx2"), s1))
+		assert.Equal(t, uint64(1), s1.Seq)
+
+		// several changes in one transaction capture the value only once
+		require.NoError(t, b.Credit(tx("tx3"), a, tt, c, big.NewInt(10)))
+		require.NoError(t, b.Credit(tx("tx3"), a, tt, c, big.NewInt(5)))
+
+		s2 := &model.Snapshot{ID: "s2"}
+		require.NoError(t, snapshots.Take(tx("tx4"), s2))
+		assert.Equal(t, uint64(2), s2.Seq)
+
+		require.NoError(t, b.Save(tx("tx5"), a, tt, c, big.NewInt(50)))
+
+		loaded, err := snapshots.Load(context.Background(), "s2")
+		require.NoError(t, err)
+		assert.Equal(t, s2.Seq, loaded.Seq)
+
+		for seq, expected := range map[uint64]int64{1: 100, 2: 115, 3: 50} {
+			val, err := b.LoadAt(context.Background(), seq, a, tt, c)
+			require.NoError(t, err)
+			assert.Equal(t, 0, big.NewInt(expected).Cmp(val), "seq %d: %s", seq, val)
+		}
+
+		// the balance untouched after the snapshot is the current one
+		val, err := b.LoadAt(context.Background(), s1.Seq, a, tt, "BTC")
+		require.NoError(t, err)
+		assert.Equal(t, 0, big.NewInt(7).Cmp(val))
+
+		// the balance created after the snapshot was zero as of it
+		require.NoError(t, b.Credit(tx("tx6"), a, tt, "USD", big.NewInt(3)))
+
+		val, err = b.LoadAt(context.Background(), s2.Seq, a, tt, "USD")
+		require.NoError(t, err)
+		assert.Equal(t, 0, val.Sign())
+
+		missing, err := snapshots.Load(context.Background(), "unknown")
+		require.NoError(t, err)
+		assert.Nil(t, missing)
+	}
+}